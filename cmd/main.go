@@ -2,30 +2,56 @@ package main
 
 import (
 	"app/internal/application"
+	"app/internal/config"
+	"app/platform/logging"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
+	"os"
 )
 
 func main() {
 	// env
-	// ...
+	// - config: defaults < config file < environment variables < flags
+	cfg, printConfig, err := config.Load(os.Args[1:], os.LookupEnv)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		// the configuration is not known yet, so the error is not a record of the logger
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if printConfig {
+		err = cfg.Print(os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	// - logger: JSON records in the standard output
+	logger := logging.New(os.Stdout, cfg.LogLevel)
+	slog.SetDefault(logger)
 
 	// app
-	// - config
-	cfg := &application.ConfigApplicationDefault{
-		ServerAddress: ":8080",
-		LoaderFilePath: "docs/db/vehicles_100.json",
-	}
-	app := application.NewApplicationDefault(cfg)
+	app := application.NewApplicationDefault(cfg.Application(logger))
 	// - setup
-	err := app.SetUp()
+	err = app.SetUp()
 	if err != nil {
-		fmt.Println(err)
-		return
+		logger.Error("set up failed", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
 	// - run
 	err = app.Run()
 	if err != nil {
-		fmt.Println(err)
-		return
+		logger.Error("run failed", slog.String("error", err.Error()))
+	}
+	// - tear down
+	err = app.TearDown()
+	if err != nil {
+		logger.Error("tear down failed", slog.String("error", err.Error()))
+		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,577 @@
+// Command mockgen writes the mock of an interface, to be used by the tests of other packages.
+//
+// Usage:
+//
+//	mockgen -source <dir> -interface <name> -type <name> [-package <name>] [-output <file>]
+//
+// It is meant to be run by go generate from the package of the interface implementation, writing the mock in a
+// package only imported by tests, since the mock imports testing, e.g.
+//
+//	//go:generate go run app/cmd/mockgen -source .. -interface RepositoryVehicle -type VehicleMapMock -package repositorytest -output repositorytest/vehicle_map_mock.go
+//
+// Each method of the mock records its arguments and the order of the calls, and calls the function configured
+// in the field with the name of the method and the suffix Func. A call without its function fails the test.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is a function that writes the mock given by the arguments and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	// env
+	fs := flag.NewFlagSet("mockgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	source := fs.String("source", ".", "directory of the package with the interface")
+	iface := fs.String("interface", "", "name of the interface")
+	typ := fs.String("type", "", "name of the mock")
+	pkg := fs.String("package", os.Getenv("GOPACKAGE"), "package of the mock, by default the one run by go generate")
+	output := fs.String("output", "", "file of the mock, by default the standard output")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *iface == "" || *typ == "" || *pkg == "" || fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "usage: mockgen -source <dir> -interface <name> -type <name> [-package <name>] [-output <file>]")
+		fs.PrintDefaults()
+		return 2
+	}
+
+	// process
+	dest := "."
+	if *output != "" {
+		dest = filepath.Dir(*output)
+	}
+	b, err := generate(*source, *iface, *typ, *pkg, dest)
+	if err != nil {
+		fmt.Fprintf(stderr, "mockgen: %s\n", err)
+		return 1
+	}
+
+	// output
+	if *output == "" {
+		_, err = stdout.Write(b)
+	} else {
+		err = os.WriteFile(*output, b, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "mockgen: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// sourcePackage is a struct that represents the parsed package with the interface
+type sourcePackage struct {
+	// name is the name of the package
+	name string
+	// path is the import path of the package
+	path string
+	// types are the names of the types declared by the package
+	types map[string]bool
+	// interfaces are the interfaces declared by the package, with the file that declares them
+	interfaces map[string]sourceInterface
+}
+
+// sourceInterface is a struct that represents an interface with its file
+type sourceInterface struct {
+	// typ is the interface
+	typ *ast.InterfaceType
+	// file is the file that declares the interface, its imports qualify the types of the methods
+	file *ast.File
+}
+
+// parsePackage is a function that parses the go files of the directory, except the tests
+func parsePackage(dir string) (p sourcePackage, err error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return
+	}
+
+	p.types = make(map[string]bool)
+	p.interfaces = make(map[string]sourceInterface)
+	fset := token.NewFileSet()
+	for _, fp := range paths {
+		if strings.HasSuffix(fp, "_test.go") {
+			continue
+		}
+		var f *ast.File
+		f, err = parser.ParseFile(fset, fp, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return
+		}
+		p.name = f.Name.Name
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				p.types[ts.Name.Name] = true
+				if it, ok := ts.Type.(*ast.InterfaceType); ok {
+					p.interfaces[ts.Name.Name] = sourceInterface{typ: it, file: f}
+				}
+			}
+		}
+	}
+	if p.name == "" {
+		err = fmt.Errorf("no go files in %s", dir)
+		return
+	}
+
+	p.path, err = importPath(dir)
+	return
+}
+
+// importPath is a function that returns the import path of the directory, by the module of the nearest go.mod
+func importPath(dir string) (p string, err error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+
+	for root := abs; ; root = filepath.Dir(root) {
+		f, e := os.Open(filepath.Join(root, "go.mod"))
+		if e == nil {
+			var module string
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				if line, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "module "); ok {
+					module = strings.Trim(strings.TrimSpace(line), `"`)
+					break
+				}
+			}
+			f.Close()
+			if module == "" {
+				err = fmt.Errorf("no module in %s", filepath.Join(root, "go.mod"))
+				return
+			}
+			rel, _ := filepath.Rel(root, abs)
+			p = path.Join(module, filepath.ToSlash(rel))
+			return
+		}
+		if filepath.Dir(root) == root {
+			err = fmt.Errorf("no go.mod for %s", dir)
+			return
+		}
+	}
+}
+
+// mockParam is a struct that represents a parameter or a result of a method
+type mockParam struct {
+	// Name is the name of the parameter
+	Name string
+	// Field is the name of the field of the call that records the parameter
+	Field string
+	// Type is the type of the parameter, qualified for the package of the mock
+	Type string
+	// FieldType is the type of the field of the call, variadic parameters are slices
+	FieldType string
+	// Variadic is a flag that indicates that the parameter is variadic
+	Variadic bool
+}
+
+// mockMethod is a struct that represents a method of the mock
+type mockMethod struct {
+	// Name is the name of the method
+	Name string
+	// Params are the parameters of the method
+	Params []mockParam
+	// Results are the results of the method, always named so that a call without function returns the zero values
+	Results []mockParam
+}
+
+// Signature is a method that returns the parameters and results of the method, as declared in a function type
+func (m mockMethod) Signature() string {
+	params := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		params[i] = p.Name + " " + p.Type
+	}
+	results := make([]string, len(m.Results))
+	for i, r := range m.Results {
+		results[i] = r.Name + " " + r.Type
+	}
+	if len(results) == 0 {
+		return "(" + strings.Join(params, ", ") + ")"
+	}
+	return "(" + strings.Join(params, ", ") + ") (" + strings.Join(results, ", ") + ")"
+}
+
+// Args is a method that returns the arguments of the call to the function of the method
+func (m mockMethod) Args() string {
+	args := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		args[i] = p.Name
+		if p.Variadic {
+			args[i] += "..."
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// reservedNames are the names used by the body of the methods, parameters can not take them
+var reservedNames = map[string]bool{"mk": true, "fn": true}
+
+// generator is a struct that collects the methods and imports of a mock
+type generator struct {
+	// pkg is the package with the interface
+	pkg sourcePackage
+	// qualified is a flag that indicates that the types of pkg are written with the name of the package
+	qualified bool
+	// imports are the import paths used by the mock
+	imports map[string]bool
+	// methods are the methods of the interface, with the embedded interfaces expanded
+	methods []mockMethod
+	// seen are the names of the methods already collected
+	seen map[string]bool
+}
+
+// generate is a function that returns the formatted source of the mock of the interface
+// - dest is the directory of the mock, the types of the source package are qualified unless it is the same directory
+func generate(source, iface, typ, pkg, dest string) (b []byte, err error) {
+	sp, err := parsePackage(source)
+	if err != nil {
+		return
+	}
+	destPath, err := importPath(dest)
+	if err != nil {
+		return
+	}
+
+	g := &generator{
+		pkg:       sp,
+		qualified: destPath != sp.path,
+		imports:   map[string]bool{"slices": true, "sync": true, "testing": true},
+		seen:      make(map[string]bool),
+	}
+	if err = g.collect(iface); err != nil {
+		return
+	}
+
+	imports := make([]string, 0, len(g.imports))
+	for p := range g.imports {
+		imports = append(imports, p)
+	}
+	sort.Strings(imports)
+	iname := iface
+	if g.qualified {
+		iname = sp.name + "." + iface
+	}
+
+	var buf bytes.Buffer
+	err = mockTemplate.Execute(&buf, map[string]any{
+		"Package":   pkg,
+		"Imports":   imports,
+		"Interface": iname,
+		"Type":      typ,
+		"Methods":   g.methods,
+	})
+	if err != nil {
+		return
+	}
+	b, err = format.Source(buf.Bytes())
+	return
+}
+
+// collect is a method that adds the methods of the interface, in order of declaration
+func (g *generator) collect(name string) (err error) {
+	si, ok := g.pkg.interfaces[name]
+	if !ok {
+		err = fmt.Errorf("interface %s not found in package %s", name, g.pkg.path)
+		return
+	}
+
+	for _, field := range si.typ.Methods.List {
+		// embedded interface
+		if len(field.Names) == 0 {
+			id, ok := field.Type.(*ast.Ident)
+			if !ok {
+				err = fmt.Errorf("%s: embedded interface %s is not supported", name, types.ExprString(field.Type))
+				return
+			}
+			if err = g.collect(id.Name); err != nil {
+				return
+			}
+			continue
+		}
+
+		// method
+		ft := field.Type.(*ast.FuncType)
+		for _, n := range field.Names {
+			if g.seen[n.Name] {
+				continue
+			}
+			g.seen[n.Name] = true
+			m := mockMethod{Name: n.Name}
+			if m.Params, err = g.params(si.file, ft.Params, "p"); err != nil {
+				return
+			}
+			if m.Results, err = g.params(si.file, ft.Results, "r"); err != nil {
+				return
+			}
+			g.methods = append(g.methods, m)
+		}
+	}
+	return
+}
+
+// params is a method that returns the parameters of a list, unnamed ones are named with the prefix and their position
+func (g *generator) params(f *ast.File, fl *ast.FieldList, prefix string) (ps []mockParam, err error) {
+	if fl == nil {
+		return
+	}
+
+	for _, field := range fl.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{nil}
+		}
+		for _, n := range names {
+			p := mockParam{Name: prefix + strconv.Itoa(len(ps))}
+			if n != nil && n.Name != "_" {
+				p.Name = n.Name
+			}
+			if reservedNames[p.Name] {
+				err = fmt.Errorf("parameter name %s is reserved", p.Name)
+				return
+			}
+			p.Field = exportName(p.Name)
+
+			t := field.Type
+			if el, ok := t.(*ast.Ellipsis); ok {
+				p.Variadic = true
+				t = el.Elt
+			}
+			if t, err = g.qualify(f, t); err != nil {
+				return
+			}
+			p.Type, p.FieldType = types.ExprString(t), types.ExprString(t)
+			if p.Variadic {
+				p.Type, p.FieldType = "..."+p.Type, "[]"+p.Type
+			}
+			ps = append(ps, p)
+		}
+	}
+	return
+}
+
+// qualify is a method that returns a copy of the type expression valid in the package of the mock
+// - the types of the source package take its name, the packages of other selectors are imported
+func (g *generator) qualify(f *ast.File, e ast.Expr) (q ast.Expr, err error) {
+	switch t := e.(type) {
+	case *ast.Ident:
+		q = t
+		if g.pkg.types[t.Name] && g.qualified {
+			g.imports[g.pkg.path] = true
+			q = &ast.SelectorExpr{X: ast.NewIdent(g.pkg.name), Sel: ast.NewIdent(t.Name)}
+		}
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		if !ok {
+			err = fmt.Errorf("type %s is not supported", types.ExprString(e))
+			return
+		}
+		var p string
+		if p, err = fileImport(f, x.Name); err != nil {
+			return
+		}
+		g.imports[p] = true
+		q = t
+	case *ast.StarExpr:
+		var x ast.Expr
+		x, err = g.qualify(f, t.X)
+		q = &ast.StarExpr{X: x}
+	case *ast.ArrayType:
+		var elt ast.Expr
+		elt, err = g.qualify(f, t.Elt)
+		q = &ast.ArrayType{Len: t.Len, Elt: elt}
+	case *ast.MapType:
+		var key, value ast.Expr
+		if key, err = g.qualify(f, t.Key); err != nil {
+			return
+		}
+		value, err = g.qualify(f, t.Value)
+		q = &ast.MapType{Key: key, Value: value}
+	case *ast.ChanType:
+		var value ast.Expr
+		value, err = g.qualify(f, t.Value)
+		q = &ast.ChanType{Dir: t.Dir, Value: value}
+	case *ast.FuncType:
+		ft := &ast.FuncType{}
+		if ft.Params, err = g.qualifyFields(f, t.Params); err != nil {
+			return
+		}
+		ft.Results, err = g.qualifyFields(f, t.Results)
+		q = ft
+	case *ast.Ellipsis:
+		var elt ast.Expr
+		elt, err = g.qualify(f, t.Elt)
+		q = &ast.Ellipsis{Elt: elt}
+	case *ast.InterfaceType:
+		if len(t.Methods.List) > 0 {
+			err = fmt.Errorf("type %s is not supported", types.ExprString(e))
+			return
+		}
+		q = t
+	default:
+		err = fmt.Errorf("type %s is not supported", types.ExprString(e))
+	}
+	return
+}
+
+// qualifyFields is a method that returns a copy of the field list with its types qualified
+func (g *generator) qualifyFields(f *ast.File, fl *ast.FieldList) (q *ast.FieldList, err error) {
+	if fl == nil {
+		return
+	}
+
+	q = &ast.FieldList{}
+	for _, field := range fl.List {
+		var t ast.Expr
+		if t, err = g.qualify(f, field.Type); err != nil {
+			return
+		}
+		q.List = append(q.List, &ast.Field{Names: field.Names, Type: t})
+	}
+	return
+}
+
+// fileImport is a function that returns the import path of the package with the name in the file
+func fileImport(f *ast.File, name string) (p string, err error) {
+	for _, spec := range f.Imports {
+		ip, _ := strconv.Unquote(spec.Path.Value)
+		n := path.Base(ip)
+		if spec.Name != nil {
+			n = spec.Name.Name
+		}
+		if n == name {
+			p = ip
+			return
+		}
+	}
+	err = errors.New("package " + name + " is not imported")
+	return
+}
+
+// exportName is a function that returns the name with its first letter in upper case
+func exportName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// unexportName is a function that returns the name with its first letter in lower case
+func unexportName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// mockTemplate is the template of the source of a mock
+var mockTemplate = template.Must(template.New("mock").Funcs(template.FuncMap{"unexport": unexportName}).Parse(`// Code generated by mockgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+// New{{.Type}} is a function that returns a new instance of {{.Type}}
+// - a call to a method without its function configured fails the test t
+func New{{.Type}}(t testing.TB) *{{.Type}} {
+	return &{{.Type}}{t: t}
+}
+
+// {{.Type}} is a struct that implements the {{.Interface}} interface for tests
+// - each method records its arguments and calls the function of the field with its name and the suffix Func
+type {{.Type}} struct {
+{{- range .Methods}}
+	// {{.Name}}Func is the function called by {{.Name}}
+	{{.Name}}Func func{{.Signature}}
+{{- end}}
+
+	// t is the test that uses the mock
+	t testing.TB
+	// mu is the mutex that guards the calls
+	mu sync.Mutex
+	// calls are the names of the methods called, in order
+	calls []string
+{{- range .Methods}}
+	// {{unexport .Name}}Calls are the arguments of the calls to {{.Name}}, in order
+	{{unexport .Name}}Calls []{{$.Type}}{{.Name}}Call
+{{- end}}
+}
+
+// Calls is a method that returns the names of the methods called, in order
+func (mk *{{.Type}}) Calls() []string {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.calls)
+}
+
+// AssertCalls is a method that checks that the methods called are the methods given, in the same order
+func (mk *{{.Type}}) AssertCalls(methods ...string) bool {
+	mk.t.Helper()
+	calls := mk.Calls()
+	if !slices.Equal(calls, methods) {
+		mk.t.Errorf("{{.Type}}: calls %v, expected %v", calls, methods)
+		return false
+	}
+	return true
+}
+{{range .Methods}}
+// {{$.Type}}{{.Name}}Call is a struct that represents the arguments of a call to {{.Name}}
+type {{$.Type}}{{.Name}}Call struct {
+{{- range .Params}}
+	// {{.Field}} is the argument {{.Name}}
+	{{.Field}} {{.FieldType}}
+{{- end}}
+}
+
+// {{.Name}} is a method that records the call and returns the results of {{.Name}}Func
+func (mk *{{$.Type}}) {{.Name}}{{.Signature}} {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "{{.Name}}")
+	mk.{{unexport .Name}}Calls = append(mk.{{unexport .Name}}Calls, {{$.Type}}{{.Name}}Call{ {{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Field}}: {{$p.Name}}{{end -}} })
+	fn := mk.{{.Name}}Func
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("{{$.Type}}: unexpected call to {{.Name}}, {{.Name}}Func is not configured")
+		return
+	}
+	{{if .Results}}return {{end}}fn({{.Args}})
+}
+
+// {{.Name}}Calls is a method that returns the arguments of the calls to {{.Name}}, in order
+func (mk *{{$.Type}}) {{.Name}}Calls() []{{$.Type}}{{.Name}}Call {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.{{unexport .Name}}Calls)
+}
+{{end}}`))
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"app/internal"
+	"app/internal/repository/repositorytest"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recorderT is a struct that records the errors of a test instead of failing it
+type recorderT struct {
+	testing.TB
+	// errors are the errors reported
+	errors []string
+}
+
+// Helper is a method that does nothing
+func (r *recorderT) Helper() {}
+
+// Errorf is a method that records the error
+func (r *recorderT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Mocks are up to date", func(t *testing.T) {
+		// Given
+		cases := []struct {
+			dir, iface, typ, pkg, file string
+		}{
+			{"../../internal/repository/repositorytest", "RepositoryVehicle", "VehicleMapMock", "repositorytest", "vehicle_map_mock.go"},
+			{"../../internal/service/servicetest", "ServiceVehicle", "VehicleDefaultMock", "servicetest", "vehicle_default_mock.go"},
+		}
+
+		for _, c := range cases {
+			// When
+			b, err := generate("../../internal", c.iface, c.typ, c.pkg, c.dir)
+			// Then
+			require.NoError(t, err)
+			expected, err := os.ReadFile(filepath.Join(c.dir, c.file))
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(b), "run go generate ./... to update %s", c.file)
+		}
+	})
+
+	t.Run("Embedded interfaces, unnamed and variadic parameters", func(t *testing.T) {
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-source", "testdata/source", "-interface", "Logger", "-type", "LoggerMock", "-package", "mocks"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 0, code, stderr.String())
+		out := stdout.String()
+		require.Contains(t, out, "\t\"app/cmd/mockgen/testdata/source\"\n\t\"io\"\n")
+		require.Contains(t, out, "func (mk *LoggerMock) Close() (r0 error) {")
+		require.Contains(t, out, "func (mk *LoggerMock) Log(p0 string, p1 ...any) {")
+		require.Contains(t, out, "\tfn(p0, p1...)\n")
+		require.Contains(t, out, "\tP1 []any\n")
+		require.Contains(t, out, "func (mk *LoggerMock) Output() (w io.Writer) {")
+		require.Contains(t, out, "func (mk *LoggerMock) Level(levels map[string]source.Level) (l source.Level, err error) {")
+	})
+
+	t.Run("Interface not found", func(t *testing.T) {
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-source", "testdata/source", "-interface", "Writer", "-type", "WriterMock", "-package", "mocks"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 1, code)
+		require.Equal(t, "mockgen: interface Writer not found in package app/cmd/mockgen/testdata/source\n", stderr.String())
+	})
+
+	t.Run("Missing flags", func(t *testing.T) {
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-interface", "Logger"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 2, code)
+	})
+}
+
+func TestMock(t *testing.T) {
+	t.Run("Calls are recorded in order", func(t *testing.T) {
+		// Given
+		mt := &recorderT{}
+		rp := repositorytest.NewVehicleMapMock(mt)
+		rp.FindByIdFunc = func(id int) (v internal.Vehicle, err error) { return internal.Vehicle{Id: id}, nil }
+		rp.DeleteFunc = func(id int) (err error) { return nil }
+
+		// When
+		_, _ = rp.FindById(1)
+		_ = rp.Delete(1)
+		_, _ = rp.FindById(2)
+		// Then
+		require.True(t, rp.AssertCalls("FindById", "Delete", "FindById"))
+		require.False(t, rp.AssertCalls("Delete", "FindById", "FindById"))
+		require.Equal(t, []repositorytest.VehicleMapMockFindByIdCall{{Id: 1}, {Id: 2}}, rp.FindByIdCalls())
+		require.Equal(t, []string{"VehicleMapMock: calls [FindById Delete FindById], expected [Delete FindById FindById]"}, mt.errors)
+	})
+
+	t.Run("Call without function fails the test", func(t *testing.T) {
+		// Given
+		mt := &recorderT{}
+		rp := repositorytest.NewVehicleMapMock(mt)
+
+		// When
+		v, err := rp.FindByBrand("A")
+		// Then
+		require.Nil(t, v)
+		require.NoError(t, err)
+		require.Equal(t, []string{"VehicleMapMock: unexpected call to FindByBrand, FindByBrandFunc is not configured"}, mt.errors)
+		require.Len(t, rp.FindByBrandCalls(), 1)
+	})
+}
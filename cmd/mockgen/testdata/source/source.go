@@ -0,0 +1,20 @@
+// Package source has the interfaces used to test the generation of mocks
+package source
+
+import "io"
+
+// Closer is an interface that is embedded by Logger
+type Closer interface {
+	Close() error
+}
+
+// Logger is an interface with embedded interfaces, unnamed, variadic and qualified parameters
+type Logger interface {
+	Closer
+	Log(string, ...any)
+	Output() (w io.Writer)
+	Level(levels map[string]Level) (l Level, err error)
+}
+
+// Level is a type declared by the package
+type Level int
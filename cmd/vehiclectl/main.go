@@ -0,0 +1,329 @@
+// Command vehiclectl runs the queries of the api directly against a file of vehicles, and converts between file formats.
+//
+// Usage:
+//
+//	vehiclectl [-file path] [-output table|json|csv] <command> [arguments]
+//
+// Commands:
+//
+//	find color <color> <year>                     vehicles by color and fabrication year
+//	find brand <brand> <start_year> <end_year>    vehicles by brand between fabrication years
+//	average speed <brand>                         average max speed of a brand
+//	average capacity <brand>                      average capacity of a brand
+//	search [param=value ...]                      vehicles by the query parameters of GET /vehicles
+//	stats [param=value ...]                       metrics by the query parameters of GET /vehicles/stats
+//	convert <from> <to>                           converts a file of vehicles, formats given by the extensions
+//
+// Unlike the api, search returns every vehicle unless a limit is given.
+// The exit code is 0 on success, 1 when the command fails and 2 on invalid usage.
+package main
+
+import (
+	"app/internal"
+	"app/internal/config"
+	"app/internal/handler"
+	"app/internal/loader"
+	"app/internal/repository"
+	"app/internal/service"
+	"app/platform/logging"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	// errUsage is an error that represents an invalid command line
+	errUsage = errors.New("invalid usage")
+)
+
+// usage is the description of the command line
+const usage = `usage: vehiclectl [-file path] [-output table|json|csv] <command> [arguments]
+
+commands:
+  find color <color> <year>
+  find brand <brand> <start_year> <end_year>
+  average speed <brand>
+  average capacity <brand>
+  search [param=value ...]
+  stats [param=value ...]
+  convert <from> <to>
+
+flags:`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is a function that runs the command given by the arguments and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	// env
+	fs := flag.NewFlagSet("vehiclectl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, usage)
+		fs.PrintDefaults()
+	}
+	path := fs.String("file", config.Default().LoaderFilePath, "file of vehicles, .json or .csv")
+	format := fs.String("output", FormatTable, "output format: table, json or csv")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 || (*format != FormatTable && *format != FormatJSON && *format != FormatCSV) {
+		fs.Usage()
+		return 2
+	}
+
+	// - context: warnings of the service in the standard error
+	ctx := logging.WithLogger(context.Background(), logging.New(stderr, slog.LevelWarn))
+
+	// process
+	r, err := execute(ctx, *path, fs.Arg(0), fs.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, "vehiclectl:", errorMessage(err))
+		if errors.Is(err, errUsage) {
+			fs.Usage()
+			return 2
+		}
+		return 1
+	}
+
+	// output
+	if r == nil {
+		return 0
+	}
+	err = r.write(stdout, *format)
+	if err != nil {
+		fmt.Fprintln(stderr, "vehiclectl:", err)
+		return 1
+	}
+	return 0
+}
+
+// execute is a function that runs a command against the file and returns its result, nil if it has no output
+func execute(ctx context.Context, path string, command string, args []string) (r *result, err error) {
+	if command == "convert" {
+		if len(args) != 2 {
+			err = fmt.Errorf("%w: convert takes <from> <to>", errUsage)
+			return
+		}
+		err = convert(args[0], args[1])
+		return
+	}
+
+	sv, err := newService(path)
+	if err != nil {
+		return
+	}
+
+	var res result
+	switch command {
+	case "find":
+		res, err = find(ctx, sv, args)
+	case "average":
+		res, err = average(ctx, sv, args)
+	case "search":
+		res, err = search(ctx, sv, args)
+	case "stats":
+		res, err = stats(ctx, sv, args)
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errUsage, command)
+	}
+	if err != nil {
+		return
+	}
+
+	r = &res
+	return
+}
+
+// newService is a function that returns the service of the vehicles of the file
+func newService(path string) (sv internal.ServiceVehicle, err error) {
+	ld, err := loader.NewLoaderVehicle(path)
+	if err != nil {
+		return
+	}
+	db, err := ld.Load()
+	if err != nil {
+		return
+	}
+
+	sv = service.NewServiceVehicleDefault(repository.NewRepositoryReadVehicleMap(db))
+	return
+}
+
+// convert is a function that stores the vehicles of a file in another, formats given by the extensions
+func convert(from, to string) (err error) {
+	ld, err := loader.NewLoaderVehicle(from)
+	if err != nil {
+		return
+	}
+	st, err := loader.NewStorerVehicle(to)
+	if err != nil {
+		return
+	}
+
+	v, err := ld.Load()
+	if err != nil {
+		return
+	}
+	err = st.Store(v)
+	return
+}
+
+// find is a function that runs the find commands, as FindByColorAndYear and FindByBrandAndYearRange
+func find(ctx context.Context, sv internal.ServiceVehicle, args []string) (r result, err error) {
+	var v map[int]internal.Vehicle
+	switch {
+	case len(args) == 3 && args[0] == "color":
+		var year int
+		year, err = intArg("year", args[2])
+		if err != nil {
+			return
+		}
+		v, err = sv.FindByColorAndYear(ctx, args[1], year)
+	case len(args) == 4 && args[0] == "brand":
+		var startYear, endYear int
+		startYear, err = intArg("start_year", args[2])
+		if err != nil {
+			return
+		}
+		endYear, err = intArg("end_year", args[3])
+		if err != nil {
+			return
+		}
+		v, err = sv.FindByBrandAndYearRange(ctx, args[1], startYear, endYear)
+	default:
+		err = fmt.Errorf("%w: find takes color <color> <year> or brand <brand> <start_year> <end_year>", errUsage)
+	}
+	if err != nil {
+		return
+	}
+
+	sorted, err := internal.SortVehicles(v, nil)
+	if err != nil {
+		return
+	}
+	r = vehiclesResult(sorted)
+	return
+}
+
+// average is a function that runs the average commands, as AverageMaxSpeedByBrand and AverageCapacityByBrand
+func average(ctx context.Context, sv internal.ServiceVehicle, args []string) (r result, err error) {
+	if len(args) != 2 || (args[0] != "speed" && args[0] != "capacity") {
+		err = fmt.Errorf("%w: average takes speed <brand> or capacity <brand>", errUsage)
+		return
+	}
+
+	switch args[0] {
+	case "speed":
+		var a float64
+		a, err = sv.AverageMaxSpeedByBrand(ctx, args[1])
+		if err != nil {
+			return
+		}
+		r = valueResult("average_max_speed", a)
+	case "capacity":
+		var a int
+		a, err = sv.AverageCapacityByBrand(ctx, args[1])
+		if err != nil {
+			return
+		}
+		r = valueResult("average_capacity", float64(a))
+	}
+	return
+}
+
+// search is a function that runs the search command, as Search with the order and page of the list
+func search(ctx context.Context, sv internal.ServiceVehicle, args []string) (r result, err error) {
+	query, err := queryArgs(args)
+	if err != nil {
+		return
+	}
+	filter, err := handler.ParseVehicleFilter(query, handler.ListQueryParameters)
+	if err != nil {
+		return
+	}
+	lq, err := handler.ParseVehicleListQuery(query)
+	if err != nil {
+		return
+	}
+	if !query.Has("limit") {
+		lq.Page.Limit = 0
+	}
+
+	v, err := sv.Search(ctx, filter)
+	if err != nil {
+		return
+	}
+	sorted, err := internal.SortVehicles(v, lq.Sorts)
+	if err != nil {
+		return
+	}
+	r = vehiclesResult(internal.PaginateVehicles(sorted, lq.Page))
+	return
+}
+
+// stats is a function that runs the stats command, as Stats
+func stats(ctx context.Context, sv internal.ServiceVehicle, args []string) (r result, err error) {
+	query, err := queryArgs(args)
+	if err != nil {
+		return
+	}
+	filter, err := handler.ParseVehicleFilter(query, handler.StatsQueryParameters)
+	if err != nil {
+		return
+	}
+	groupBy, metrics, err := handler.ParseVehicleStatsQuery(query)
+	if err != nil {
+		return
+	}
+
+	rows, err := sv.Stats(ctx, filter, groupBy, metrics)
+	if err != nil {
+		return
+	}
+	r = statsResult(rows, groupBy, metrics)
+	return
+}
+
+// queryArgs is a function that returns the query parameters given as param=value arguments
+func queryArgs(args []string) (query url.Values, err error) {
+	query = make(url.Values)
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			err = fmt.Errorf("%w: %q is not param=value", errUsage, arg)
+			return
+		}
+		query.Add(key, value)
+	}
+	return
+}
+
+// intArg is a function that returns an argument as an int
+func intArg(name, value string) (n int, err error) {
+	n, err = strconv.Atoi(value)
+	if err != nil {
+		err = fmt.Errorf("%w: %s must be an integer", errUsage, name)
+	}
+	return
+}
+
+// errorMessage is a function that returns the message of an error, with the reason of an invalid query parameter
+func errorMessage(err error) string {
+	var qe *handler.QueryError
+	if errors.As(err, &qe) {
+		if qe.Message != "" {
+			return qe.Error() + ": " + qe.Message
+		}
+		return qe.Error() + ": " + qe.Err.Error()
+	}
+	return err.Error()
+}
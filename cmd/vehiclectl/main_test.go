@@ -0,0 +1,112 @@
+package main
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeVehicles is a function that stores the vehicles in a file of the temporary directory and returns its path
+func writeVehicles(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	st, err := loader.NewStorerVehicle(path)
+	require.NoError(t, err)
+	err = st.Store(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "Red", FabricationYear: 2010, Capacity: 4, MaxSpeed: 180}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "Blue", FabricationYear: 2012, Capacity: 2, MaxSpeed: 200}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Color: "Red", FabricationYear: 2010, Capacity: 5, MaxSpeed: 150}},
+	})
+	require.NoError(t, err)
+	return path
+}
+
+func TestRun(t *testing.T) {
+	t.Run("Search as CSV", func(t *testing.T) {
+		// Given
+		path := writeVehicles(t, "vehicles.json")
+
+		expectedOutput := "id,brand,model,registration,color,year,passengers,max_speed,fuel_type,transmission,weight,height,length,width\n" +
+			"2,Ford,,,Blue,2012,2,200,,,0,0,0,0\n" +
+			"1,Ford,,,Red,2010,4,180,,,0,0,0,0\n"
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-file", path, "-output", "csv", "search", "brand=Ford", "sort=-year"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 0, code)
+		require.Equal(t, expectedOutput, stdout.String())
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("Stats as JSON", func(t *testing.T) {
+		// Given
+		path := writeVehicles(t, "vehicles.json")
+
+		expectedOutput := `[{"group":{"color":"Blue"},"metrics":{"count":1,"avg:max_speed":200}},` +
+			`{"group":{"color":"Red"},"metrics":{"count":2,"avg:max_speed":165}}]`
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-file", path, "-output", "json", "stats", "group_by=color", "metrics=count,avg:max_speed"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 0, code)
+		require.JSONEq(t, expectedOutput, stdout.String())
+	})
+
+	t.Run("Find by color and year as table", func(t *testing.T) {
+		// Given
+		path := writeVehicles(t, "vehicles.json")
+
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-file", path, "find", "color", "Red", "2010"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 0, code)
+		require.Contains(t, stdout.String(), "ID  BRAND")
+		require.Len(t, bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n")), 3)
+	})
+
+	t.Run("Convert between formats", func(t *testing.T) {
+		// Given
+		from := writeVehicles(t, "vehicles.json")
+		to := filepath.Join(t.TempDir(), "vehicles.csv")
+
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"convert", from, to}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 0, code)
+		expected, err := loader.NewLoaderVehicleJSON(from).Load()
+		require.NoError(t, err)
+		result, err := loader.NewLoaderVehicleCSV(to).Load()
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("Invalid query parameter", func(t *testing.T) {
+		// Given
+		path := writeVehicles(t, "vehicles.json")
+
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-file", path, "search", "year_gte=recent"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 1, code)
+		require.Equal(t, "vehiclectl: invalid year_gte: must be a number\n", stderr.String())
+	})
+
+	t.Run("Unknown command", func(t *testing.T) {
+		// Given
+		path := writeVehicles(t, "vehicles.json")
+
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-file", path, "delete", "1"}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr.String(), `vehiclectl: invalid usage: unknown command "delete"`)
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/loader"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	// FormatTable is the output format of aligned columns, for people
+	FormatTable = "table"
+	// FormatJSON is the output format of the JSON encoding used by the api
+	FormatJSON = "json"
+	// FormatCSV is the output format of a CSV file with header
+	FormatCSV = "csv"
+)
+
+// result is a struct that represents the output of a command, in rows and in JSON format
+type result struct {
+	// header is the name of the columns of the rows
+	header []string
+	// rows are the values of the result as text
+	rows [][]string
+	// data is the value encoded in the JSON format
+	data any
+}
+
+// write is a method that writes the result in the format
+func (r result) write(w io.Writer, format string) (err error) {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(r.data)
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		err = writer.Write(r.header)
+		if err != nil {
+			return
+		}
+		err = writer.WriteAll(r.rows)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.header, "\t")))
+		for _, row := range r.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		err = tw.Flush()
+	}
+	return
+}
+
+// vehiclesResult is a function that returns the result of a list of vehicles, with the columns of the CSV format
+func vehiclesResult(v []internal.Vehicle) (r result) {
+	data := make([]loader.VehicleJSON, len(v))
+	r = result{header: loader.CSVHeader, rows: make([][]string, len(v)), data: data}
+	for i, vh := range v {
+		data[i] = loader.NewVehicleJSON(vh)
+		r.rows[i] = data[i].CSVRecord()
+	}
+	return
+}
+
+// valueResult is a function that returns the result of a single named value
+func valueResult(name string, value float64) (r result) {
+	r = result{
+		header: []string{name},
+		rows:   [][]string{{formatNumber(value)}},
+		data:   map[string]float64{name: value},
+	}
+	return
+}
+
+// statsResult is a function that returns the result of the metrics of the groups of vehicles
+// - columns: the group by fields followed by the metrics, in the requested order
+func statsResult(rows []internal.VehicleStatsRow, groupBy []internal.VehicleField, metrics []internal.VehicleMetric) (r result) {
+	data := make([]handler.VehicleStatsRowJSON, len(rows))
+	r = result{rows: make([][]string, len(rows)), data: data}
+	for _, f := range groupBy {
+		r.header = append(r.header, string(f))
+	}
+	for _, m := range metrics {
+		r.header = append(r.header, m.String())
+	}
+
+	for i, row := range rows {
+		data[i] = handler.VehicleStatsRowJSON{Group: row.Group, Metrics: row.Metrics}
+		for _, f := range groupBy {
+			switch value := row.Group[f].(type) {
+			case float64:
+				r.rows[i] = append(r.rows[i], formatNumber(value))
+			default:
+				r.rows[i] = append(r.rows[i], fmt.Sprint(value))
+			}
+		}
+		for _, m := range metrics {
+			r.rows[i] = append(r.rows[i], formatNumber(row.Metrics[m.String()]))
+		}
+	}
+	return
+}
+
+// formatNumber is a function that returns the shortest text of a number
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,85 @@
+// Command vehiclelint reports the data-quality issues of a file of vehicles without running the application.
+//
+// Usage:
+//
+//	vehiclelint [-format text|json] <file.json|file.csv>
+//
+// The exit code is 0 when the file has no issues, 1 when it has issues and 2 when it can not be read.
+package main
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is a function that lints the file given by the arguments and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	// env
+	fs := flag.NewFlagSet("vehiclelint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: vehiclelint [-format text|json] <file.json|file.csv>")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "text", "output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return 2
+	}
+
+	// process
+	rp, err := loader.NewQualityCheckerVehicleFile(fs.Arg(0)).Check()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	// output
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(loader.NewQualityReportJSON(rp))
+	default:
+		err = writeText(stdout, rp)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if len(rp.Issues) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeText is a function that writes the report with an issue per line and a summary
+func writeText(w io.Writer, rp internal.QualityReport) (err error) {
+	for _, is := range rp.Issues {
+		_, err = fmt.Fprintf(w, "record %d (id %d): %s: %s: %s\n", is.Record, is.Id, is.Field, is.Kind, is.Message)
+		if err != nil {
+			return
+		}
+	}
+
+	counts := rp.Counts()
+	summary := make([]string, len(internal.QualityIssueKinds))
+	for i, k := range internal.QualityIssueKinds {
+		summary[i] = fmt.Sprintf("%s=%d", k, counts[k])
+	}
+	_, err = fmt.Fprintf(w, "%d records, %d issues: %s\n", rp.Records, len(rp.Issues), strings.Join(summary, " "))
+	return
+}
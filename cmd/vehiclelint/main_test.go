@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile is a function that writes the content in a file of the temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0o644)
+	require.NoError(t, err)
+	return path
+}
+
+const (
+	// cleanVehicle is a record without issues
+	cleanVehicle = `{"id":1,"brand":"Ford","model":"Focus","registration":"AB-123","color":"Red","year":2010,"passengers":4,` +
+		`"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1200,"height":1.5,"length":4.3,"width":1.8}`
+	// invalidVehicle is a record with the same id as cleanVehicle and a fuel type that is not accepted
+	invalidVehicle = `{"id":1,"brand":"Fiat","model":"Uno","registration":"CD-456","color":"Blue","year":2012,"passengers":5,` +
+		`"max_speed":150,"fuel_type":"steam","transmission":"manual","weight":900,"height":1.4,"length":3.6,"width":1.6}`
+)
+
+func TestRun(t *testing.T) {
+	t.Run("File without issues", func(t *testing.T) {
+		// Given
+		path := writeFile(t, "vehicles.json", "["+cleanVehicle+"]")
+
+		expectedOutput := "1 records, 0 issues: duplicate_id=0 duplicate_registration=0 missing_field=0 out_of_range=0 unknown_value=0\n"
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{path}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 0, code)
+		require.Equal(t, expectedOutput, stdout.String())
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("File with issues as text", func(t *testing.T) {
+		// Given
+		path := writeFile(t, "vehicles.json", "["+cleanVehicle+","+invalidVehicle+"]")
+
+		expectedOutput := "record 2 (id 1): id: duplicate_id: same id as record 1\n" +
+			"record 2 (id 1): fuel_type: unknown_value: must be one of gas, gasoline, diesel, biodiesel, electric, hybrid\n" +
+			"2 records, 2 issues: duplicate_id=1 duplicate_registration=0 missing_field=0 out_of_range=0 unknown_value=1\n"
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{path}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 1, code)
+		require.Equal(t, expectedOutput, stdout.String())
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("File with issues as JSON", func(t *testing.T) {
+		// Given
+		path := writeFile(t, "vehicles.json", "["+cleanVehicle+","+invalidVehicle+"]")
+
+		expectedOutput := `{"records":2,` +
+			`"counts":{"missing_field":0,"duplicate_id":1,"duplicate_registration":0,"out_of_range":0,"unknown_value":1},` +
+			`"issues":[` +
+			`{"record":2,"id":1,"field":"id","kind":"duplicate_id","message":"same id as record 1"},` +
+			`{"record":2,"id":1,"field":"fuel_type","kind":"unknown_value","message":"must be one of gas, gasoline, diesel, biodiesel, electric, hybrid"}]}`
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-format", "json", path}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 1, code)
+		require.JSONEq(t, expectedOutput, stdout.String())
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("Missing field of a CSV file", func(t *testing.T) {
+		// Given
+		path := writeFile(t, "vehicles.csv", "id,brand,model,registration,color,year,passengers,max_speed,fuel_type,transmission,weight,height,length,width\n"+
+			"1,Ford,Focus,AB-123,,2010,4,180,gasoline,manual,1200,1.5,4.3,1.8\n")
+
+		expectedOutput := "record 1 (id 1): color: missing_field: is missing\n" +
+			"1 records, 1 issues: duplicate_id=0 duplicate_registration=0 missing_field=1 out_of_range=0 unknown_value=0\n"
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{path}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 1, code)
+		require.Equal(t, expectedOutput, stdout.String())
+	})
+
+	t.Run("File that can not be read", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+
+		// When
+		var stdout, stderr bytes.Buffer
+		code := run([]string{path}, &stdout, &stderr)
+		// Then
+		require.Equal(t, 2, code)
+		require.Empty(t, stdout.String())
+		require.NotEmpty(t, stderr.String())
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		// Given
+		cases := [][]string{
+			{},
+			{"a.json", "b.json"},
+			{"-format", "xml", "a.json"},
+			{"-unknown", "a.json"},
+		}
+
+		for _, args := range cases {
+			// When
+			var stdout, stderr bytes.Buffer
+			code := run(args, &stdout, &stderr)
+			// Then
+			require.Equal(t, 2, code, args)
+			require.Empty(t, stdout.String(), args)
+			require.Contains(t, stderr.String(), "usage: vehiclelint", args)
+		}
+	})
+}
@@ -4,6 +4,8 @@ package application
 type Application interface {
 	// SetUp is a method that sets up the application
 	SetUp() (err error)
-	// Run is a method that runs the application
+	// Run is a method that runs the application until it is stopped
 	Run() (err error)
+	// TearDown is a method that releases the resources of the application, flushing any pending state
+	TearDown() (err error)
 }
\ No newline at end of file
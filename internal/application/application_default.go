@@ -1,24 +1,59 @@
 package application
 
 import (
+	"app/internal"
 	"app/internal/handler"
 	"app/internal/loader"
+	"app/internal/reloader"
 	"app/internal/repository"
 	"app/internal/service"
+	"app/platform/logging"
+	"app/platform/metrics"
+	"app/platform/web/cache"
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 )
 
 // ConfigApplicationDefault is a struct that represents the configuration for ApplicationDefault
 type ConfigApplicationDefault struct {
 	// Router is the router / multiplexer that will be used by the application
 	Router *chi.Mux
+	// Logger is the logger of the application, records of the requests carry their request ID
+	Logger *slog.Logger
 	// ServerAddress is the address where the server will be listening
 	ServerAddress string
+	// ServerReadTimeout is the maximum duration for reading an entire request, including the body
+	ServerReadTimeout time.Duration
+	// ServerWriteTimeout is the maximum duration before timing out writes of the response
+	ServerWriteTimeout time.Duration
+	// ServerIdleTimeout is the maximum duration to wait for the next request on a keep-alive connection
+	ServerIdleTimeout time.Duration
+	// ShutdownTimeout is the maximum duration to wait for in-flight requests to finish on shutdown
+	ShutdownTimeout time.Duration
 	// LoaderFilePath is the path to the file that contains the vehicles
+	// - format: given by the extension, .json or .csv
 	LoaderFilePath string
+	// LoaderReloadInterval is the interval between checks for changes in the file, 0 disables the reload
+	// - ignored when the vehicles are held in the SQLite database
+	LoaderReloadInterval time.Duration
+	// StorerFlushInterval is the interval between stores of the vehicles in the file, 0 stores after each change
+	// - ignored when the vehicles are held in the SQLite database
+	StorerFlushInterval time.Duration
+	// RepositorySQLitePath is the path to the SQLite database that holds the vehicles
+	// - empty: the vehicles are held in memory
+	// - otherwise: the file only seeds the database when it has no vehicles, it is neither reloaded nor written
+	RepositorySQLitePath string
+	// CacheSize is the number of responses of the vehicles kept in memory, 0 disables the cache
+	// - the responses are tagged with an ETag and revalidated with If-None-Match either way
+	CacheSize int
 }
 
 // NewApplicationDefault is a function that returns a new instance of ApplicationDefault
@@ -26,24 +61,65 @@ func NewApplicationDefault(cfg *ConfigApplicationDefault) *ApplicationDefault {
 	// default values
 	defaultConfig := &ConfigApplicationDefault{
 		Router: chi.NewRouter(),
+		Logger: logging.New(os.Stdout, slog.LevelInfo),
 		ServerAddress: ":8080",
+		ServerReadTimeout: 10 * time.Second,
+		ServerWriteTimeout: 30 * time.Second,
+		ServerIdleTimeout: 60 * time.Second,
+		ShutdownTimeout: 15 * time.Second,
 	}
 	if cfg != nil {
 		if cfg.Router != nil {
 			defaultConfig.Router = cfg.Router
 		}
+		if cfg.Logger != nil {
+			defaultConfig.Logger = cfg.Logger
+		}
 		if cfg.ServerAddress != "" {
 			defaultConfig.ServerAddress = cfg.ServerAddress
 		}
+		if cfg.ServerReadTimeout > 0 {
+			defaultConfig.ServerReadTimeout = cfg.ServerReadTimeout
+		}
+		if cfg.ServerWriteTimeout > 0 {
+			defaultConfig.ServerWriteTimeout = cfg.ServerWriteTimeout
+		}
+		if cfg.ServerIdleTimeout > 0 {
+			defaultConfig.ServerIdleTimeout = cfg.ServerIdleTimeout
+		}
+		if cfg.ShutdownTimeout > 0 {
+			defaultConfig.ShutdownTimeout = cfg.ShutdownTimeout
+		}
 		if cfg.LoaderFilePath != "" {
 			defaultConfig.LoaderFilePath = cfg.LoaderFilePath
 		}
+		if cfg.LoaderReloadInterval > 0 {
+			defaultConfig.LoaderReloadInterval = cfg.LoaderReloadInterval
+		}
+		if cfg.StorerFlushInterval > 0 {
+			defaultConfig.StorerFlushInterval = cfg.StorerFlushInterval
+		}
+		if cfg.RepositorySQLitePath != "" {
+			defaultConfig.RepositorySQLitePath = cfg.RepositorySQLitePath
+		}
+		if cfg.CacheSize > 0 {
+			defaultConfig.CacheSize = cfg.CacheSize
+		}
 	}
 
 	return &ApplicationDefault{
 		router: defaultConfig.Router,
+		logger: defaultConfig.Logger,
 		serverAddress: defaultConfig.ServerAddress,
+		serverReadTimeout: defaultConfig.ServerReadTimeout,
+		serverWriteTimeout: defaultConfig.ServerWriteTimeout,
+		serverIdleTimeout: defaultConfig.ServerIdleTimeout,
+		shutdownTimeout: defaultConfig.ShutdownTimeout,
 		loaderFilePath: defaultConfig.LoaderFilePath,
+		loaderReloadInterval: defaultConfig.LoaderReloadInterval,
+		storerFlushInterval: defaultConfig.StorerFlushInterval,
+		repositorySQLitePath: defaultConfig.RepositorySQLitePath,
+		cacheSize: defaultConfig.CacheSize,
 	}
 }
 
@@ -51,35 +127,105 @@ func NewApplicationDefault(cfg *ConfigApplicationDefault) *ApplicationDefault {
 type ApplicationDefault struct {
 	// router is the router / multiplexer that will be used by the application
 	router *chi.Mux
+	// logger is the logger of the application
+	logger *slog.Logger
 	// serverAddress is the address where the server will be listening
 	serverAddress string
+	// serverReadTimeout is the maximum duration for reading an entire request
+	serverReadTimeout time.Duration
+	// serverWriteTimeout is the maximum duration before timing out writes of the response
+	serverWriteTimeout time.Duration
+	// serverIdleTimeout is the maximum duration to wait for the next request on a keep-alive connection
+	serverIdleTimeout time.Duration
+	// shutdownTimeout is the maximum duration to wait for in-flight requests to finish on shutdown
+	shutdownTimeout time.Duration
 	// loaderFilePath is the path to the file that contains the vehicles
 	loaderFilePath string
+	// loaderReloadInterval is the interval between checks for changes in the file
+	loaderReloadInterval time.Duration
+	// storerFlushInterval is the interval between stores of the vehicles in the file
+	storerFlushInterval time.Duration
+	// repositorySQLitePath is the path to the SQLite database that holds the vehicles, empty holds them in memory
+	repositorySQLitePath string
+	// cacheSize is the number of responses of the vehicles kept in memory, 0 disables the cache
+	cacheSize int
+	// sqlite is the database of the vehicles, nil when they are held in memory
+	sqlite *repository.RepositoryVehicleSQLite
+	// rl is the reloader of the vehicles, nil when they are held in the database
+	rl *reloader.ReloaderVehicleFile
+	// st is the repository of the vehicles stored back in the file, nil when they are held in the database
+	st *repository.RepositoryVehicleStorer
 }
 
 // SetUp is a method that sets up the application
 func (a *ApplicationDefault) SetUp() (err error) {
 	// dependencies
-	// - loader: loader for vehicles
-	ld := loader.NewLoaderVehicleJSON(a.loaderFilePath)
-	// - db: map of vehicles
-	db, err := ld.Load()
+	// - loader: loader for vehicles, selected by the extension of the file
+	ld, err := loader.NewLoaderVehicle(a.loaderFilePath)
+	if err != nil {
+		return
+	}
+	// - repository and reloader: the vehicles are held in memory, loaded from the file and stored back in it,
+	// or in the SQLite database if a path is given, seeded from the file only when empty
+	var rp internal.RepositoryVehicle
+	var rl internal.ReloaderVehicle
+	var vdb *repository.RepositoryVehicleVersioned
+	if a.repositorySQLitePath != "" {
+		rp, rl, vdb, err = a.setUpSQLite(ld)
+	} else {
+		rp, rl, vdb, err = a.setUpMemory(ld)
+	}
 	if err != nil {
 		return
 	}
-	// - repository: repository for vehicles
-	rp := repository.NewRepositoryReadVehicleMap(db)
 	// - service: service for vehicles
 	sv := service.NewServiceVehicleDefault(rp)
 	// - handler: handler for vehicles
 	hd := handler.NewHandlerVehicle(sv)
+	// - checker: data quality of the records of the file, read on each check
+	qc := loader.NewQualityCheckerVehicleFile(a.loaderFilePath)
+	// - handler: handler for administration
+	hdAdmin := handler.NewHandlerAdmin(rl, qc)
+	// - handler: handler for the health of the application
+	hdHealth := handler.NewHandlerHealth(rp, rl, handler.ReadBuildInfo())
+	// - cache: responses of the vehicles by version, nil if disabled
+	var lru *cache.LRU
+	if a.cacheSize > 0 {
+		lru = cache.NewLRU(a.cacheSize)
+	}
+
+	// - metrics: requests served and state of the vehicles, exposed in the Prometheus text format
+	reg := metrics.NewRegistry()
+	reg.Register(vehicleCollectors(rp, rl)...)
+	mt := metrics.NewHTTPMetrics(reg, func(r *http.Request) string {
+		return chi.RouteContext(r.Context()).RoutePattern()
+	})
 
 	// routes
 	// - middlewares
-	a.router.Use(middleware.Logger)
-	a.router.Use(middleware.Recoverer)
+	a.router.Use(logging.Middleware(a.logger))
+	a.router.Use(mt.Middleware)
+	a.router.Use(logging.Recoverer)
 	// - endpoints
+	// Get whether the process is alive
+	a.router.Get("/healthz", hdHealth.Healthz())
+	// Get whether the vehicles are loaded and can be served
+	a.router.Get("/readyz", hdHealth.Readyz())
+	// Get the version of the application and of the dataset
+	a.router.Get("/version", hdHealth.Version())
+	// Get the metrics of the application
+	a.router.Get("/metrics", reg.Handler())
 	a.router.Route("/vehicles", func(r chi.Router) {
+		// - middlewares
+		r.Use(cache.Middleware(vdb.Version, lru))
+		// Get vehicles by any combination of fields (query)
+		r.Get("/", hd.Search())
+		// Get metrics of the vehicles grouped by fields (query)
+		r.Get("/stats", hd.Stats())
+		// Get a vehicle by id
+		r.Get("/{id}", hd.FindById())
+		// Get a vehicle by registration
+		r.Get("/registration/{registration}", hd.FindByRegistration())
 		// Get vehicles by color and year
 		r.Get("/color/{color}/year/{year}", hd.FindByColorAndYear())
 		// Get vehicles by brand between years
@@ -90,13 +236,123 @@ func (a *ApplicationDefault) SetUp() (err error) {
 		r.Get("/average_capacity/brand/{brand}", hd.AverageCapacityByBrand())
 		// Get vehicles by weight range (query)
 		r.Get("/weight", hd.SearchByWeightRange())
+		// Create a vehicle
+		r.Post("/", hd.Create())
+		// Replace a vehicle
+		r.Put("/{id}", hd.Update())
+		// Update some attributes of a vehicle
+		r.Patch("/{id}", hd.Patch())
+		// Delete a vehicle
+		r.Delete("/{id}", hd.Delete())
+	})
+	a.router.Route("/admin", func(r chi.Router) {
+		// Get the outcome of the reloads of the vehicles
+		r.Get("/reload", hdAdmin.ReloadStatus())
+		// Get the data-quality issues of the file of the vehicles
+		r.Get("/data-quality", hdAdmin.DataQuality())
 	})
 
 	return
 }
 
-// Run is a method that runs the application
+// setUpMemory is a method that sets up the vehicles held in memory
+// - the file is loaded, reloaded each time it changes but not by its own stores, and the changes are stored back in it
+func (a *ApplicationDefault) setUpMemory(ld internal.LoaderVehicle) (rp internal.RepositoryVehicle, rl internal.ReloaderVehicle, vdb *repository.RepositoryVehicleVersioned, err error) {
+	// - storer: storer for vehicles, in the same format of the file
+	st, err := loader.NewStorerVehicle(a.loaderFilePath)
+	if err != nil {
+		return
+	}
+	// - versioned: counts the changes of the vehicles, by the reloader or the requests
+	vdb = repository.NewRepositoryVehicleVersioned(repository.NewRepositoryReadVehicleMap(nil))
+	// - repository: repository for vehicles, stored back in the file after being modified
+	sf := reloader.NewStorerVehicleFile(st, a.loaderFilePath)
+	a.st = repository.NewRepositoryVehicleStorer(vdb, sf, a.storerFlushInterval)
+	// - reloader: loads the vehicles in the repository, and again each time the file changes but not by its own stores
+	a.rl = reloader.NewReloaderVehicleFile(ld, a.st, sf, a.loaderFilePath, a.loaderReloadInterval)
+	err = a.rl.Reload()
+	if err != nil {
+		return
+	}
+	if a.loaderReloadInterval > 0 {
+		a.rl.Start()
+	}
+
+	rp, rl = a.st, a.rl
+	return
+}
+
+// setUpSQLite is a method that sets up the vehicles held in the SQLite database
+// - the database keeps the vehicles and their changes across starts, so the file only seeds it when empty
+// and is neither reloaded nor written
+func (a *ApplicationDefault) setUpSQLite(ld internal.LoaderVehicle) (rp internal.RepositoryVehicle, rl internal.ReloaderVehicle, vdb *repository.RepositoryVehicleVersioned, err error) {
+	// - db: SQLite database of vehicles
+	a.sqlite, err = repository.NewRepositoryVehicleSQLite(a.repositorySQLitePath)
+	if err != nil {
+		return
+	}
+	// - versioned: counts the changes of the vehicles, by the seed or the requests
+	vdb = repository.NewRepositoryVehicleVersioned(a.sqlite)
+	// - seeder: loads the file in the database if it has no vehicles
+	sd := reloader.NewSeederVehicleFile(ld, vdb, a.loaderFilePath)
+	seeded, err := sd.Seed()
+	if err != nil {
+		return
+	}
+	a.logger.Info("vehicles database opened", slog.String("path", a.repositorySQLitePath), slog.Bool("seeded", seeded))
+
+	rp, rl = vdb, sd
+	return
+}
+
+// Run is a method that runs the application until it receives SIGINT or SIGTERM
+// - in-flight requests are drained for up to the shutdown timeout
 func (a *ApplicationDefault) Run() (err error) {
-	err = http.ListenAndServe(a.serverAddress, a.router)
+	server := &http.Server{
+		Addr: a.serverAddress,
+		Handler: a.router,
+		ReadTimeout: a.serverReadTimeout,
+		WriteTimeout: a.serverWriteTimeout,
+		IdleTimeout: a.serverIdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	a.logger.Info("server listening", slog.String("address", a.serverAddress))
+
+	select {
+	case err = <-serveErr:
+		// the server failed before any signal, e.g. the address is already in use
+		return
+	case <-ctx.Done():
+	}
+
+	// drain
+	a.logger.Info("shutting down", slog.Duration("timeout", a.shutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+	err = server.Shutdown(shutdownCtx)
+	if e := <-serveErr; !errors.Is(e, http.ErrServerClosed) {
+		err = errors.Join(err, e)
+	}
+	return
+}
+
+// TearDown is a method that stops the background tasks, stores the pending changes of the vehicles and closes their database
+func (a *ApplicationDefault) TearDown() (err error) {
+	if a.rl != nil {
+		a.rl.Stop()
+	}
+	if a.st != nil {
+		err = a.st.Close()
+	}
+	if a.sqlite != nil {
+		err = errors.Join(err, a.sqlite.Close())
+	}
 	return
 }
\ No newline at end of file
@@ -0,0 +1,42 @@
+package application
+
+import (
+	"app/internal"
+	"app/platform/metrics"
+)
+
+// vehicleCollectors is a function that returns the metric families of the vehicles, computed when collected
+func vehicleCollectors(rp internal.RepositoryReadVehicle, rl internal.ReloaderVehicle) []metrics.Collector {
+	return []metrics.Collector{
+		metrics.NewFunc("vehicles_fleet_size", "Number of vehicles by brand.", metrics.TypeGauge, []string{"brand"}, func() (s []metrics.Sample) {
+			byBrand, err := rp.CountByBrand()
+			if err != nil {
+				return
+			}
+			for brand, n := range byBrand {
+				s = append(s, metrics.Sample{Labels: metrics.Labels{"brand": brand}, Value: float64(n)})
+			}
+			return
+		}),
+		metrics.NewFunc("vehicles_reloads_total", "Number of reloads of the vehicles file by outcome.", metrics.TypeCounter, []string{"outcome"}, func() []metrics.Sample {
+			st := rl.Status()
+			return []metrics.Sample{
+				{Labels: metrics.Labels{"outcome": "success"}, Value: float64(st.Succeeded)},
+				{Labels: metrics.Labels{"outcome": "failure"}, Value: float64(st.Failed)},
+			}
+		}),
+		metrics.NewFunc("vehicles_reload_last_success_timestamp_seconds", "Unix time of the last successful reload of the vehicles file.", metrics.TypeGauge, nil, func() []metrics.Sample {
+			st := rl.Status()
+			if st.LastReload.IsZero() {
+				return []metrics.Sample{{Value: 0}}
+			}
+			return []metrics.Sample{{Value: float64(st.LastReload.UnixNano()) / 1e9}}
+		}),
+		metrics.NewFunc("vehicles_reload_healthy", "1 if the last reload of the vehicles file succeeded, 0 otherwise.", metrics.TypeGauge, nil, func() []metrics.Sample {
+			if rl.Status().LastError != nil {
+				return []metrics.Sample{{Value: 0}}
+			}
+			return []metrics.Sample{{Value: 1}}
+		}),
+	}
+}
@@ -0,0 +1,300 @@
+package config
+
+import (
+	"app/internal/application"
+	"app/internal/loader"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrConfigInvalid is an error that represents a configuration that can not be used to run the application
+	ErrConfigInvalid = errors.New("config: invalid configuration")
+	// ErrConfigFile is an error that represents a config file that can not be read
+	ErrConfigFile = errors.New("config: invalid config file")
+)
+
+const (
+	// EnvPrefix is the prefix of the environment variables read by the configuration
+	EnvPrefix = "VEHICLES_"
+	// EnvConfigFile is the environment variable with the path to the config file
+	EnvConfigFile = EnvPrefix + "CONFIG"
+)
+
+// Config is a struct that represents the configuration of the application
+type Config struct {
+	// ServerAddress is the address where the server will be listening
+	ServerAddress string `yaml:"server_address"`
+	// ServerReadTimeout is the maximum duration for reading an entire request
+	ServerReadTimeout time.Duration `yaml:"server_read_timeout"`
+	// ServerWriteTimeout is the maximum duration before timing out writes of the response
+	ServerWriteTimeout time.Duration `yaml:"server_write_timeout"`
+	// ServerIdleTimeout is the maximum duration to wait for the next request on a keep-alive connection
+	ServerIdleTimeout time.Duration `yaml:"server_idle_timeout"`
+	// ShutdownTimeout is the maximum duration to wait for in-flight requests to finish on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// LoaderFilePath is the path to the file that contains the vehicles, .json or .csv
+	LoaderFilePath string `yaml:"loader_file_path"`
+	// LoaderReloadInterval is the interval between checks for changes in the file, 0 disables the reload
+	LoaderReloadInterval time.Duration `yaml:"loader_reload_interval"`
+	// StorerFlushInterval is the interval between stores of the vehicles in the file, 0 stores after each change
+	StorerFlushInterval time.Duration `yaml:"storer_flush_interval"`
+	// RepositorySQLitePath is the path to the SQLite database that holds the vehicles, empty keeps them in memory
+	// - the file only seeds an empty database, it is neither reloaded nor written
+	RepositorySQLitePath string `yaml:"repository_sqlite_path"`
+	// CacheSize is the number of responses kept in memory by the cache, 0 disables it
+	CacheSize int `yaml:"cache_size"`
+	// LogLevel is the minimum level of the records logged: debug, info, warn or error
+	LogLevel slog.Level `yaml:"log_level"`
+}
+
+// Default is a function that returns the configuration used when nothing else is given
+func Default() (cfg Config) {
+	cfg = Config{
+		ServerAddress: ":8080",
+		ServerReadTimeout: 10 * time.Second,
+		ServerWriteTimeout: 30 * time.Second,
+		ServerIdleTimeout: 60 * time.Second,
+		ShutdownTimeout: 15 * time.Second,
+		LoaderFilePath: "docs/db/vehicles_100.json",
+		LoaderReloadInterval: 5 * time.Second,
+		LogLevel: slog.LevelInfo,
+	}
+	return
+}
+
+// option is a struct that represents a setting that can be given by environment variable and flag
+type option struct {
+	// name is the name of the flag, the environment variable is the name in upper snake case with EnvPrefix
+	name string
+	// usage is the description of the flag
+	usage string
+	// set is the function that parses the value into the configuration
+	set func(cfg *Config, value string) (err error)
+}
+
+// env is a method that returns the name of the environment variable of the option
+func (o option) env() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(o.name, "-", "_"))
+}
+
+// options is the list of settings that can be given by environment variable and flag
+var options = []option{
+	{name: "server-address", usage: "address where the server will be listening", set: setString(func(cfg *Config) *string { return &cfg.ServerAddress })},
+	{name: "server-read-timeout", usage: "maximum duration for reading an entire request", set: setDuration(func(cfg *Config) *time.Duration { return &cfg.ServerReadTimeout })},
+	{name: "server-write-timeout", usage: "maximum duration before timing out writes of the response", set: setDuration(func(cfg *Config) *time.Duration { return &cfg.ServerWriteTimeout })},
+	{name: "server-idle-timeout", usage: "maximum duration to wait for the next request on a keep-alive connection", set: setDuration(func(cfg *Config) *time.Duration { return &cfg.ServerIdleTimeout })},
+	{name: "shutdown-timeout", usage: "maximum duration to wait for in-flight requests on shutdown", set: setDuration(func(cfg *Config) *time.Duration { return &cfg.ShutdownTimeout })},
+	{name: "loader-file-path", usage: "path to the file that contains the vehicles (.json or .csv)", set: setString(func(cfg *Config) *string { return &cfg.LoaderFilePath })},
+	{name: "loader-reload-interval", usage: "interval between checks for changes in the file, 0 disables the reload", set: setDuration(func(cfg *Config) *time.Duration { return &cfg.LoaderReloadInterval })},
+	{name: "storer-flush-interval", usage: "interval between stores of the vehicles in the file, 0 stores after each change", set: setDuration(func(cfg *Config) *time.Duration { return &cfg.StorerFlushInterval })},
+	{name: "repository-sqlite-path", usage: "path to the SQLite database that holds the vehicles, seeded from the file when empty; empty keeps them in memory", set: setString(func(cfg *Config) *string { return &cfg.RepositorySQLitePath })},
+	{name: "cache-size", usage: "number of responses kept in memory by the cache, 0 disables it", set: setInt(func(cfg *Config) *int { return &cfg.CacheSize })},
+	{name: "log-level", usage: "minimum level of the records logged: debug, info, warn or error", set: func(cfg *Config, value string) error { return cfg.LogLevel.UnmarshalText([]byte(value)) }},
+}
+
+// setString is a function that returns a setter for a string field of the configuration
+func setString(field func(cfg *Config) *string) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) (err error) {
+		*field(cfg) = value
+		return
+	}
+}
+
+// setInt is a function that returns a setter for an integer field of the configuration
+func setInt(field func(cfg *Config) *int) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) (err error) {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return
+		}
+		*field(cfg) = n
+		return
+	}
+}
+
+// setDuration is a function that returns a setter for a duration field of the configuration
+func setDuration(field func(cfg *Config) *time.Duration) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) (err error) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return
+		}
+		*field(cfg) = d
+		return
+	}
+}
+
+// Load is a function that builds the configuration from, in increasing order of precedence:
+// the defaults, the config file, the environment variables and the command-line flags
+// - args are the command-line arguments without the program name
+// - lookupEnv is the function used to read the environment, usually os.LookupEnv
+// - printConfig is true when the effective configuration was asked to be printed
+func Load(args []string, lookupEnv func(key string) (string, bool)) (cfg Config, printConfig bool, err error) {
+	// flags: parsed first to know the config file, applied last
+	fs := flag.NewFlagSet("vehicles", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configFile := fs.String("config", "", "path to a YAML or JSON config file (env "+EnvConfigFile+")")
+	fs.BoolVar(&printConfig, "print-config", false, "print the effective configuration and exit")
+	type flagValue struct {
+		opt   option
+		value string
+	}
+	var flagValues []flagValue
+	for _, opt := range options {
+		opt := opt
+		fs.Func(opt.name, opt.usage+" (env "+opt.env()+")", func(value string) error {
+			flagValues = append(flagValues, flagValue{opt: opt, value: value})
+			return nil
+		})
+	}
+	if err = fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fs.PrintDefaults()
+		}
+		return
+	}
+	if fs.NArg() > 0 {
+		err = fmt.Errorf("%w: unexpected argument %q", ErrConfigInvalid, fs.Arg(0))
+		return
+	}
+
+	// defaults
+	cfg = Default()
+
+	// file
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv(EnvConfigFile)
+	}
+	if path != "" {
+		if err = cfg.readFile(path); err != nil {
+			return
+		}
+	}
+
+	// env
+	for _, opt := range options {
+		value, ok := lookupEnv(opt.env())
+		if !ok {
+			continue
+		}
+		if err = opt.set(&cfg, value); err != nil {
+			err = fmt.Errorf("%w: %s: %w", ErrConfigInvalid, opt.env(), err)
+			return
+		}
+	}
+
+	// flags
+	for _, fv := range flagValues {
+		if err = fv.opt.set(&cfg, fv.value); err != nil {
+			err = fmt.Errorf("%w: -%s: %w", ErrConfigInvalid, fv.opt.name, err)
+			return
+		}
+	}
+
+	err = cfg.Validate()
+	return
+}
+
+// readFile is a method that overrides the configuration with the settings present in a YAML or JSON file
+// - JSON is read as YAML, durations are given as strings like "5s"
+func (c *Config) readFile(path string) (err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrConfigFile, err)
+		return
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+	default:
+		err = fmt.Errorf("%w: unsupported format %q", ErrConfigFile, ext)
+		return
+	}
+
+	dc := yaml.NewDecoder(bytes.NewReader(b))
+	dc.KnownFields(true)
+	if err = dc.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		err = fmt.Errorf("%w: %s: %w", ErrConfigFile, path, err)
+		return
+	}
+	err = nil
+	return
+}
+
+// Validate is a method that checks that the configuration can be used to run the application
+func (c Config) Validate() (err error) {
+	if _, _, e := net.SplitHostPort(c.ServerAddress); e != nil {
+		return fmt.Errorf("%w: server_address: %w", ErrConfigInvalid, e)
+	}
+	if _, e := loader.NewLoaderVehicle(c.LoaderFilePath); e != nil {
+		return fmt.Errorf("%w: loader_file_path: %w", ErrConfigInvalid, e)
+	}
+
+	timeouts := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"server_read_timeout", c.ServerReadTimeout},
+		{"server_write_timeout", c.ServerWriteTimeout},
+		{"server_idle_timeout", c.ServerIdleTimeout},
+		{"shutdown_timeout", c.ShutdownTimeout},
+	}
+	for _, t := range timeouts {
+		if t.d <= 0 {
+			return fmt.Errorf("%w: %s: must be greater than 0", ErrConfigInvalid, t.name)
+		}
+	}
+	if c.LoaderReloadInterval < 0 {
+		return fmt.Errorf("%w: loader_reload_interval: must not be negative", ErrConfigInvalid)
+	}
+	if c.StorerFlushInterval < 0 {
+		return fmt.Errorf("%w: storer_flush_interval: must not be negative", ErrConfigInvalid)
+	}
+	if c.CacheSize < 0 {
+		return fmt.Errorf("%w: cache_size: must not be negative", ErrConfigInvalid)
+	}
+	return
+}
+
+// Print is a method that writes the configuration as YAML
+func (c Config) Print(w io.Writer) (err error) {
+	ec := yaml.NewEncoder(w)
+	defer ec.Close()
+
+	err = ec.Encode(c)
+	return
+}
+
+// Application is a method that returns the configuration of the application
+// - logger is the logger of the application, usually built with the log level of the configuration
+func (c Config) Application(logger *slog.Logger) (cfg *application.ConfigApplicationDefault) {
+	cfg = &application.ConfigApplicationDefault{
+		Logger: logger,
+		ServerAddress: c.ServerAddress,
+		ServerReadTimeout: c.ServerReadTimeout,
+		ServerWriteTimeout: c.ServerWriteTimeout,
+		ServerIdleTimeout: c.ServerIdleTimeout,
+		ShutdownTimeout: c.ShutdownTimeout,
+		LoaderFilePath: c.LoaderFilePath,
+		LoaderReloadInterval: c.LoaderReloadInterval,
+		StorerFlushInterval: c.StorerFlushInterval,
+		RepositorySQLitePath: c.RepositorySQLitePath,
+		CacheSize: c.CacheSize,
+	}
+	return
+}
@@ -0,0 +1,155 @@
+package config_test
+
+import (
+	"app/internal/config"
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// env is a helper that returns a lookup function over a fixed set of environment variables
+func env(vars map[string]string) func(key string) (string, bool) {
+	return func(key string) (value string, ok bool) {
+		value, ok = vars[key]
+		return
+	}
+}
+
+// writeFile is a helper that writes a config file in a temporary directory
+func writeFile(t *testing.T, name, content string) (path string) {
+	path = filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err)
+	return
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		// When
+		cfg, printConfig, err := config.Load(nil, env(nil))
+		// Then
+		require.NoError(t, err)
+		require.False(t, printConfig)
+		require.Equal(t, config.Default(), cfg)
+	})
+
+	t.Run("Precedence of file, env and flags", func(t *testing.T) {
+		// Given
+		path := writeFile(t, "config.yaml", "server_address: \":7000\"\nloader_reload_interval: 1m\nshutdown_timeout: 3s\nstorer_flush_interval: 2s\n")
+		vars := map[string]string{
+			config.EnvConfigFile: path,
+			"VEHICLES_SERVER_ADDRESS": ":7001",
+			"VEHICLES_LOADER_RELOAD_INTERVAL": "2m",
+			"VEHICLES_REPOSITORY_SQLITE_PATH": "vehicles.db",
+			"VEHICLES_CACHE_SIZE": "10",
+		}
+		args := []string{"-server-address", ":7002", "-cache-size", "20", "--print-config"}
+
+		expectedResult := config.Default()
+		expectedResult.ServerAddress = ":7002"
+		expectedResult.LoaderReloadInterval = 2 * time.Minute
+		expectedResult.ShutdownTimeout = 3 * time.Second
+		expectedResult.StorerFlushInterval = 2 * time.Second
+		expectedResult.RepositorySQLitePath = "vehicles.db"
+		expectedResult.CacheSize = 20
+		// When
+		cfg, printConfig, err := config.Load(args, env(vars))
+		// Then
+		require.NoError(t, err)
+		require.True(t, printConfig)
+		require.Equal(t, expectedResult, cfg)
+	})
+
+	t.Run("JSON config file given by flag", func(t *testing.T) {
+		// Given
+		path := writeFile(t, "config.json", `{"loader_file_path": "vehicles.csv", "server_read_timeout": "1s"}`)
+
+		expectedResult := config.Default()
+		expectedResult.LoaderFilePath = "vehicles.csv"
+		expectedResult.ServerReadTimeout = time.Second
+		// When
+		cfg, _, err := config.Load([]string{"-config", path}, env(nil))
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, cfg)
+	})
+
+	t.Run("Unknown key in config file", func(t *testing.T) {
+		// Given
+		path := writeFile(t, "config.yaml", "server_port: 8080\n")
+		// When
+		_, _, err := config.Load([]string{"-config", path}, env(nil))
+		// Then
+		require.ErrorIs(t, err, config.ErrConfigFile)
+	})
+
+	t.Run("Missing config file", func(t *testing.T) {
+		// When
+		_, _, err := config.Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, env(nil))
+		// Then
+		require.ErrorIs(t, err, config.ErrConfigFile)
+	})
+
+	t.Run("Invalid duration in env", func(t *testing.T) {
+		// When
+		_, _, err := config.Load(nil, env(map[string]string{"VEHICLES_SHUTDOWN_TIMEOUT": "soon"}))
+		// Then
+		require.ErrorIs(t, err, config.ErrConfigInvalid)
+	})
+
+	t.Run("Invalid configuration", func(t *testing.T) {
+		// Given
+		cases := [][]string{
+			{"-server-address", "8080"},
+			{"-loader-file-path", "vehicles.xml"},
+			{"-server-write-timeout", "0s"},
+			{"-loader-reload-interval", "-1s"},
+			{"-log-level", "verbose"},
+			{"-cache-size", "many"},
+			{"-cache-size", "-1"},
+		}
+
+		for _, args := range cases {
+			// When
+			_, _, err := config.Load(args, env(nil))
+			// Then
+			require.ErrorIs(t, err, config.ErrConfigInvalid, args)
+		}
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		// When
+		_, _, err := config.Load([]string{"-h"}, env(nil))
+		// Then
+		require.ErrorIs(t, err, flag.ErrHelp)
+	})
+}
+
+func TestConfig_Print(t *testing.T) {
+	// Given
+	cfg := config.Default()
+
+	expectedOutput := `server_address: :8080
+server_read_timeout: 10s
+server_write_timeout: 30s
+server_idle_timeout: 1m0s
+shutdown_timeout: 15s
+loader_file_path: docs/db/vehicles_100.json
+loader_reload_interval: 5s
+storer_flush_interval: 0s
+repository_sqlite_path: ""
+cache_size: 0
+log_level: INFO
+`
+	// When
+	var b bytes.Buffer
+	err := cfg.Print(&b)
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, expectedOutput, b.String())
+}
@@ -0,0 +1,40 @@
+package internal
+
+import "errors"
+
+// Kinds of the domain errors, every error returned by the repository and the service wraps one of them
+// so that callers can tell how to react without knowing the specific error
+var (
+	// ErrNotFound is the kind of the errors of something that does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is the kind of the errors of a change that conflicts with the current state
+	ErrConflict = errors.New("conflict")
+	// ErrValidation is the kind of the errors of an invalid input
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable is the kind of the errors of a dependency that is temporarily not working
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// NewError is a function that returns an error with its own message that is also of a kind
+// - errors.Is(err, kind) is true, while err.Error() is only the message
+func NewError(kind error, message string) error {
+	return &kindError{kind: kind, message: message}
+}
+
+// kindError is an error with its own message that is also of a kind
+type kindError struct {
+	// kind is the kind of the error
+	kind error
+	// message is the message of the error
+	message string
+}
+
+// Error is a method that returns the message of the error
+func (e *kindError) Error() string {
+	return e.message
+}
+
+// Unwrap is a method that returns the kind of the error
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
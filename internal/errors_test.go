@@ -0,0 +1,44 @@
+package internal_test
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewError(t *testing.T) {
+	// Given
+	err := internal.NewError(internal.ErrNotFound, "repository: vehicle not found")
+	wrapped := fmt.Errorf("service: %w", err)
+
+	// Then
+	require.EqualError(t, err, "repository: vehicle not found")
+	require.ErrorIs(t, wrapped, err)
+	require.ErrorIs(t, wrapped, internal.ErrNotFound)
+	require.False(t, errors.Is(wrapped, internal.ErrValidation))
+}
+
+func TestErrorKinds(t *testing.T) {
+	// Given
+	cases := map[error]error{
+		internal.ErrRepositoryVehicleNotFound:   internal.ErrNotFound,
+		internal.ErrRepositoryVehicleStore:      internal.ErrUnavailable,
+		internal.ErrRepositoryVehiclePending:    internal.ErrConflict,
+		internal.ErrRepositoryRegistrationTaken: internal.ErrConflict,
+		internal.ErrRepositoryDatabase:          internal.ErrUnavailable,
+		internal.ErrServiceNoVehicles:           internal.ErrNotFound,
+		internal.ErrServiceInvalidSearch:        internal.ErrValidation,
+		internal.ErrVehicleFilterInvalid:        internal.ErrValidation,
+		internal.ErrVehicleSortInvalid:          internal.ErrValidation,
+		internal.ErrVehicleMetricInvalid:        internal.ErrValidation,
+		internal.ErrVehicleGroupInvalid:         internal.ErrValidation,
+	}
+
+	for err, kind := range cases {
+		// Then
+		require.ErrorIs(t, err, kind, err.Error())
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/platform/web/response"
+	"net/http"
+	"time"
+)
+
+// HandlerAdmin is a struct with methods that represent handlers for the administration of the application
+type HandlerAdmin struct {
+	// rl is the reloader of the vehicles
+	rl internal.ReloaderVehicle
+	// qc is the checker of the data quality of the vehicles
+	qc internal.QualityCheckerVehicle
+}
+
+// NewHandlerAdmin is a function that returns a new instance of HandlerAdmin
+func NewHandlerAdmin(rl internal.ReloaderVehicle, qc internal.QualityCheckerVehicle) *HandlerAdmin {
+	return &HandlerAdmin{rl: rl, qc: qc}
+}
+
+// ReloadStatusJSON is a struct that represents the outcome of the reloads in JSON format
+type ReloadStatusJSON struct {
+	LastReload  *time.Time `json:"last_reload"`
+	LastAttempt *time.Time `json:"last_attempt"`
+	LastError   *string    `json:"last_error"`
+}
+
+// ReloadStatus returns a handler that returns the outcome of the reloads of the vehicles
+func (h *HandlerAdmin) ReloadStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		s := h.rl.Status()
+
+		// response
+		var data ReloadStatusJSON
+		if !s.LastReload.IsZero() {
+			data.LastReload = &s.LastReload
+		}
+		if !s.LastAttempt.IsZero() {
+			data.LastAttempt = &s.LastAttempt
+		}
+		if s.LastError != nil {
+			msg := s.LastError.Error()
+			data.LastError = &msg
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "reload status",
+			"data":    data,
+		})
+	}
+}
+
+// DataQuality returns a handler that returns the data-quality issues of the records of the vehicles
+// - the file is read again, so records lost in the load, as the ones with duplicate ids, are reported
+func (h *HandlerAdmin) DataQuality() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		rp, err := h.qc.Check()
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "data quality",
+			"data":    loader.NewQualityReportJSON(rp),
+		})
+	}
+}
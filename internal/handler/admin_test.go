@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reloaderVehicleStub is a reloader that returns a fixed status
+type reloaderVehicleStub struct {
+	status internal.ReloadStatus
+}
+
+func (r *reloaderVehicleStub) Status() (s internal.ReloadStatus) {
+	return r.status
+}
+
+func TestHandlerAdmin_ReloadStatus(t *testing.T) {
+	t.Run("Last reload succeeded", func(t *testing.T) {
+		// Given
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		rl := &reloaderVehicleStub{status: internal.ReloadStatus{LastReload: at, LastAttempt: at}}
+		hd := handler.NewHandlerAdmin(rl, nil)
+
+		hdFunc := hd.ReloadStatus()
+
+		expectedBodyOutput := `{"data":{"last_reload":"2024-01-02T03:04:05Z","last_attempt":"2024-01-02T03:04:05Z","last_error":null},"message":"reload status"}`
+		expectedStatusCode := http.StatusOK
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/admin/reload", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Last reload failed", func(t *testing.T) {
+		// Given
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		rl := &reloaderVehicleStub{status: internal.ReloadStatus{
+			LastReload:  at,
+			LastAttempt: at.Add(time.Minute),
+			LastError:   errors.New("unexpected EOF"),
+		}}
+		hd := handler.NewHandlerAdmin(rl, nil)
+
+		hdFunc := hd.ReloadStatus()
+
+		expectedBodyOutput := `{"data":{"last_reload":"2024-01-02T03:04:05Z","last_attempt":"2024-01-02T03:05:05Z","last_error":"unexpected EOF"},"message":"reload status"}`
+		expectedStatusCode := http.StatusOK
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/admin/reload", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+}
+
+// qualityCheckerVehicleStub is a checker that returns a fixed report or error
+type qualityCheckerVehicleStub struct {
+	report internal.QualityReport
+	err    error
+}
+
+func (c *qualityCheckerVehicleStub) Check() (r internal.QualityReport, err error) {
+	return c.report, c.err
+}
+
+func TestHandlerAdmin_DataQuality(t *testing.T) {
+	t.Run("Report the issues of the records", func(t *testing.T) {
+		// Given
+		qc := &qualityCheckerVehicleStub{report: internal.QualityReport{
+			Records: 2,
+			Issues: []internal.QualityIssue{
+				{Record: 2, Id: 1, Field: "id", Kind: internal.QualityIssueDuplicateId, Message: "same id as record 1"},
+			},
+		}}
+		hd := handler.NewHandlerAdmin(nil, qc)
+
+		hdFunc := hd.DataQuality()
+
+		expectedBodyOutput := `{"data":{"records":2,` +
+			`"counts":{"duplicate_id":1,"duplicate_registration":0,"missing_field":0,"out_of_range":0,"unknown_value":0},` +
+			`"issues":[{"record":2,"id":1,"field":"id","kind":"duplicate_id","message":"same id as record 1"}]},` +
+			`"message":"data quality"}`
+		expectedStatusCode := http.StatusOK
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/admin/data-quality", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("File can not be read", func(t *testing.T) {
+		// Given
+		qc := &qualityCheckerVehicleStub{err: errors.New("open vehicles.json: no such file or directory")}
+		hd := handler.NewHandlerAdmin(nil, qc)
+
+		hdFunc := hd.DataQuality()
+
+		expectedStatusCode := http.StatusInternalServerError
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/admin/data-quality", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+	})
+}
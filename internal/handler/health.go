@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/response"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// BuildInfo is a struct that represents the version of the running binary
+type BuildInfo struct {
+	// Module is the path of the main module
+	Module string
+	// Version is the version of the main module, "(devel)" when built from a working tree
+	Version string
+	// GoVersion is the version of the toolchain used to build the binary
+	GoVersion string
+	// Revision is the VCS revision the binary was built from, empty if unknown
+	Revision string
+	// RevisionTime is the time of the VCS revision, zero if unknown
+	RevisionTime time.Time
+	// Modified is true when the working tree had local changes at build time
+	Modified bool
+}
+
+// ReadBuildInfo is a function that returns the version of the running binary from the information embedded by the toolchain
+func ReadBuildInfo() (b BuildInfo) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+
+	b.Module = info.Main.Path
+	b.Version = info.Main.Version
+	b.GoVersion = info.GoVersion
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.time":
+			b.RevisionTime, _ = time.Parse(time.RFC3339, s.Value)
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	return
+}
+
+// HandlerHealth is a struct with methods that represent handlers for the health of the application
+type HandlerHealth struct {
+	// rp is the repository of the vehicles
+	rp internal.RepositoryReadVehicle
+	// rl is the reloader of the vehicles
+	rl internal.ReloaderVehicle
+	// build is the version of the running binary
+	build BuildInfo
+}
+
+// NewHandlerHealth is a function that returns a new instance of HandlerHealth
+func NewHandlerHealth(rp internal.RepositoryReadVehicle, rl internal.ReloaderVehicle, build BuildInfo) *HandlerHealth {
+	return &HandlerHealth{rp: rp, rl: rl, build: build}
+}
+
+// ReadyChecksJSON is a struct that represents the checks of the readiness in JSON format
+type ReadyChecksJSON struct {
+	DataLoaded       bool `json:"data_loaded"`
+	RepositoryFilled bool `json:"repository_filled"`
+	LastReloadOk     bool `json:"last_reload_ok"`
+}
+
+// VersionJSON is a struct that represents the version of the application and its dataset in JSON format
+type VersionJSON struct {
+	Module       string     `json:"module"`
+	Version      string     `json:"version"`
+	GoVersion    string     `json:"go_version"`
+	Revision     *string    `json:"revision"`
+	RevisionTime *time.Time `json:"revision_time"`
+	Modified     bool       `json:"modified"`
+	Records      int        `json:"records"`
+	Checksum     *string    `json:"checksum"`
+}
+
+// Healthz returns a handler that reports that the process is alive
+func (h *HandlerHealth) Healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "alive",
+		})
+	}
+}
+
+// Readyz returns a handler that reports whether the application can serve the vehicles
+// - ready: the data was loaded, the repository is not empty and the last reload succeeded
+func (h *HandlerHealth) Readyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		s := h.rl.Status()
+		n, err := h.rp.Count()
+
+		checks := ReadyChecksJSON{
+			DataLoaded:       !s.LastReload.IsZero(),
+			RepositoryFilled: err == nil && n > 0,
+			LastReloadOk:     s.LastError == nil,
+		}
+
+		// response
+		if !checks.DataLoaded || !checks.RepositoryFilled || !checks.LastReloadOk {
+			response.JSON(w, http.StatusServiceUnavailable, map[string]any{
+				"message": "not ready",
+				"data":    checks,
+			})
+			return
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "ready",
+			"data":    checks,
+		})
+	}
+}
+
+// Version returns a handler that returns the version of the application and of the loaded dataset
+func (h *HandlerHealth) Version() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		s := h.rl.Status()
+		n, err := h.rp.Count()
+		if err != nil {
+			response.InternalError(w, r, err)
+			return
+		}
+
+		// response
+		data := VersionJSON{
+			Module:    h.build.Module,
+			Version:   h.build.Version,
+			GoVersion: h.build.GoVersion,
+			Modified:  h.build.Modified,
+			Records:   n,
+		}
+		if h.build.Revision != "" {
+			data.Revision = &h.build.Revision
+		}
+		if !h.build.RevisionTime.IsZero() {
+			data.RevisionTime = &h.build.RevisionTime
+		}
+		if s.Checksum != "" {
+			data.Checksum = &s.Checksum
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "version",
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/repository/repositorytest"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerHealth_Healthz(t *testing.T) {
+	// Given
+	hd := handler.NewHandlerHealth(repositorytest.NewVehicleMapMock(t), &reloaderVehicleStub{}, handler.BuildInfo{})
+
+	hdFunc := hd.Healthz()
+
+	expectedBodyOutput := `{"message":"alive"}`
+	expectedStatusCode := http.StatusOK
+	// When
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	res := httptest.NewRecorder()
+	hdFunc(res, req)
+	// Then
+	require.Equal(t, expectedStatusCode, res.Code)
+	require.JSONEq(t, expectedBodyOutput, res.Body.String())
+}
+
+func TestHandlerHealth_Readyz(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Ready", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.CountFunc = func() (n int, err error) {
+			return 1, nil
+		}
+		rl := &reloaderVehicleStub{status: internal.ReloadStatus{LastReload: at, LastAttempt: at}}
+		hd := handler.NewHandlerHealth(rp, rl, handler.BuildInfo{})
+
+		hdFunc := hd.Readyz()
+
+		expectedBodyOutput := `{"data":{"data_loaded":true,"repository_filled":true,"last_reload_ok":true},"message":"ready"}`
+		expectedStatusCode := http.StatusOK
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Not ready: empty repository and failed reload", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.CountFunc = func() (n int, err error) {
+			return 0, nil
+		}
+		rl := &reloaderVehicleStub{status: internal.ReloadStatus{LastReload: at, LastAttempt: at, LastError: errors.New("unexpected EOF")}}
+		hd := handler.NewHandlerHealth(rp, rl, handler.BuildInfo{})
+
+		hdFunc := hd.Readyz()
+
+		expectedBodyOutput := `{"data":{"data_loaded":true,"repository_filled":false,"last_reload_ok":false},"message":"not ready"}`
+		expectedStatusCode := http.StatusServiceUnavailable
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Not ready: data not loaded", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.CountFunc = func() (n int, err error) {
+			return 0, nil
+		}
+		hd := handler.NewHandlerHealth(rp, &reloaderVehicleStub{}, handler.BuildInfo{})
+
+		hdFunc := hd.Readyz()
+
+		expectedStatusCode := http.StatusServiceUnavailable
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+	})
+}
+
+func TestHandlerHealth_Version(t *testing.T) {
+	// Given
+	rp := repositorytest.NewVehicleMapMock(t)
+	rp.CountFunc = func() (n int, err error) {
+		return 2, nil
+	}
+	rl := &reloaderVehicleStub{status: internal.ReloadStatus{Records: 2, Checksum: "sha256:abc"}}
+	build := handler.BuildInfo{
+		Module:       "app",
+		Version:      "v1.2.3",
+		GoVersion:    "go1.21.2",
+		Revision:     "0123abc",
+		RevisionTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	hd := handler.NewHandlerHealth(rp, rl, build)
+
+	hdFunc := hd.Version()
+
+	expectedBodyOutput := `{"data":{"module":"app","version":"v1.2.3","go_version":"go1.21.2","revision":"0123abc","revision_time":"2024-01-02T03:04:05Z","modified":false,"records":2,"checksum":"sha256:abc"},"message":"version"}`
+	expectedStatusCode := http.StatusOK
+	// When
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	res := httptest.NewRecorder()
+	hdFunc(res, req)
+	// Then
+	require.Equal(t, expectedStatusCode, res.Code)
+	require.JSONEq(t, expectedBodyOutput, res.Body.String())
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/logging"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrorStatus is a function that returns the status code of an error returned by the service, given by its kind
+func ErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, internal.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, internal.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, internal.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, internal.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// unavailableErrors are the unavailable errors whose message is told to clients, others are only "service unavailable"
+var unavailableErrors = []error{
+	internal.ErrRepositoryVehicleStore,
+	internal.ErrRepositoryDatabase,
+}
+
+// writeServiceError writes the problem of an error returned by the service, with the status code given by ErrorStatus
+// - the message of the error is the detail, except for server errors that are logged and only described by their kind,
+// since their cause may tell about paths, files or queries
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch code := ErrorStatus(err); code {
+	case http.StatusInternalServerError:
+		response.InternalError(w, r, err)
+	case http.StatusServiceUnavailable:
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelWarn, "service unavailable", slog.String("error", err.Error()))
+		detail := "service unavailable"
+		for _, e := range unavailableErrors {
+			if errors.Is(err, e) {
+				detail = errorDetail(e)
+				break
+			}
+		}
+		response.Problem(w, r, code, detail)
+	default:
+		var ve *internal.ValidationError
+		if errors.As(err, &ve) {
+			fields := make([]response.FieldError, len(ve.Fields))
+			for i, f := range ve.Fields {
+				fields[i] = response.FieldError{Field: f.Field, Message: f.Message}
+			}
+			response.Problem(w, r, code, errorDetail(ve.Err), fields...)
+			return
+		}
+		response.Problem(w, r, code, errorDetail(err))
+	}
+}
+
+// errorDetail is a function that returns the message of an error without the layer that defines it, like "repository: "
+func errorDetail(err error) string {
+	msg := err.Error()
+	if layer, detail, ok := strings.Cut(msg, ": "); ok && !strings.ContainsAny(layer, " :") {
+		return detail
+	}
+	return msg
+}
+
+// writeQueryError writes the problem of an invalid query, with the invalid parameters as field errors
+func writeQueryError(w http.ResponseWriter, r *http.Request, err error) {
+	var ve *internal.ValidationError
+	if errors.As(err, &ve) {
+		writeServiceError(w, r, ve)
+		return
+	}
+
+	var qe *QueryError
+	if !errors.As(err, &qe) {
+		response.Problem(w, r, http.StatusBadRequest, "invalid query")
+		return
+	}
+
+	message := qe.Message
+	if message == "" && qe.Err != nil {
+		message = qe.Err.Error()
+	}
+	response.Problem(w, r, http.StatusBadRequest, qe.Error(), response.FieldError{Field: qe.Parameter, Message: message})
+}
+
+// writeParamError writes the problem of an invalid path or query parameter that must be of some kind, like "an integer"
+func writeParamError(w http.ResponseWriter, r *http.Request, param, kind string) {
+	response.Problem(w, r, http.StatusBadRequest, "invalid "+param, response.FieldError{Field: param, Message: "must be " + kind})
+}
+
+// writeBodyError writes the problem of a body that can not be decoded, with the field of the wrong type if known
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, request.ErrRequestContentTypeNotJSON):
+		response.Problem(w, r, http.StatusBadRequest, "invalid body", response.FieldError{Field: "Content-Type", Message: "must be application/json"})
+		return
+	}
+
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) && te.Field != "" {
+		response.Problem(w, r, http.StatusBadRequest, "invalid body", response.FieldError{Field: te.Field, Message: "must be " + kindOf(te.Type)})
+		return
+	}
+	response.Problem(w, r, http.StatusBadRequest, "invalid body")
+}
+
+// kindOf is a function that returns the description of the JSON values accepted by a type
+func kindOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return kindOf(t.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	}
+	return "a valid " + t.Kind().String()
+}
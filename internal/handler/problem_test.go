@@ -0,0 +1,156 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/service/servicetest"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorStatus(t *testing.T) {
+	// Given
+	cases := map[error]int{
+		internal.ErrRepositoryVehicleNotFound:                                      http.StatusNotFound,
+		fmt.Errorf("%w: %w", internal.ErrServiceInvalidSearch, errors.New("x")):    http.StatusBadRequest,
+		internal.NewError(internal.ErrConflict, "repository: registration in use"): http.StatusConflict,
+		internal.ErrRepositoryVehicleStore:                                         http.StatusServiceUnavailable,
+		errors.New("unexpected"):                                                   http.StatusInternalServerError,
+	}
+
+	for err, expectedCode := range cases {
+		// When
+		code := handler.ErrorStatus(err)
+		// Then
+		require.Equal(t, expectedCode, code, err.Error())
+	}
+}
+
+func TestHandlerVehicle_ServiceErrors(t *testing.T) {
+	t.Run("Store unavailable on create", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SaveFunc = func(ctx context.Context, v *internal.Vehicle) (err error) {
+			return fmt.Errorf("%w: %w", internal.ErrRepositoryVehicleStore, errors.New("disk full"))
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Create()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Service Unavailable","status":503,"detail":"vehicles could not be stored","instance":"/vehicles"}`
+		expectedStatusCode := http.StatusServiceUnavailable
+		// When
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(`{"brand":"A"}`))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Database unavailable on find", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByIdFunc = func(ctx context.Context, id int) (v internal.Vehicle, err error) {
+			err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, errors.New("SQL logic error: no such table: vehicles"))
+			return
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.FindById()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Service Unavailable","status":503,"detail":"database failed","instance":"/vehicles/1"}`
+		expectedStatusCode := http.StatusServiceUnavailable
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/1", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Unknown unavailable error", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SearchFunc = func(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+			err = fmt.Errorf("%w: open /tmp/vehicles.json: too many open files", internal.ErrUnavailable)
+			return
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Search()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Service Unavailable","status":503,"detail":"service unavailable","instance":"/vehicles"}`
+		expectedStatusCode := http.StatusServiceUnavailable
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Invalid attributes on create", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SaveFunc = func(ctx context.Context, v *internal.Vehicle) (err error) {
+			return &internal.ValidationError{Err: internal.ErrVehicleInvalid, Fields: []internal.FieldError{
+				{Field: "registration", Message: "is required"},
+				{Field: "weight", Message: "must not be negative"},
+			}}
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Create()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid attributes","instance":"/vehicles","errors":[{"field":"registration","message":"is required"},{"field":"weight","message":"must not be negative"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(`{"brand":"A","weight":-1}`))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Invalid find by color and year", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByColorAndYearFunc = func(ctx context.Context, color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+			return nil, internal.ErrServiceInvalidFind
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.FindByColorAndYear()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid find","instance":"/vehicles/color/red/year/1"}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/color/red/year/1", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("color", "red")
+		chiCtx.URLParams.Add("year", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+}
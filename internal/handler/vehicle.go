@@ -2,14 +2,54 @@ package handler
 
 import (
 	"app/internal"
+	"app/internal/loader"
+	"app/platform/web/request"
 	"app/platform/web/response"
-	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// VehiclePatchJSON is a struct that represents a partial update of a vehicle in JSON format
+// - the keys are the ones of loader.VehicleJSON, the format of the vehicles in the API and in the files
+type VehiclePatchJSON struct {
+	Brand           *string  `json:"brand"`
+	Model           *string  `json:"model"`
+	Registration    *string  `json:"registration"`
+	Color           *string  `json:"color"`
+	FabricationYear *int     `json:"year"`
+	Capacity        *int     `json:"passengers"`
+	MaxSpeed        *float64 `json:"max_speed"`
+	FuelType        *string  `json:"fuel_type"`
+	Transmission    *string  `json:"transmission"`
+	Weight          *float64 `json:"weight"`
+	Height          *float64 `json:"height"`
+	Length          *float64 `json:"length"`
+	Width           *float64 `json:"width"`
+}
+
+// VehiclePatch is a method that returns the partial update of the vehicle
+func (v VehiclePatchJSON) VehiclePatch() internal.VehiclePatch {
+	return internal.VehiclePatch{
+		Brand:           v.Brand,
+		Model:           v.Model,
+		Registration:    v.Registration,
+		Color:           v.Color,
+		FabricationYear: v.FabricationYear,
+		Capacity:        v.Capacity,
+		MaxSpeed:        v.MaxSpeed,
+		FuelType:        v.FuelType,
+		Transmission:    v.Transmission,
+		Weight:          v.Weight,
+		DimensionsPatch: internal.DimensionsPatch{
+			Height: v.Height,
+			Length: v.Length,
+			Width:  v.Width,
+		},
+	}
+}
+
 // HandlerVehicle is a struct with methods that represent handlers for vehicles
 type HandlerVehicle struct {
 	// sv is the service that will be used by the handler
@@ -21,60 +61,126 @@ func NewHandlerVehicle(sv internal.ServiceVehicle) *HandlerVehicle {
 	return &HandlerVehicle{sv: sv}
 }
 
-// FindByColorAndYear returns a handler that returns a map of vehicles that match the color and fabrication year
+// FindById returns a handler that returns the vehicle with the id
+func (h *HandlerVehicle) FindById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			writeParamError(w, r, "id", "an integer")
+			return
+		}
+
+		// process
+		v, err := h.sv.FindById(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "vehicle found",
+			"data":    loader.NewVehicleJSON(v),
+		})
+	}
+}
+
+// FindByRegistration returns a handler that returns the vehicle with the registration, ignoring case and whitespace
+func (h *HandlerVehicle) FindByRegistration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		registration := chi.URLParam(r, "registration")
+
+		// process
+		v, err := h.sv.FindByRegistration(r.Context(), registration)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "vehicle found",
+			"data":    loader.NewVehicleJSON(v),
+		})
+	}
+}
+
+// FindByColorAndYear returns a handler that returns a page of the vehicles that match the color and fabrication year
 func (h *HandlerVehicle) FindByColorAndYear() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
+		if err := checkQueryParameters(r.URL.Query(), ListQueryParameters...); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
 		color := chi.URLParam(r, "color")
 		year, err := strconv.Atoi(chi.URLParam(r, "year"))
 		if err != nil {
-			response.Error(w, http.StatusBadRequest, "invalid year")
+			writeParamError(w, r, "year", "an integer")
+			return
+		}
+		lq, err := ParseVehicleListQuery(r.URL.Query())
+		if err != nil {
+			writeQueryError(w, r, err)
 			return
 		}
 
 		// process
-		v, err := h.sv.FindByColorAndYear(color, year)
+		v, err := h.sv.FindByColorAndYear(r.Context(), color, year)
 		if err != nil {
-			response.Error(w, http.StatusInternalServerError, "internal error")
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// response
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "vehicles found",
-			"data": v,
-		})
+		writeVehicleList(w, r, v, lq, "vehicles found")
 	}
 }
 
-// FindByBrandAndYearRange returns a handler that returns a map of vehicles that match the brand and a range of fabrication years
+// FindByBrandAndYearRange returns a handler that returns a page of the vehicles that match the brand and a range of fabrication years
 func (h *HandlerVehicle) FindByBrandAndYearRange() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
+		if err := checkQueryParameters(r.URL.Query(), ListQueryParameters...); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
 		brand := chi.URLParam(r, "brand")
 		startYear, err := strconv.Atoi(chi.URLParam(r, "start_year"))
 		if err != nil {
-			response.Error(w, http.StatusBadRequest, "invalid start_year")
+			writeParamError(w, r, "start_year", "an integer")
 			return
 		}
 		endYear, err := strconv.Atoi(chi.URLParam(r, "end_year"))
 		if err != nil {
-			response.Error(w, http.StatusBadRequest, "invalid end_year")
+			writeParamError(w, r, "end_year", "an integer")
+			return
+		}
+		lq, err := ParseVehicleListQuery(r.URL.Query())
+		if err != nil {
+			writeQueryError(w, r, err)
 			return
 		}
 
 		// process
-		v, err := h.sv.FindByBrandAndYearRange(brand, startYear, endYear)
+		v, err := h.sv.FindByBrandAndYearRange(r.Context(), brand, startYear, endYear)
 		if err != nil {
-			response.Error(w, http.StatusInternalServerError, "internal error")
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// response
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "vehicles found",
-			"data": v,
-		})
+		writeVehicleList(w, r, v, lq, "vehicles found")
 	}
 }
 
@@ -82,17 +188,16 @@ func (h *HandlerVehicle) FindByBrandAndYearRange() http.HandlerFunc {
 func (h *HandlerVehicle) AverageMaxSpeedByBrand() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
 		brand := chi.URLParam(r, "brand")
 
 		// process
-		average, err := h.sv.AverageMaxSpeedByBrand(brand)
+		average, err := h.sv.AverageMaxSpeedByBrand(r.Context(), brand)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrServiceNoVehicles):
-				response.Error(w, http.StatusNotFound, "vehicles not found")
-			default:
-				response.Error(w, http.StatusInternalServerError, "internal error")
-			}
+			writeServiceError(w, r, err)
 			return
 		}
 
@@ -108,17 +213,16 @@ func (h *HandlerVehicle) AverageMaxSpeedByBrand() http.HandlerFunc {
 func (h *HandlerVehicle) AverageCapacityByBrand() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
 		brand := chi.URLParam(r, "brand")
 
 		// process
-		average, err := h.sv.AverageCapacityByBrand(brand)
+		average, err := h.sv.AverageCapacityByBrand(r.Context(), brand)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrServiceNoVehicles):
-				response.Error(w, http.StatusNotFound, "vehicles not found")
-			default:
-				response.Error(w, http.StatusInternalServerError, "internal error")
-			}
+			writeServiceError(w, r, err)
 			return
 		}
 
@@ -130,10 +234,14 @@ func (h *HandlerVehicle) AverageCapacityByBrand() http.HandlerFunc {
 	}
 }
 
-// SearchByWeightRange returns a handler that returns a map of vehicles that match the weight range
+// SearchByWeightRange returns a handler that returns a page of the vehicles that match the weight range
 func (h *HandlerVehicle) SearchByWeightRange() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
+		if err := checkQueryParameters(r.URL.Query(), weightQueryParameters...); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
 		var query internal.SearchQuery
 
 		// check if query exists and decode
@@ -142,28 +250,180 @@ func (h *HandlerVehicle) SearchByWeightRange() http.HandlerFunc {
 			var err error
 			query.FromWeight, err = strconv.ParseFloat(r.URL.Query().Get("weight_min"), 64)
 			if err != nil {
-				response.Error(w, http.StatusBadRequest, "invalid weight_min")
+				writeParamError(w, r, "weight_min", "a number")
 				return
 			}
 
 			query.ToWeight, err = strconv.ParseFloat(r.URL.Query().Get("weight_max"), 64)
 			if err != nil {
-				response.Error(w, http.StatusBadRequest, "invalid weight_max")
+				writeParamError(w, r, "weight_max", "a number")
 				return
 			}
 		}
+		lq, err := ParseVehicleListQuery(r.URL.Query())
+		if err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
 
 		// process
-		v, err := h.sv.SearchByWeightRange(query, ok)
+		v, err := h.sv.SearchByWeightRange(r.Context(), query, ok)
 		if err != nil {
-			response.Error(w, http.StatusInternalServerError, "internal error")
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		writeVehicleList(w, r, v, lq, "vehicles found")
+	}
+}
+
+// Search returns a handler that returns a page of the vehicles that match the filter given by the query parameters
+func (h *HandlerVehicle) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		filter, err := ParseVehicleFilter(r.URL.Query(), ListQueryParameters)
+		if err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		lq, err := ParseVehicleListQuery(r.URL.Query())
+		if err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+
+		// process
+		v, err := h.sv.Search(r.Context(), filter)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		writeVehicleList(w, r, v, lq, "vehicles found")
+	}
+}
+
+// Create returns a handler that creates a new vehicle
+func (h *HandlerVehicle) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		var body loader.VehicleJSON
+		if err := request.JSON(r, &body); err != nil {
+			writeBodyError(w, r, err)
+			return
+		}
+
+		// process
+		// - the id is given by the repository, an id in the body is ignored
+		v := internal.Vehicle{VehicleAttributes: body.Vehicle().VehicleAttributes}
+		if err := h.sv.Save(r.Context(), &v); err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, map[string]any{
+			"message": "vehicle created",
+			"data":    loader.NewVehicleJSON(v),
+		})
+	}
+}
+
+// Update returns a handler that replaces all the attributes of a vehicle
+func (h *HandlerVehicle) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			writeParamError(w, r, "id", "an integer")
+			return
+		}
+		var body loader.VehicleJSON
+		if err := request.JSON(r, &body); err != nil {
+			writeBodyError(w, r, err)
+			return
+		}
+
+		// process
+		v := internal.Vehicle{Id: id, VehicleAttributes: body.Vehicle().VehicleAttributes}
+		if err := h.sv.Update(r.Context(), v); err != nil {
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// response
 		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "vehicles found",
-			"data": v,
+			"message": "vehicle updated",
+			"data":    loader.NewVehicleJSON(v),
 		})
 	}
-}
\ No newline at end of file
+}
+
+// Patch returns a handler that updates only the given attributes of a vehicle
+func (h *HandlerVehicle) Patch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			writeParamError(w, r, "id", "an integer")
+			return
+		}
+		var body VehiclePatchJSON
+		if err := request.JSON(r, &body); err != nil {
+			writeBodyError(w, r, err)
+			return
+		}
+
+		// process
+		v, err := h.sv.Patch(r.Context(), id, body.VehiclePatch())
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "vehicle updated",
+			"data":    loader.NewVehicleJSON(v),
+		})
+	}
+}
+
+// Delete returns a handler that deletes a vehicle
+func (h *HandlerVehicle) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		if err := checkQueryParameters(r.URL.Query()); err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			writeParamError(w, r, "id", "an integer")
+			return
+		}
+
+		// process
+		if err := h.sv.Delete(r.Context(), id); err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
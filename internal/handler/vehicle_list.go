@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/platform/logging"
+	"app/platform/web/response"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultListLimit is the number of vehicles of a page when no limit is given
+	DefaultListLimit = 100
+	// MaxListLimit is the maximum number of vehicles of a page
+	MaxListLimit = 1000
+)
+
+const (
+	// ListFormatJSON is the format of a list as a JSON object with the page and its metadata
+	ListFormatJSON = "json"
+	// ListFormatCSV is the format of a list as a CSV file with header, streamed
+	ListFormatCSV = "csv"
+	// ListFormatNDJSON is the format of a list as a JSON value per line, streamed
+	ListFormatNDJSON = "ndjson"
+)
+
+// listFormat is a struct that represents a format of a list
+type listFormat struct {
+	// name is the name of the format in the format query parameter
+	name string
+	// mediaType is the media type of the format in the Accept header
+	mediaType string
+}
+
+// listFormats are the formats of a list, in order of preference for the negotiation
+var listFormats = []listFormat{
+	{name: ListFormatJSON, mediaType: "application/json"},
+	{name: ListFormatCSV, mediaType: "text/csv"},
+	{name: ListFormatNDJSON, mediaType: response.NDJSONContentType},
+}
+
+var (
+	// errCursorInvalid is an error that represents a cursor that was not returned by the api
+	errCursorInvalid = errors.New("handler: invalid cursor")
+)
+
+// VehicleListQuery is a struct that represents the order and page requested for a list of vehicles
+type VehicleListQuery struct {
+	// Sorts is the order of the vehicles, in order of priority
+	Sorts []internal.VehicleSort
+	// Page is the page of the ordered vehicles
+	Page internal.VehiclePage
+	// Format is the format of the list, negotiated by the Accept header of the request when empty
+	Format string
+}
+
+// ListMetaJSON is a struct that represents the metadata of a page of a list in JSON format
+type ListMetaJSON struct {
+	Total      int     `json:"total"`
+	Limit      int     `json:"limit"`
+	Offset     int     `json:"offset"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// ParseVehicleListQuery is a function that returns the order and page described by the query parameters
+// - sort=field,-field: ascending by field, descending if prefixed with "-". By id when not given
+// - limit: number of vehicles, DefaultListLimit when not given, up to MaxListLimit
+// - offset: number of vehicles skipped
+// - cursor: next_cursor of a previous page, replaces offset
+// - format: json, csv or ndjson, takes precedence over the Accept header
+func ParseVehicleListQuery(query url.Values) (lq VehicleListQuery, err error) {
+	// sort
+	if query.Has("sort") {
+		for _, name := range strings.Split(query.Get("sort"), ",") {
+			var st internal.VehicleSort
+			var ok bool
+			name, st.Descending = strings.CutPrefix(strings.TrimSpace(name), "-")
+			st.Field, ok = internal.ParseVehicleField(name)
+			if !ok {
+				err = &QueryError{Parameter: "sort", Err: internal.ErrVehicleSortInvalid, Message: fmt.Sprintf("unknown field %q", name)}
+				return
+			}
+			lq.Sorts = append(lq.Sorts, st)
+		}
+	}
+
+	// limit
+	lq.Page.Limit = DefaultListLimit
+	if query.Has("limit") {
+		lq.Page.Limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || lq.Page.Limit < 1 || lq.Page.Limit > MaxListLimit {
+			err = &QueryError{Parameter: "limit", Err: err, Message: fmt.Sprintf("must be an integer between 1 and %d", MaxListLimit)}
+			return
+		}
+	}
+
+	// offset
+	switch {
+	case query.Has("cursor"):
+		lq.Page.Offset, err = decodeCursor(query.Get("cursor"))
+		if err != nil {
+			err = &QueryError{Parameter: "cursor", Err: err, Message: "must be the next_cursor of a previous page"}
+			return
+		}
+	case query.Has("offset"):
+		lq.Page.Offset, err = strconv.Atoi(query.Get("offset"))
+		if err != nil || lq.Page.Offset < 0 {
+			err = &QueryError{Parameter: "offset", Err: err, Message: "must be a non-negative integer"}
+			return
+		}
+	}
+
+	// format
+	if query.Has("format") {
+		lq.Format = query.Get("format")
+		if !slices.ContainsFunc(listFormats, func(f listFormat) bool { return f.name == lq.Format }) {
+			err = &QueryError{Parameter: "format", Message: fmt.Sprintf("must be one of %s, %s or %s", ListFormatJSON, ListFormatCSV, ListFormatNDJSON)}
+			return
+		}
+	}
+
+	return
+}
+
+// negotiateListFormat is a function that returns the format of a list accepted by the request, "" if none is
+func negotiateListFormat(r *http.Request) (format string) {
+	offers := make([]string, len(listFormats))
+	for i, f := range listFormats {
+		offers[i] = f.mediaType
+	}
+
+	mediaType := response.Negotiate(r, offers...)
+	for _, f := range listFormats {
+		if f.mediaType == mediaType {
+			format = f.name
+		}
+	}
+	return
+}
+
+// writeVehicleList is a function that writes the requested page of the vehicles ordered, with its metadata
+// - json: the page in an object with its metadata
+// - csv and ndjson: the page streamed, with the metadata in the X-Total-Count and X-Next-Cursor headers.
+// Without a limit, every vehicle is written
+// - only the response is streamed: the vehicles are held in memory to be sorted and counted,
+// so the memory of an export grows with the number of vehicles found
+func writeVehicleList(w http.ResponseWriter, r *http.Request, v map[int]internal.Vehicle, lq VehicleListQuery, message string) {
+	// format
+	w.Header().Add("Vary", "Accept")
+	if lq.Format == "" {
+		lq.Format = negotiateListFormat(r)
+		if lq.Format == "" {
+			response.Problem(w, r, http.StatusNotAcceptable, "acceptable media types are application/json, text/csv and application/x-ndjson")
+			return
+		}
+	}
+	if lq.Format != ListFormatJSON && !r.URL.Query().Has("limit") {
+		lq.Page.Limit = 0
+	}
+
+	sorted, err := internal.SortVehicles(v, lq.Sorts)
+	if err != nil {
+		response.Problem(w, r, http.StatusBadRequest, "invalid sort", response.FieldError{Field: "sort", Message: err.Error()})
+		return
+	}
+	page := internal.PaginateVehicles(sorted, lq.Page)
+
+	meta := ListMetaJSON{
+		Total:  len(sorted),
+		Limit:  lq.Page.Limit,
+		Offset: lq.Page.Offset,
+	}
+	if next := lq.Page.Offset + len(page); len(page) > 0 && next < len(sorted) {
+		cursor := encodeCursor(next)
+		meta.NextCursor = &cursor
+	}
+
+	switch lq.Format {
+	case ListFormatCSV, ListFormatNDJSON:
+		w.Header().Set("X-Total-Count", strconv.Itoa(meta.Total))
+		if meta.NextCursor != nil {
+			w.Header().Set("X-Next-Cursor", *meta.NextCursor)
+		}
+		err = streamVehicles(w, lq.Format, page)
+		if err != nil {
+			// the status code was already sent, the client sees a truncated response
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelWarn, "stream interrupted", slog.String("error", err.Error()))
+		}
+	default:
+		data := make([]loader.VehicleJSON, len(page))
+		for i, vh := range page {
+			data[i] = loader.NewVehicleJSON(vh)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": message,
+			"data":    data,
+			"meta":    meta,
+		})
+	}
+}
+
+// streamVehicles is a function that writes the vehicles one at a time in a streamed format
+// - csv: a column per field, named as in VehicleFields
+// - ndjson: a vehicle per line, as in the data of the json format, with the names of the columns of csv
+func streamVehicles(w http.ResponseWriter, format string, v []internal.Vehicle) (err error) {
+	if format == ListFormatNDJSON {
+		s := response.NewNDJSONStream(w, http.StatusOK)
+		for _, vh := range v {
+			err = s.Encode(loader.NewVehicleJSON(vh))
+			if err != nil {
+				return
+			}
+		}
+		err = s.Flush()
+		return
+	}
+
+	header := make([]string, len(internal.VehicleFields))
+	for i, f := range internal.VehicleFields {
+		header[i] = string(f)
+	}
+	s, err := response.NewCSVStream(w, http.StatusOK, header)
+	if err != nil {
+		return
+	}
+	record := make([]string, len(internal.VehicleFields))
+	for _, vh := range v {
+		for i, f := range internal.VehicleFields {
+			if f.Numeric() {
+				record[i] = strconv.FormatFloat(vh.Number(f), 'f', -1, 64)
+			} else {
+				record[i] = vh.String(f)
+			}
+		}
+		err = s.Write(record)
+		if err != nil {
+			return
+		}
+	}
+	err = s.Flush()
+	return
+}
+
+// encodeCursor is a function that returns the opaque cursor of an offset
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// decodeCursor is a function that returns the offset of an opaque cursor
+func decodeCursor(cursor string) (offset int, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		err = errCursorInvalid
+		return
+	}
+	value, ok := strings.CutPrefix(string(b), "offset:")
+	if !ok {
+		err = errCursorInvalid
+		return
+	}
+	offset, err = strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		err = errCursorInvalid
+		return
+	}
+	return
+}
@@ -0,0 +1,181 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/loader"
+	"app/internal/service/servicetest"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVehicleListQuery(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		// Given
+		query := url.Values{}
+
+		expectedResult := handler.VehicleListQuery{Page: internal.VehiclePage{Limit: handler.DefaultListLimit}}
+		// When
+		result, err := handler.ParseVehicleListQuery(query)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Sort, limit and offset", func(t *testing.T) {
+		// Given
+		query := url.Values{"sort": {"brand,-year"}, "limit": {"5"}, "offset": {"10"}}
+
+		expectedResult := handler.VehicleListQuery{
+			Sorts: []internal.VehicleSort{
+				{Field: internal.VehicleFieldBrand},
+				{Field: internal.VehicleFieldFabricationYear, Descending: true},
+			},
+			Page: internal.VehiclePage{Offset: 10, Limit: 5},
+		}
+		// When
+		result, err := handler.ParseVehicleListQuery(query)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		// Given
+		cases := map[string]url.Values{
+			"invalid sort":   {"sort": {"wheels"}},
+			"invalid limit":  {"limit": {"0"}},
+			"invalid offset": {"offset": {"-1"}},
+			"invalid cursor": {"cursor": {"not a cursor"}},
+			"invalid format": {"format": {"xml"}},
+		}
+
+		for expectedError, query := range cases {
+			// When
+			_, err := handler.ParseVehicleListQuery(query)
+			// Then
+			require.EqualError(t, err, expectedError)
+		}
+	})
+}
+
+func TestHandlerVehicle_Search_Pagination(t *testing.T) {
+	// Given
+	sv := servicetest.NewVehicleDefaultMock(t)
+	sv.SearchFunc = func(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+		return map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{FabricationYear: 2001}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{FabricationYear: 2003}},
+			3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{FabricationYear: 2002}},
+		}, nil
+	}
+	hd := handler.NewHandlerVehicle(sv)
+
+	hdFunc := hd.Search()
+
+	type body struct {
+		Data []loader.VehicleJSON `json:"data"`
+		Meta handler.ListMetaJSON `json:"meta"`
+	}
+
+	// When
+	req := httptest.NewRequest(http.MethodGet, "/vehicles?sort=-year&limit=2", nil)
+	res := httptest.NewRecorder()
+	hdFunc(res, req)
+	var first body
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &first))
+
+	req = httptest.NewRequest(http.MethodGet, "/vehicles?sort=-year&limit=2&cursor="+*first.Meta.NextCursor, nil)
+	res = httptest.NewRecorder()
+	hdFunc(res, req)
+	var second body
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &second))
+
+	// Then
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, []int{2, 3}, []int{first.Data[0].Id, first.Data[1].Id})
+	require.Equal(t, 3, first.Meta.Total)
+	require.Len(t, second.Data, 1)
+	require.Equal(t, 1, second.Data[0].Id)
+	require.Equal(t, 2, second.Meta.Offset)
+	require.Nil(t, second.Meta.NextCursor)
+}
+
+func TestHandlerVehicle_Search_Formats(t *testing.T) {
+	// Given
+	sv := servicetest.NewVehicleDefaultMock(t)
+	sv.SearchFunc = func(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+		v = make(map[int]internal.Vehicle)
+		for id := 1; id <= handler.DefaultListLimit+1; id++ {
+			v[id] = internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Ka, Street", FabricationYear: 2001, Weight: 1.5}}
+		}
+		return
+	}
+	hd := handler.NewHandlerVehicle(sv)
+
+	hdFunc := hd.Search()
+
+	t.Run("CSV by the Accept header, every vehicle without a limit", func(t *testing.T) {
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles", nil)
+		req.Header.Set("Accept", "text/csv")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		lines := strings.Split(strings.TrimSuffix(res.Body.String(), "\n"), "\n")
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "text/csv; charset=utf-8", res.Header().Get("Content-Type"))
+		require.Equal(t, "Accept", res.Header().Get("Vary"))
+		require.Equal(t, "101", res.Header().Get("X-Total-Count"))
+		require.Len(t, lines, handler.DefaultListLimit+2)
+		require.Equal(t, "id,brand,model,registration,color,year,passengers,max_speed,fuel_type,transmission,weight,height,length,width", lines[0])
+		require.Equal(t, `1,Ford,"Ka, Street",,,2001,0,0,,,1.5,0,0,0`, lines[1])
+	})
+
+	t.Run("NDJSON by the format parameter, with a limit", func(t *testing.T) {
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?format=ndjson&limit=2", nil)
+		req.Header.Set("Accept", "text/csv")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		lines := strings.Split(strings.TrimSuffix(res.Body.String(), "\n"), "\n")
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Equal(t, "application/x-ndjson", res.Header().Get("Content-Type"))
+		require.Equal(t, "101", res.Header().Get("X-Total-Count"))
+		require.NotEmpty(t, res.Header().Get("X-Next-Cursor"))
+		require.Len(t, lines, 2)
+		require.JSONEq(t, `{"id":2,"brand":"Ford","model":"Ka, Street","registration":"","color":"","year":2001,"passengers":0,`+
+			`"max_speed":0,"fuel_type":"","transmission":"","weight":1.5,"height":0,"length":0,"width":0}`, lines[1])
+	})
+
+	t.Run("Not acceptable", func(t *testing.T) {
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles", nil)
+		req.Header.Set("Accept", "application/xml")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, http.StatusNotAcceptable, res.Code)
+		require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?format=xml", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		expectedBody := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid format","instance":"/vehicles",` +
+			`"errors":[{"field":"format","message":"must be one of json, csv or ndjson"}]}`
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		require.JSONEq(t, expectedBody, res.Body.String())
+	})
+}
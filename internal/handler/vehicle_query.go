@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"app/internal"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryError is an error that represents an invalid query parameter
+type QueryError struct {
+	// Parameter is the name of the query parameter
+	Parameter string
+	// Err is the underlying error
+	Err error
+	// Message is the description of why the value is invalid, for clients. Err is used when empty
+	Message string
+}
+
+// Error is a method that returns the error message
+func (e *QueryError) Error() string {
+	return "invalid " + e.Parameter
+}
+
+// Unwrap is a method that returns the underlying error
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// filterOperatorSuffixes are the suffixes of the query parameters for range operators
+var filterOperatorSuffixes = map[string]internal.FilterOperator{
+	"_gt":  internal.FilterOperatorGt,
+	"_gte": internal.FilterOperatorGte,
+	"_lt":  internal.FilterOperatorLt,
+	"_lte": internal.FilterOperatorLte,
+}
+
+var (
+	// ListQueryParameters are the query parameters of the order, page and format of the lists of vehicles
+	ListQueryParameters = []string{"limit", "offset", "cursor", "sort", "format"}
+	// StatsQueryParameters are the query parameters of the groups and metrics of the statistics of the vehicles
+	StatsQueryParameters = []string{"group_by", "metrics"}
+	// weightQueryParameters are the query parameters of the search by weight range, besides the ones of the list
+	weightQueryParameters = append([]string{"weight_min", "weight_max"}, ListQueryParameters...)
+)
+
+// checkQueryParameters is a function that returns a QueryError for the first query parameter, by name, that is not one of parameters
+func checkQueryParameters(query url.Values, parameters ...string) (err error) {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !slices.Contains(parameters, key) {
+			err = &QueryError{Parameter: key, Err: internal.ErrVehicleFilterInvalid, Message: "unknown parameter"}
+			return
+		}
+	}
+	return
+}
+
+// ParseVehicleFilter is a function that returns the filter described by the query parameters
+// - field=value: equality
+// - field=in:value1,value2: set membership
+// - field_gt, field_gte, field_lt, field_lte=value: ranges (numeric fields)
+// Values are trimmed, and parameters that are neither a condition nor one of parameters, the other parameters of the
+// endpoint like ListQueryParameters, are invalid.
+// The values are checked with the rules of the attributes of their field, and the ranges must not be empty,
+// returning a ValidationError wrapping ErrVehicleFilterInvalid with the invalid parameters
+func ParseVehicleFilter(query url.Values, parameters []string) (f internal.VehicleFilter, err error) {
+	// sorted for a deterministic order of the conditions
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var vl internal.Validator
+	var bounds []filterBound
+	for _, key := range keys {
+		if slices.Contains(parameters, key) {
+			continue
+		}
+
+		// field and operator
+		field, ok := internal.ParseVehicleField(key)
+		operator := internal.FilterOperatorEq
+		if !ok {
+			for suffix, op := range filterOperatorSuffixes {
+				name, found := strings.CutSuffix(key, suffix)
+				if !found {
+					continue
+				}
+				if field, ok = internal.ParseVehicleField(name); ok {
+					operator = op
+					break
+				}
+			}
+			if !ok {
+				err = &QueryError{Parameter: key, Err: internal.ErrVehicleFilterInvalid, Message: "unknown parameter"}
+				return
+			}
+		}
+
+		for _, raw := range query[key] {
+			// values
+			c := internal.VehicleCondition{Field: field, Operator: operator}
+			values := []string{raw}
+			if list, found := strings.CutPrefix(raw, "in:"); found && operator == internal.FilterOperatorEq {
+				c.Operator = internal.FilterOperatorIn
+				values = strings.Split(list, ",")
+			}
+
+			for _, value := range values {
+				value = strings.TrimSpace(value)
+				if !field.Numeric() {
+					c.Strings = append(c.Strings, value)
+					continue
+				}
+				n, parseErr := strconv.ParseFloat(value, 64)
+				if parseErr != nil {
+					err = &QueryError{Parameter: key, Err: parseErr, Message: "must be a number"}
+					return
+				}
+				c.Numbers = append(c.Numbers, n)
+			}
+
+			if validateErr := c.Validate(); validateErr != nil {
+				err = &QueryError{Parameter: key, Err: validateErr}
+				return
+			}
+			vl.Condition(key, c)
+			if c.Operator != internal.FilterOperatorEq && c.Operator != internal.FilterOperatorIn {
+				bounds = append(bounds, filterBound{parameter: key, condition: c})
+			}
+			f.Conditions = append(f.Conditions, c)
+		}
+	}
+
+	checkFilterBounds(&vl, bounds)
+	err = vl.Err(internal.ErrVehicleFilterInvalid)
+	return
+}
+
+// filterBound is a struct that represents a range condition and its query parameter
+type filterBound struct {
+	parameter string
+	condition internal.VehicleCondition
+}
+
+// checkFilterBounds is a function that checks that the lower bounds of each field are below its upper bounds
+// - the upper bound is the invalid parameter
+func checkFilterBounds(vl *internal.Validator, bounds []filterBound) {
+	for _, upper := range bounds {
+		if upper.condition.Operator != internal.FilterOperatorLt && upper.condition.Operator != internal.FilterOperatorLte {
+			continue
+		}
+		for _, lower := range bounds {
+			if lower.condition.Field != upper.condition.Field ||
+				(lower.condition.Operator != internal.FilterOperatorGt && lower.condition.Operator != internal.FilterOperatorGte) {
+				continue
+			}
+
+			lo, hi := lower.condition.Numbers[0], upper.condition.Numbers[0]
+			if lower.condition.Operator == internal.FilterOperatorGte && upper.condition.Operator == internal.FilterOperatorLte {
+				vl.Check(lo <= hi, upper.parameter, "must not be less than "+lower.parameter)
+			} else {
+				vl.Check(lo < hi, upper.parameter, "must be greater than "+lower.parameter)
+			}
+		}
+	}
+}
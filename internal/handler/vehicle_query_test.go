@@ -0,0 +1,154 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/service/servicetest"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVehicleFilter(t *testing.T) {
+	t.Run("Equality, ranges and set membership", func(t *testing.T) {
+		// Given
+		query := url.Values{
+			"brand":     {"Ford"},
+			"year_gte":  {"2000"},
+			"height_lt": {"1.5"},
+			"fuel_type": {"in:diesel, biodiesel"},
+			"limit":     {"10"},
+		}
+
+		expectedResult := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{"Ford"}},
+			{Field: internal.VehicleFieldFuelType, Operator: internal.FilterOperatorIn, Strings: []string{"diesel", "biodiesel"}},
+			{Field: internal.VehicleFieldHeight, Operator: internal.FilterOperatorLt, Numbers: []float64{1.5}},
+			{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorGte, Numbers: []float64{2000}},
+		}}
+		// When
+		result, err := handler.ParseVehicleFilter(query, handler.ListQueryParameters)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Invalid number", func(t *testing.T) {
+		// Given
+		query := url.Values{"year_gte": {"two thousand"}}
+
+		// When
+		_, err := handler.ParseVehicleFilter(query, handler.ListQueryParameters)
+		// Then
+		require.EqualError(t, err, "invalid year_gte")
+	})
+
+	t.Run("Unknown parameter", func(t *testing.T) {
+		// Given
+		cases := []string{"colour", "year_gt3", "brand_between"}
+
+		for _, key := range cases {
+			// When
+			_, err := handler.ParseVehicleFilter(url.Values{key: {"red"}}, handler.ListQueryParameters)
+			// Then
+			var queryErr *handler.QueryError
+			require.ErrorAs(t, err, &queryErr, key)
+			require.Equal(t, key, queryErr.Parameter)
+			require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+		}
+	})
+
+	t.Run("Values checked as the attributes of their field", func(t *testing.T) {
+		// Given
+		cases := map[string]url.Values{
+			"weight_gte":   {"weight_gte": {"-5"}},
+			"fuel_type":    {"fuel_type": {"in:diesel, plutonium"}},
+			"transmission": {"transmission": {"cvt"}},
+			"year":         {"year": {"1492"}},
+			"passengers":   {"passengers": {"2.5"}},
+		}
+
+		for parameter, query := range cases {
+			// When
+			_, err := handler.ParseVehicleFilter(query, handler.ListQueryParameters)
+			// Then
+			var validationErr *internal.ValidationError
+			require.ErrorAs(t, err, &validationErr, parameter)
+			require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+			require.Len(t, validationErr.Fields, 1, parameter)
+			require.Equal(t, parameter, validationErr.Fields[0].Field)
+		}
+	})
+
+	t.Run("Empty ranges", func(t *testing.T) {
+		// Given
+		cases := map[string]bool{
+			"year_gte=2010&year_lte=2000": false,
+			"year_gte=2000&year_lte=2000": true,
+			"year_gt=2000&year_lte=2000":  false,
+			"weight_gt=10&weight_lt=10":   false,
+			"weight_gt=10&height_lt=5":    true,
+		}
+
+		for raw, expectedValid := range cases {
+			query, _ := url.ParseQuery(raw)
+			// When
+			_, err := handler.ParseVehicleFilter(query, handler.ListQueryParameters)
+			// Then
+			require.Equal(t, expectedValid, err == nil, raw)
+			if !expectedValid {
+				require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid, raw)
+			}
+		}
+	})
+
+	t.Run("Range over a string field", func(t *testing.T) {
+		// Given
+		query := url.Values{"brand_gte": {"A"}}
+
+		// When
+		_, err := handler.ParseVehicleFilter(query, handler.ListQueryParameters)
+		// Then
+		require.EqualError(t, err, "invalid brand_gte")
+		require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+	})
+}
+
+func TestHandlerVehicle_QueryParameters(t *testing.T) {
+	t.Run("Parameters of other endpoints are unknown", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+		rt := chi.NewRouter()
+		rt.Get("/vehicles", hd.Search())
+		rt.Get("/vehicles/stats", hd.Stats())
+		rt.Get("/vehicles/weight", hd.SearchByWeightRange())
+		rt.Get("/vehicles/{id}", hd.FindById())
+		rt.Get("/vehicles/color/{color}/year/{year}", hd.FindByColorAndYear())
+		rt.Get("/vehicles/average_speed/brand/{brand}", hd.AverageMaxSpeedByBrand())
+
+		cases := map[string]string{
+			"/vehicles/color/Red/year/2010?foo=1":                "foo",
+			"/vehicles/1?limit=1":                                "limit",
+			"/vehicles/average_speed/brand/A?format=csv":         "format",
+			"/vehicles/weight?weight_min=1&weight_max=2&brand=A": "brand",
+			"/vehicles?group_by=brand&metrics=bogus":             "group_by",
+			"/vehicles/stats?group_by=brand&limit=abc&sort=zzz":  "limit",
+		}
+
+		for target, parameter := range cases {
+			// When
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			res := httptest.NewRecorder()
+			rt.ServeHTTP(res, req)
+			// Then
+			require.Equal(t, http.StatusBadRequest, res.Code, target)
+			require.Contains(t, res.Body.String(), `"errors":[{"field":"`+parameter+`","message":"unknown parameter"}]`, target)
+		}
+		sv.AssertCalls()
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/response"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VehicleStatsRowJSON is a struct that represents the metrics of a group of vehicles in JSON format
+type VehicleStatsRowJSON struct {
+	Group   map[internal.VehicleField]any `json:"group"`
+	Metrics map[string]float64            `json:"metrics"`
+}
+
+// ParseVehicleStatsQuery is a function that returns the group by fields and metrics described by the query parameters
+// - group_by=field,field: fields that group the vehicles, a single group when not given
+// - metrics=aggregate:field,count: metrics of each group, count when not given
+func ParseVehicleStatsQuery(query url.Values) (groupBy []internal.VehicleField, metrics []internal.VehicleMetric, err error) {
+	// group by
+	if value := query.Get("group_by"); value != "" {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			f, ok := internal.ParseVehicleField(name)
+			if !ok {
+				err = &QueryError{Parameter: "group_by", Err: internal.ErrVehicleGroupInvalid, Message: fmt.Sprintf("unknown field %q", name)}
+				return
+			}
+			groupBy = append(groupBy, f)
+		}
+	}
+
+	// metrics
+	value := query.Get("metrics")
+	if value == "" {
+		value = string(internal.VehicleAggregateCount)
+	}
+	for _, name := range strings.Split(value, ",") {
+		m, parseErr := internal.ParseVehicleMetric(name)
+		if parseErr != nil {
+			err = &QueryError{Parameter: "metrics", Err: parseErr}
+			return
+		}
+		metrics = append(metrics, m)
+	}
+
+	return
+}
+
+// Stats returns a handler that returns the metrics of the vehicles that match the filter, grouped by fields
+func (h *HandlerVehicle) Stats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		filter, err := ParseVehicleFilter(r.URL.Query(), StatsQueryParameters)
+		if err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		groupBy, metrics, err := ParseVehicleStatsQuery(r.URL.Query())
+		if err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+
+		// process
+		rows, err := h.sv.Stats(r.Context(), filter, groupBy, metrics)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// response
+		data := make([]VehicleStatsRowJSON, len(rows))
+		for i, row := range rows {
+			data[i] = VehicleStatsRowJSON{Group: row.Group, Metrics: row.Metrics}
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "stats found",
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handler_test
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/service/servicetest"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerVehicle_Stats(t *testing.T) {
+	t.Run("Stats grouped by brand and fuel type", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		var (
+			gotFilter  internal.VehicleFilter
+			gotGroupBy []internal.VehicleField
+			gotMetrics []internal.VehicleMetric
+		)
+		sv.StatsFunc = func(ctx context.Context, filter internal.VehicleFilter, groupBy []internal.VehicleField, metrics []internal.VehicleMetric) (rows []internal.VehicleStatsRow, err error) {
+			gotFilter, gotGroupBy, gotMetrics = filter, groupBy, metrics
+			return []internal.VehicleStatsRow{{
+				Group:   map[internal.VehicleField]any{"brand": "A", "fuel_type": "gas"},
+				Metrics: map[string]float64{"count": 2, "p90:passengers": 4},
+			}}, nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Stats()
+
+		expectedBodyOutput := `{"data":[{"group":{"brand":"A","fuel_type":"gas"},"metrics":{"count":2,"p90:passengers":4}}],"message":"stats found"}`
+		expectedStatusCode := http.StatusOK
+		expectedFilter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorGte, Numbers: []float64{2000}},
+		}}
+		expectedGroupBy := []internal.VehicleField{internal.VehicleFieldBrand, internal.VehicleFieldFuelType}
+		expectedMetrics := []internal.VehicleMetric{
+			{Aggregate: internal.VehicleAggregateCount},
+			{Aggregate: internal.VehicleAggregatePercentile, Percentile: 90, Field: internal.VehicleFieldCapacity},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/stats?group_by=brand,fuel_type&metrics=count,p90:capacity&year_gte=2000", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedFilter, gotFilter)
+		require.Equal(t, expectedGroupBy, gotGroupBy)
+		require.Equal(t, expectedMetrics, gotMetrics)
+		require.Len(t, sv.StatsCalls(), 1)
+	})
+
+	t.Run("Invalid metrics", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Stats()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid metrics","instance":"/vehicles/stats","errors":[{"field":"metrics","message":"stats: invalid metric: unknown aggregate \"median\""}]}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/stats?metrics=median:weight", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		sv.AssertCalls()
+	})
+}
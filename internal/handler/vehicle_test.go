@@ -3,21 +3,25 @@ package handler_test
 import (
 	"app/internal"
 	"app/internal/handler"
-	"app/internal/service"
+	"app/internal/loader"
+	"app/internal/service/servicetest"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestHandlerVehicle_FindByColorAndYear(t *testing.T) {
 	t.Run("Find a vehicle by color and year", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.FindByColorAndYearFunc = func(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByColorAndYearFunc = func(ctx context.Context, color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
 				VehicleAttributes: internal.VehicleAttributes{
@@ -43,10 +47,11 @@ func TestHandlerVehicle_FindByColorAndYear(t *testing.T) {
 
 		hdFunc := hd.FindByColorAndYear()
 
-		expectedBodyOutput := `{"data":{"1":{"Id":1,"Brand":"A","Model":"B","Registration":"C","Color":"D","FabricationYear":1,"Capacity":1,"MaxSpeed":1,"FuelType":"E","Transmission":"F","Weight":1,"Height":1,"Length":1,"Width":1}},"message":"vehicles found"}`
+		expectedBodyOutput := `{"data":[{"id":1,"brand":"A","model":"B","registration":"C","color":"D","year":1,"passengers":1,"max_speed":1,"fuel_type":"E","transmission":"F","weight":1,"height":1,"length":1,"width":1}],"message":"vehicles found","meta":{"total":1,"limit":100,"offset":0,"next_cursor":null}}`
 		expectedStatusCode := http.StatusOK
 		expectedHeaderOutput := http.Header{
 			"Content-Type": []string{"application/json; charset=utf-8"},
+			"Vary":         []string{"Accept"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/color/D/year/1", nil)
@@ -60,20 +65,23 @@ func TestHandlerVehicle_FindByColorAndYear(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.FindByColorAndYear)
+		calls := sv.FindByColorAndYearCalls()
+		require.Len(t, calls, 1)
+		require.Equal(t, "D", calls[0].Color)
+		require.Equal(t, 1, calls[0].FabricationYear)
 	})
 
 	t.Run("Invalid year", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
+		sv := servicetest.NewVehicleDefaultMock(t)
 		hd := handler.NewHandlerVehicle(sv)
 
 		hdFunc := hd.FindByColorAndYear()
 
-		expectedBodyOutput := `{"message":"invalid year", "status":"Bad Request"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid year","instance":"/vehicles/color/D/year/F","errors":[{"field":"year","message":"must be an integer"}]}`
 		expectedStatusCode := http.StatusBadRequest
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/color/D/year/F", nil)
@@ -87,13 +95,13 @@ func TestHandlerVehicle_FindByColorAndYear(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 0, sv.Spy.FindByColorAndYear)
+		sv.AssertCalls()
 	})
 
 	t.Run("Unknown error", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.FindByColorAndYearFunc = func(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByColorAndYearFunc = func(ctx context.Context, color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
 			return nil, errors.New("unknown error")
 		}
 
@@ -101,10 +109,10 @@ func TestHandlerVehicle_FindByColorAndYear(t *testing.T) {
 
 		hdFunc := hd.FindByColorAndYear()
 
-		expectedBodyOutput := `{"message":"internal error", "status":"Internal Server Error"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error","instance":"/vehicles/color/D/year/1"}`
 		expectedStatusCode := http.StatusInternalServerError
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/color/D/year/1", nil)
@@ -118,15 +126,15 @@ func TestHandlerVehicle_FindByColorAndYear(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.FindByColorAndYear)
+		require.Len(t, sv.FindByColorAndYearCalls(), 1)
 	})
 }
 
 func TestHandlerVehicle_FindByBrandAndYearRange(t *testing.T) {
 	t.Run("Find a vehicle by brand and year", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.FindByBrandAndYearRangeFunc = func(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByBrandAndYearRangeFunc = func(ctx context.Context, brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
 				VehicleAttributes: internal.VehicleAttributes{
@@ -152,10 +160,11 @@ func TestHandlerVehicle_FindByBrandAndYearRange(t *testing.T) {
 
 		hdFunc := hd.FindByBrandAndYearRange()
 
-		expectedBodyOutput := `{"data":{"1":{"Id":1,"Brand":"A","Model":"B","Registration":"C","Color":"D","FabricationYear":1,"Capacity":1,"MaxSpeed":1,"FuelType":"E","Transmission":"F","Weight":1,"Height":1,"Length":1,"Width":1}},"message":"vehicles found"}`
+		expectedBodyOutput := `{"data":[{"id":1,"brand":"A","model":"B","registration":"C","color":"D","year":1,"passengers":1,"max_speed":1,"fuel_type":"E","transmission":"F","weight":1,"height":1,"length":1,"width":1}],"message":"vehicles found","meta":{"total":1,"limit":100,"offset":0,"next_cursor":null}}`
 		expectedStatusCode := http.StatusOK
 		expectedHeaderOutput := http.Header{
 			"Content-Type": []string{"application/json; charset=utf-8"},
+			"Vary":         []string{"Accept"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/brand/A/between/0/2", nil)
@@ -170,20 +179,20 @@ func TestHandlerVehicle_FindByBrandAndYearRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.FindByBrandAndYearRange)
+		require.Len(t, sv.FindByBrandAndYearRangeCalls(), 1)
 	})
 
 	t.Run("Invalid start year", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
+		sv := servicetest.NewVehicleDefaultMock(t)
 		hd := handler.NewHandlerVehicle(sv)
 
 		hdFunc := hd.FindByBrandAndYearRange()
 
-		expectedBodyOutput := `{"message":"invalid start_year", "status":"Bad Request"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid start_year","instance":"/vehicles/brand/A/between/A/2","errors":[{"field":"start_year","message":"must be an integer"}]}`
 		expectedStatusCode := http.StatusBadRequest
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/brand/A/between/A/2", nil)
@@ -198,20 +207,20 @@ func TestHandlerVehicle_FindByBrandAndYearRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 0, sv.Spy.FindByBrandAndYearRange)
+		sv.AssertCalls()
 	})
 
 	t.Run("Invalid end year", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
+		sv := servicetest.NewVehicleDefaultMock(t)
 		hd := handler.NewHandlerVehicle(sv)
 
 		hdFunc := hd.FindByBrandAndYearRange()
 
-		expectedBodyOutput := `{"message":"invalid end_year", "status":"Bad Request"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid end_year","instance":"/vehicles/brand/A/between/0/A","errors":[{"field":"end_year","message":"must be an integer"}]}`
 		expectedStatusCode := http.StatusBadRequest
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/brand/A/between/0/A", nil)
@@ -226,13 +235,13 @@ func TestHandlerVehicle_FindByBrandAndYearRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 0, sv.Spy.FindByBrandAndYearRange)
+		sv.AssertCalls()
 	})
 
 	t.Run("Unknown error", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.FindByBrandAndYearRangeFunc = func(color string, startYear, endYear int) (v map[int]internal.Vehicle, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByBrandAndYearRangeFunc = func(ctx context.Context, color string, startYear, endYear int) (v map[int]internal.Vehicle, err error) {
 			return nil, errors.New("unknown error")
 		}
 
@@ -240,10 +249,10 @@ func TestHandlerVehicle_FindByBrandAndYearRange(t *testing.T) {
 
 		hdFunc := hd.FindByBrandAndYearRange()
 
-		expectedBodyOutput := `{"message":"internal error", "status":"Internal Server Error"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error","instance":"/vehicles/brand/A/between/0/1"}`
 		expectedStatusCode := http.StatusInternalServerError
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/brand/A/between/0/1", nil)
@@ -258,15 +267,15 @@ func TestHandlerVehicle_FindByBrandAndYearRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.FindByBrandAndYearRange)
+		require.Len(t, sv.FindByBrandAndYearRangeCalls(), 1)
 	})
 }
 
 func TestHandlerVehicle_AverageMaxSpeedByBrand(t *testing.T) {
 	t.Run("Find average speed by a brand", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.AverageMaxSpeedByBrandFunc = func(brand string) (a float64, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.AverageMaxSpeedByBrandFunc = func(ctx context.Context, brand string) (a float64, err error) {
 			return 3.14, nil
 		}
 
@@ -290,13 +299,13 @@ func TestHandlerVehicle_AverageMaxSpeedByBrand(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.AverageMaxSpeedByBrand)
+		require.Len(t, sv.AverageMaxSpeedByBrandCalls(), 1)
 	})
 
 	t.Run("Vehicles not found", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.AverageMaxSpeedByBrandFunc = func(brand string) (a float64, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.AverageMaxSpeedByBrandFunc = func(ctx context.Context, brand string) (a float64, err error) {
 			return 0.0, internal.ErrServiceNoVehicles
 		}
 
@@ -304,10 +313,10 @@ func TestHandlerVehicle_AverageMaxSpeedByBrand(t *testing.T) {
 
 		hdFunc := hd.AverageMaxSpeedByBrand()
 
-		expectedBodyOutput := `{"message":"vehicles not found", "status": "Not Found"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Not Found","status":404,"detail":"no vehicles","instance":"/vehicles/average_speed/brand/A"}`
 		expectedStatusCode := http.StatusNotFound
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/average_speed/brand/A", nil)
@@ -320,13 +329,13 @@ func TestHandlerVehicle_AverageMaxSpeedByBrand(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.AverageMaxSpeedByBrand)
+		require.Len(t, sv.AverageMaxSpeedByBrandCalls(), 1)
 	})
 
 	t.Run("Unknown error", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.AverageMaxSpeedByBrandFunc = func(brand string) (a float64, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.AverageMaxSpeedByBrandFunc = func(ctx context.Context, brand string) (a float64, err error) {
 			return 0.0, errors.New("unknown error")
 		}
 
@@ -334,10 +343,10 @@ func TestHandlerVehicle_AverageMaxSpeedByBrand(t *testing.T) {
 
 		hdFunc := hd.AverageMaxSpeedByBrand()
 
-		expectedBodyOutput := `{"message":"internal error", "status": "Internal Server Error"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error","instance":"/vehicles/average_speed/brand/A"}`
 		expectedStatusCode := http.StatusInternalServerError
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/average_speed/brand/A", nil)
@@ -350,15 +359,15 @@ func TestHandlerVehicle_AverageMaxSpeedByBrand(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.AverageMaxSpeedByBrand)
+		require.Len(t, sv.AverageMaxSpeedByBrandCalls(), 1)
 	})
 }
 
 func TestHandlerVehicle_AverageCapacityByBrand(t *testing.T) {
 	t.Run("Find average capacity by a brand", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.AverageCapacityByBrandFunc = func(brand string) (a int, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.AverageCapacityByBrandFunc = func(ctx context.Context, brand string) (a int, err error) {
 			return 5, nil
 		}
 
@@ -382,13 +391,13 @@ func TestHandlerVehicle_AverageCapacityByBrand(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.AverageCapacityByBrand)
+		require.Len(t, sv.AverageCapacityByBrandCalls(), 1)
 	})
 
 	t.Run("Vehicles not found", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.AverageCapacityByBrandFunc = func(brand string) (a int, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.AverageCapacityByBrandFunc = func(ctx context.Context, brand string) (a int, err error) {
 			return 0, internal.ErrServiceNoVehicles
 		}
 
@@ -396,10 +405,10 @@ func TestHandlerVehicle_AverageCapacityByBrand(t *testing.T) {
 
 		hdFunc := hd.AverageCapacityByBrand()
 
-		expectedBodyOutput := `{"message":"vehicles not found", "status": "Not Found"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Not Found","status":404,"detail":"no vehicles","instance":"/vehicles/average_capacity/brand/A"}`
 		expectedStatusCode := http.StatusNotFound
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/average_capacity/brand/A", nil)
@@ -412,13 +421,13 @@ func TestHandlerVehicle_AverageCapacityByBrand(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.AverageCapacityByBrand)
+		require.Len(t, sv.AverageCapacityByBrandCalls(), 1)
 	})
 
 	t.Run("Unknown error", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.AverageCapacityByBrandFunc = func(brand string) (a int, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.AverageCapacityByBrandFunc = func(ctx context.Context, brand string) (a int, err error) {
 			return 0, errors.New("unknown error")
 		}
 
@@ -426,10 +435,10 @@ func TestHandlerVehicle_AverageCapacityByBrand(t *testing.T) {
 
 		hdFunc := hd.AverageCapacityByBrand()
 
-		expectedBodyOutput := `{"message":"internal error", "status": "Internal Server Error"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error","instance":"/vehicles/average_capacity/brand/A"}`
 		expectedStatusCode := http.StatusInternalServerError
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/average_capacity/brand/A", nil)
@@ -442,15 +451,15 @@ func TestHandlerVehicle_AverageCapacityByBrand(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.AverageCapacityByBrand)
+		require.Len(t, sv.AverageCapacityByBrandCalls(), 1)
 	})
 }
 
 func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 	t.Run("successfully search by weight range", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.SearchByWeightRangeFunc = func(query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SearchByWeightRangeFunc = func(ctx context.Context, query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
 				VehicleAttributes: internal.VehicleAttributes{
@@ -477,10 +486,11 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 
 		hdFunc := hd.SearchByWeightRange()
 
-		expectedBodyOutput := `{"data":{"1":{"Id":1,"Brand":"A","Model":"B","Registration":"C","Color":"D","FabricationYear":1,"Capacity":1,"MaxSpeed":1,"FuelType":"E","Transmission":"F","Weight":1,"Height":1,"Length":1,"Width":1}},"message":"vehicles found"}`
+		expectedBodyOutput := `{"data":[{"id":1,"brand":"A","model":"B","registration":"C","color":"D","year":1,"passengers":1,"max_speed":1,"fuel_type":"E","transmission":"F","weight":1,"height":1,"length":1,"width":1}],"message":"vehicles found","meta":{"total":1,"limit":100,"offset":0,"next_cursor":null}}`
 		expectedStatusCode := http.StatusOK
 		expectedHeaderOutput := http.Header{
 			"Content-Type": []string{"application/json; charset=utf-8"},
+			"Vary":         []string{"Accept"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/weight?weight_min=0&weight_max=20", nil)
@@ -490,13 +500,13 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.SearchByWeightRange)
+		require.Len(t, sv.SearchByWeightRangeCalls(), 1)
 	})
 
 	t.Run("successfully search without weight range", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.SearchByWeightRangeFunc = func(query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SearchByWeightRangeFunc = func(ctx context.Context, query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
 				VehicleAttributes: internal.VehicleAttributes{
@@ -523,10 +533,11 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 
 		hdFunc := hd.SearchByWeightRange()
 
-		expectedBodyOutput := `{"data":{"1":{"Id":1,"Brand":"A","Model":"B","Registration":"C","Color":"D","FabricationYear":1,"Capacity":1,"MaxSpeed":1,"FuelType":"E","Transmission":"F","Weight":1,"Height":1,"Length":1,"Width":1}},"message":"vehicles found"}`
+		expectedBodyOutput := `{"data":[{"id":1,"brand":"A","model":"B","registration":"C","color":"D","year":1,"passengers":1,"max_speed":1,"fuel_type":"E","transmission":"F","weight":1,"height":1,"length":1,"width":1}],"message":"vehicles found","meta":{"total":1,"limit":100,"offset":0,"next_cursor":null}}`
 		expectedStatusCode := http.StatusOK
 		expectedHeaderOutput := http.Header{
 			"Content-Type": []string{"application/json; charset=utf-8"},
+			"Vary":         []string{"Accept"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/weight", nil)
@@ -536,21 +547,21 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.SearchByWeightRange)
+		require.Len(t, sv.SearchByWeightRangeCalls(), 1)
 	})
 
 	t.Run("Invalid weight_min", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
+		sv := servicetest.NewVehicleDefaultMock(t)
 
 		hd := handler.NewHandlerVehicle(sv)
 
 		hdFunc := hd.SearchByWeightRange()
 
-		expectedBodyOutput := `{"message":"invalid weight_min", "status":"Bad Request"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid weight_min","instance":"/vehicles/weight","errors":[{"field":"weight_min","message":"must be a number"}]}`
 		expectedStatusCode := http.StatusBadRequest
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/weight?weight_min=invalid&weight_max=20", nil)
@@ -560,21 +571,21 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 0, sv.Spy.SearchByWeightRange)
+		sv.AssertCalls()
 	})
 
 	t.Run("Invalid weight_max", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
+		sv := servicetest.NewVehicleDefaultMock(t)
 
 		hd := handler.NewHandlerVehicle(sv)
 
 		hdFunc := hd.SearchByWeightRange()
 
-		expectedBodyOutput := `{"message":"invalid weight_max", "status":"Bad Request"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid weight_max","instance":"/vehicles/weight","errors":[{"field":"weight_max","message":"must be a number"}]}`
 		expectedStatusCode := http.StatusBadRequest
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/weight?weight_min=0&weight_max=invalid", nil)
@@ -584,13 +595,13 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 0, sv.Spy.SearchByWeightRange)
+		sv.AssertCalls()
 	})
 
 	t.Run("Unknown error", func(t *testing.T) {
 		// Given
-		sv := service.NewVehicleDefaultMock()
-		sv.SearchByWeightRangeFunc = func(query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SearchByWeightRangeFunc = func(ctx context.Context, query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
 			return nil, errors.New("unknown error")
 		}
 
@@ -598,10 +609,10 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 
 		hdFunc := hd.SearchByWeightRange()
 
-		expectedBodyOutput := `{"message":"internal error", "status": "Internal Server Error"}`
+		expectedBodyOutput := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error","instance":"/vehicles/average_capacity/brand/A"}`
 		expectedStatusCode := http.StatusInternalServerError
 		expectedHeaderOutput := http.Header{
-			"Content-Type": []string{"application/json"},
+			"Content-Type": []string{"application/problem+json"},
 		}
 		// When
 		req := httptest.NewRequest(http.MethodGet, "/vehicles/average_capacity/brand/A", nil)
@@ -611,6 +622,619 @@ func TestHandlerVehicle_SearchByWeightRange(t *testing.T) {
 		require.Equal(t, expectedStatusCode, res.Code)
 		require.JSONEq(t, expectedBodyOutput, res.Body.String())
 		require.Equal(t, expectedHeaderOutput, res.Header())
-		require.Equal(t, 1, sv.Spy.SearchByWeightRange)
+		require.Len(t, sv.SearchByWeightRangeCalls(), 1)
 	})
 }
+
+func TestHandlerVehicle_Create(t *testing.T) {
+	t.Run("Create a vehicle", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SaveFunc = func(ctx context.Context, v *internal.Vehicle) (err error) {
+			v.Id = 1
+			return nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Create()
+
+		expectedBodyOutput := `{"data":{"id":1,"brand":"A","model":"B","registration":"C","color":"D","year":1,"passengers":1,"max_speed":1,"fuel_type":"E","transmission":"F","weight":1,"height":1,"length":1,"width":1},"message":"vehicle created"}`
+		expectedStatusCode := http.StatusCreated
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/json; charset=utf-8"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(
+			`{"brand":"A","model":"B","registration":"C","color":"D","year":1,"passengers":1,"max_speed":1,"fuel_type":"E","transmission":"F","weight":1,"height":1,"length":1,"width":1}`,
+		))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.SaveCalls(), 1)
+	})
+
+	t.Run("Ignore the id of the body", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		var gotId int
+		sv.SaveFunc = func(ctx context.Context, v *internal.Vehicle) (err error) {
+			gotId = v.Id
+			v.Id = 2
+			return nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Create()
+
+		expectedStatusCode := http.StatusCreated
+		// When
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(`{"id":7,"brand":"A"}`))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.Len(t, sv.SaveCalls(), 1)
+		require.Equal(t, 0, gotId)
+		require.Equal(t, "A", sv.SaveCalls()[0].V.Brand)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Create()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid body","instance":"/vehicles"}`
+		expectedStatusCode := http.StatusBadRequest
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/problem+json"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(`{"brand":`))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		sv.AssertCalls()
+	})
+
+	t.Run("Invalid type of a field of the body", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Create()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid body","instance":"/vehicles","errors":[{"field":"year","message":"must be an integer"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(`{"brand":"A","year":"2020"}`))
+		req.Header.Set("Content-Type", "application/json")
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		sv.AssertCalls()
+	})
+
+	t.Run("Invalid content type", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Create()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid body","instance":"/vehicles","errors":[{"field":"Content-Type","message":"must be application/json"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(`{"brand":"A"}`))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		sv.AssertCalls()
+	})
+}
+
+func TestHandlerVehicle_Update(t *testing.T) {
+	t.Run("Update a vehicle", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.UpdateFunc = func(ctx context.Context, v internal.Vehicle) (err error) {
+			return nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Update()
+
+		expectedBodyOutput := `{"data":{"id":1,"brand":"A","model":"","registration":"","color":"","year":0,"passengers":0,"max_speed":0,"fuel_type":"","transmission":"","weight":0,"height":0,"length":0,"width":0},"message":"vehicle updated"}`
+		expectedStatusCode := http.StatusOK
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/json; charset=utf-8"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodPut, "/vehicles/1", strings.NewReader(`{"brand":"A"}`))
+		req.Header.Set("Content-Type", "application/json")
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.UpdateCalls(), 1)
+	})
+
+	t.Run("Invalid id", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Update()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid id","instance":"/vehicles/A","errors":[{"field":"id","message":"must be an integer"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/problem+json"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodPut, "/vehicles/A", strings.NewReader(`{"brand":"A"}`))
+		req.Header.Set("Content-Type", "application/json")
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "A")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		sv.AssertCalls()
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.UpdateFunc = func(ctx context.Context, v internal.Vehicle) (err error) {
+			return internal.ErrRepositoryVehicleNotFound
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Update()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Not Found","status":404,"detail":"vehicle not found","instance":"/vehicles/1"}`
+		expectedStatusCode := http.StatusNotFound
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/problem+json"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodPut, "/vehicles/1", strings.NewReader(`{"brand":"A"}`))
+		req.Header.Set("Content-Type", "application/json")
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.UpdateCalls(), 1)
+	})
+}
+
+func TestHandlerVehicle_Patch(t *testing.T) {
+	t.Run("Patch a vehicle", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.PatchFunc = func(ctx context.Context, id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+			v = internal.Vehicle{
+				Id: id,
+				VehicleAttributes: internal.VehicleAttributes{
+					Brand: "A",
+					Color: "B",
+				},
+			}
+			patch.Apply(&v.VehicleAttributes)
+			return
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Patch()
+
+		expectedBodyOutput := `{"data":{"id":1,"brand":"A","model":"","registration":"","color":"C","year":0,"passengers":0,"max_speed":0,"fuel_type":"","transmission":"","weight":0,"height":0,"length":0,"width":0},"message":"vehicle updated"}`
+		expectedStatusCode := http.StatusOK
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/json; charset=utf-8"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodPatch, "/vehicles/1", strings.NewReader(`{"color":"C"}`))
+		req.Header.Set("Content-Type", "application/json")
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.PatchCalls(), 1)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.PatchFunc = func(ctx context.Context, id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+			return internal.Vehicle{}, internal.ErrRepositoryVehicleNotFound
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Patch()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Not Found","status":404,"detail":"vehicle not found","instance":"/vehicles/1"}`
+		expectedStatusCode := http.StatusNotFound
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/problem+json"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodPatch, "/vehicles/1", strings.NewReader(`{"color":"C"}`))
+		req.Header.Set("Content-Type", "application/json")
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.PatchCalls(), 1)
+	})
+}
+
+func TestHandlerVehicle_Delete(t *testing.T) {
+	t.Run("Delete a vehicle", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.DeleteFunc = func(ctx context.Context, id int) (err error) {
+			return nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Delete()
+
+		expectedStatusCode := http.StatusNoContent
+		// When
+		req := httptest.NewRequest(http.MethodDelete, "/vehicles/1", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.Empty(t, res.Body.String())
+		require.Len(t, sv.DeleteCalls(), 1)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.DeleteFunc = func(ctx context.Context, id int) (err error) {
+			return internal.ErrRepositoryVehicleNotFound
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Delete()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Not Found","status":404,"detail":"vehicle not found","instance":"/vehicles/1"}`
+		expectedStatusCode := http.StatusNotFound
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/problem+json"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodDelete, "/vehicles/1", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.DeleteCalls(), 1)
+	})
+}
+
+func TestHandlerVehicle_Search(t *testing.T) {
+	t.Run("Search vehicles by query", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SearchFunc = func(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+			return map[int]internal.Vehicle{1: {
+				Id: 1,
+				VehicleAttributes: internal.VehicleAttributes{
+					Brand:           "A",
+					Model:           "B",
+					Registration:    "C",
+					Color:           "D",
+					FabricationYear: 1,
+					Capacity:        1,
+					MaxSpeed:        1,
+					FuelType:        "E",
+					Transmission:    "F",
+					Weight:          1,
+					Dimensions: internal.Dimensions{
+						Height: 1,
+						Length: 1,
+						Width:  1,
+					},
+				},
+			}}, nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Search()
+
+		expectedBodyOutput := `{"data":[{"id":1,"brand":"A","model":"B","registration":"C","color":"D","year":1,"passengers":1,"max_speed":1,"fuel_type":"E","transmission":"F","weight":1,"height":1,"length":1,"width":1}],"message":"vehicles found","meta":{"total":1,"limit":100,"offset":0,"next_cursor":null}}`
+		expectedStatusCode := http.StatusOK
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/json; charset=utf-8"},
+			"Vary":         []string{"Accept"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?brand=A&year_gte=2000&fuel_type=in:diesel,electric", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.SearchCalls(), 1)
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Search()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid weight_lte","instance":"/vehicles","errors":[{"field":"weight_lte","message":"must be a number"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/problem+json"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?weight_lte=heavy", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		sv.AssertCalls()
+	})
+
+	t.Run("Invalid values of the conditions", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Search()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid filter","instance":"/vehicles","errors":[{"field":"fuel_type","message":"must be one of gas, gasoline, diesel, biodiesel, electric, hybrid"},{"field":"weight_gte","message":"must not be negative"},{"field":"year_lte","message":"must not be less than year_gte"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?weight_gte=-5&fuel_type=in:plutonium&year_gte=2010&year_lte=2000", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Len(t, sv.SearchCalls(), 0)
+	})
+
+	t.Run("Unknown query parameter", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Search()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid colour","instance":"/vehicles","errors":[{"field":"colour","message":"unknown parameter"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?colour=red", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		sv.AssertCalls()
+	})
+
+	t.Run("Unknown error", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.SearchFunc = func(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+			return nil, errors.New("unknown error")
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.Search()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error","instance":"/vehicles"}`
+		expectedStatusCode := http.StatusInternalServerError
+		expectedHeaderOutput := http.Header{
+			"Content-Type": []string{"application/problem+json"},
+		}
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles", nil)
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		require.Equal(t, expectedHeaderOutput, res.Header())
+		require.Len(t, sv.SearchCalls(), 1)
+	})
+}
+
+func TestHandlerVehicle_FindById(t *testing.T) {
+	t.Run("Find a vehicle", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByIdFunc = func(ctx context.Context, id int) (v internal.Vehicle, err error) {
+			return internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{Brand: "A"}}, nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.FindById()
+
+		expectedBodyOutput := `{"message":"vehicle found","data":{"id":1,"brand":"A","model":"","registration":"","color":"","year":0,"passengers":0,"max_speed":0,"fuel_type":"","transmission":"","weight":0,"height":0,"length":0,"width":0}}`
+		expectedStatusCode := http.StatusOK
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/1", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		sv.AssertCalls("FindById")
+		require.Equal(t, 1, sv.FindByIdCalls()[0].Id)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByIdFunc = func(ctx context.Context, id int) (v internal.Vehicle, err error) {
+			return internal.Vehicle{}, internal.ErrRepositoryVehicleNotFound
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.FindById()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Not Found","status":404,"detail":"vehicle not found","instance":"/vehicles/2"}`
+		expectedStatusCode := http.StatusNotFound
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/2", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "2")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+
+	t.Run("Invalid id", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.FindById()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid id","instance":"/vehicles/A","errors":[{"field":"id","message":"must be an integer"}]}`
+		expectedStatusCode := http.StatusBadRequest
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/A", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("id", "A")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+		sv.AssertCalls()
+	})
+}
+
+func TestHandlerVehicle_FindByRegistration(t *testing.T) {
+	t.Run("Find a vehicle", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByRegistrationFunc = func(ctx context.Context, registration string) (v internal.Vehicle, err error) {
+			return internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "AB-123"}}, nil
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.FindByRegistration()
+
+		expectedStatusCode := http.StatusOK
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/registration/ab-123", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("registration", "ab-123")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.Contains(t, res.Body.String(), `"registration":"AB-123"`)
+		require.Len(t, sv.FindByRegistrationCalls(), 1)
+	})
+
+	t.Run("Registration shared by several vehicles", func(t *testing.T) {
+		// Given
+		sv := servicetest.NewVehicleDefaultMock(t)
+		sv.FindByRegistrationFunc = func(ctx context.Context, registration string) (v internal.Vehicle, err error) {
+			return internal.Vehicle{}, fmt.Errorf("%w: ids [2 3]", internal.ErrRepositoryRegistrationAmbiguous)
+		}
+		hd := handler.NewHandlerVehicle(sv)
+
+		hdFunc := hd.FindByRegistration()
+
+		expectedBodyOutput := `{"type":"about:blank","title":"Conflict","status":409,"detail":"registration matches several vehicles: ids [2 3]","instance":"/vehicles/registration/cd456"}`
+		expectedStatusCode := http.StatusConflict
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles/registration/cd456", nil)
+		chiCtx := chi.NewRouteContext()
+		chiCtx.URLParams.Add("registration", "cd456")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
+		res := httptest.NewRecorder()
+		hdFunc(res, req)
+		// Then
+		require.Equal(t, expectedStatusCode, res.Code)
+		require.JSONEq(t, expectedBodyOutput, res.Body.String())
+	})
+}
+
+func TestVehiclePatchJSON_Keys(t *testing.T) {
+	// Given
+	keys := func(v any) (k []string) {
+		tp := reflect.TypeOf(v)
+		for i := 0; i < tp.NumField(); i++ {
+			if key := tp.Field(i).Tag.Get("json"); key != "id" {
+				k = append(k, key)
+			}
+		}
+		return
+	}
+
+	// When
+	patch := keys(handler.VehiclePatchJSON{})
+	vehicle := keys(loader.VehicleJSON{})
+	// Then
+	require.Equal(t, vehicle, patch)
+}
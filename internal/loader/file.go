@@ -0,0 +1,49 @@
+package loader
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic is a function that writes a file through a temporary file in the same directory that is renamed once complete,
+// so a failure never leaves a truncated file behind
+func writeFileAtomic(path string, write func(w io.Writer) (err error)) (err error) {
+	// create temporary file
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+
+	// write file
+	err = write(file)
+	if err != nil {
+		return
+	}
+	err = file.Sync()
+	if err != nil {
+		return
+	}
+	err = file.Close()
+	if err != nil {
+		return
+	}
+
+	// keep permissions of the original file
+	if info, statErr := os.Stat(path); statErr == nil {
+		err = os.Chmod(file.Name(), info.Mode().Perm())
+		if err != nil {
+			return
+		}
+	}
+
+	// replace file
+	err = os.Rename(file.Name(), path)
+	return
+}
@@ -0,0 +1,40 @@
+package loader
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrLoaderUnsupportedFormat is an error that represents a file format without loader
+	ErrLoaderUnsupportedFormat = errors.New("loader: unsupported file format")
+)
+
+// NewLoaderVehicle is a function that returns the loader for the file format given by the extension of the path
+func NewLoaderVehicle(path string) (ld internal.LoaderVehicle, err error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		ld = NewLoaderVehicleJSON(path)
+	case ".csv":
+		ld = NewLoaderVehicleCSV(path)
+	default:
+		err = fmt.Errorf("%w: %q", ErrLoaderUnsupportedFormat, ext)
+	}
+	return
+}
+
+// NewStorerVehicle is a function that returns the storer for the file format given by the extension of the path
+func NewStorerVehicle(path string) (st internal.StorerVehicle, err error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		st = NewStorerVehicleJSON(path)
+	case ".csv":
+		st = NewStorerVehicleCSV(path)
+	default:
+		err = fmt.Errorf("%w: %q", ErrLoaderUnsupportedFormat, ext)
+	}
+	return
+}
@@ -0,0 +1,257 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrLoaderCSVMissingColumn is an error that represents a required column missing in the header
+	ErrLoaderCSVMissingColumn = errors.New("loader: csv missing column")
+	// ErrLoaderCSVDuplicateColumn is an error that represents a column repeated in the header
+	ErrLoaderCSVDuplicateColumn = errors.New("loader: csv duplicate column")
+	// ErrLoaderCSVInvalidValue is an error that represents a value that can not be parsed
+	ErrLoaderCSVInvalidValue = errors.New("loader: csv invalid value")
+)
+
+// ParseError is an error that represents a failure parsing a field of the CSV file
+type ParseError struct {
+	// Line is the line of the file where the error happened, starting at 1
+	Line int
+	// Column is the column of the file where the error happened, starting at 1
+	Column int
+	// Field is the name of the column in the header
+	Field string
+	// Err is the underlying error
+	Err error
+}
+
+// Error is a method that returns the error message
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d (%s): %v", e.Line, e.Column, e.Field, e.Err)
+}
+
+// Unwrap is a method that returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// CSVHeader is the header of a CSV file of vehicles, columns use the same keys as the JSON format
+var CSVHeader = []string{
+	"id", "brand", "model", "registration", "color", "year", "passengers", "max_speed",
+	"fuel_type", "transmission", "weight", "height", "length", "width",
+}
+
+// NewLoaderVehicleCSV is a function that returns a new instance of LoaderVehicleCSV
+func NewLoaderVehicleCSV(path string) *LoaderVehicleCSV {
+	return &LoaderVehicleCSV{
+		path: path,
+	}
+}
+
+// LoaderVehicleCSV is a struct that implements the LoaderVehicle interface
+// - columns are mapped by the header, so their order does not matter and unknown columns are ignored
+type LoaderVehicleCSV struct {
+	// path is the path to the file that contains the vehicles in CSV format
+	path string
+}
+
+// Load is a method that loads the vehicles
+func (l *LoaderVehicleCSV) Load() (v map[int]internal.Vehicle, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	vehiclesJSON, err := DecodeCSV(file)
+	if err != nil {
+		return
+	}
+
+	// serialize vehicles
+	v = make(map[int]internal.Vehicle)
+	for _, vh := range vehiclesJSON {
+		v[vh.Id] = vh.Vehicle()
+	}
+
+	return
+}
+
+// DecodeCSV is a function that decodes the vehicles of a CSV file with header
+// - every column of CSVHeader is required, so a file without some of them is not loaded with zero values
+// - every record must have an id, records without one would be loaded as the same vehicle
+func DecodeCSV(r io.Reader) (v []VehicleJSON, err error) {
+	records, err := decodeRecordsCSV(r, CSVHeader, []string{"id"})
+	if err != nil {
+		return
+	}
+
+	v = make([]VehicleJSON, len(records))
+	for i, rc := range records {
+		v[i] = rc.Vehicle
+	}
+	return
+}
+
+// DecodeRecordsCSV is a function that decodes the records of a CSV file with header, keeping their missing fields
+// - a field is missing when its column is not in the header or its value is empty, only the id column is required
+func DecodeRecordsCSV(r io.Reader) (v []VehicleRecord, err error) {
+	v, err = decodeRecordsCSV(r, []string{"id"}, nil)
+	return
+}
+
+// decodeRecordsCSV is a function that decodes the records of a CSV file with header
+// - required are the columns that must be in the header, and values the ones that must not be empty in any record
+// - a UTF-8 byte order mark, as written by spreadsheets, is skipped
+func decodeRecordsCSV(r io.Reader, required, values []string) (v []VehicleRecord, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	// header
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = fmt.Errorf("%w: %s", ErrLoaderCSVMissingColumn, strings.Join(required, ", "))
+		}
+		return
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok {
+			err = &ParseError{Line: 1, Column: i + 1, Field: name, Err: ErrLoaderCSVDuplicateColumn}
+			return
+		}
+		columns[name] = i
+	}
+	var missing []string
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		err = fmt.Errorf("%w: %s", ErrLoaderCSVMissingColumn, strings.Join(missing, ", "))
+		return
+	}
+
+	// records
+	v = make([]VehicleRecord, 0)
+	for {
+		var record []string
+		record, err = reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+				break
+			}
+			return
+		}
+		line, _ := reader.FieldPos(0)
+
+		// fields
+		p := csvRecordParser{record: record, columns: columns, line: line}
+		vh := VehicleJSON{
+			Id:              p.int("id"),
+			Brand:           p.string("brand"),
+			Model:           p.string("model"),
+			Registration:    p.string("registration"),
+			Color:           p.string("color"),
+			FabricationYear: p.int("year"),
+			Capacity:        p.int("passengers"),
+			MaxSpeed:        p.float("max_speed"),
+			FuelType:        p.string("fuel_type"),
+			Transmission:    p.string("transmission"),
+			Weight:          p.float("weight"),
+			Height:          p.float("height"),
+			Length:          p.float("length"),
+			Width:           p.float("width"),
+		}
+		for _, name := range values {
+			if p.err == nil && slices.Contains(p.missing, name) {
+				p.err = &ParseError{Line: line, Column: columns[name] + 1, Field: name, Err: fmt.Errorf("%w: empty value", ErrLoaderCSVInvalidValue)}
+			}
+		}
+		if p.err != nil {
+			err = p.err
+			return
+		}
+		v = append(v, VehicleRecord{Vehicle: vh, Missing: p.missing})
+	}
+
+	return
+}
+
+// csvRecordParser is a struct that parses the fields of a record keeping the first error
+type csvRecordParser struct {
+	// record is the list of fields of the record
+	record []string
+	// columns is the index of each column by name
+	columns map[string]int
+	// line is the line of the record in the file
+	line int
+	// err is the first error found
+	err error
+	// missing are the names of the columns that are not in the header or are empty
+	missing []string
+}
+
+// value is a method that returns the value of a column, empty if the column is not in the header
+func (p *csvRecordParser) value(name string) (s string, column int, ok bool) {
+	column, ok = p.columns[name]
+	if !ok || column >= len(p.record) {
+		ok = false
+		p.missing = append(p.missing, name)
+		return
+	}
+	s = strings.TrimSpace(p.record[column])
+	if s == "" {
+		p.missing = append(p.missing, name)
+	}
+	return
+}
+
+// string is a method that returns the value of a column as a string
+func (p *csvRecordParser) string(name string) (s string) {
+	s, _, _ = p.value(name)
+	return
+}
+
+// int is a method that returns the value of a column as an int, empty values are 0
+func (p *csvRecordParser) int(name string) (n int) {
+	s, column, ok := p.value(name)
+	if !ok || s == "" || p.err != nil {
+		return
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		p.err = &ParseError{Line: p.line, Column: column + 1, Field: name, Err: fmt.Errorf("%w: %q is not an integer", ErrLoaderCSVInvalidValue, s)}
+	}
+	return
+}
+
+// float is a method that returns the value of a column as a float, empty values are 0
+func (p *csvRecordParser) float(name string) (f float64) {
+	s, column, ok := p.value(name)
+	if !ok || s == "" || p.err != nil {
+		return
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		p.err = &ParseError{Line: p.line, Column: column + 1, Field: name, Err: fmt.Errorf("%w: %q is not a number", ErrLoaderCSVInvalidValue, s)}
+	}
+	return
+}
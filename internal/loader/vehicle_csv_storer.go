@@ -0,0 +1,76 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// NewStorerVehicleCSV is a function that returns a new instance of StorerVehicleCSV
+func NewStorerVehicleCSV(path string) *StorerVehicleCSV {
+	return &StorerVehicleCSV{
+		path: path,
+	}
+}
+
+// StorerVehicleCSV is a struct that implements the StorerVehicle interface
+type StorerVehicleCSV struct {
+	// path is the path to the file that contains the vehicles in CSV format
+	path string
+}
+
+// Store is a method that stores the vehicles
+// - the file is replaced atomically, a failure never leaves a truncated file behind
+func (s *StorerVehicleCSV) Store(v map[int]internal.Vehicle) (err error) {
+	vehiclesJSON := sortedVehiclesJSON(v)
+
+	err = writeFileAtomic(s.path, func(w io.Writer) (err error) {
+		err = EncodeCSV(w, vehiclesJSON)
+		return
+	})
+	return
+}
+
+// EncodeCSV is a function that encodes the vehicles as a CSV file with header
+func EncodeCSV(w io.Writer, v []VehicleJSON) (err error) {
+	writer := csv.NewWriter(w)
+
+	// header
+	err = writer.Write(CSVHeader)
+	if err != nil {
+		return
+	}
+
+	// records
+	for _, vh := range v {
+		err = writer.Write(vh.CSVRecord())
+		if err != nil {
+			return
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+	return
+}
+
+// CSVRecord is a method that returns the fields of the vehicle in the order of CSVHeader
+func (v VehicleJSON) CSVRecord() []string {
+	return []string{
+		strconv.Itoa(v.Id),
+		v.Brand,
+		v.Model,
+		v.Registration,
+		v.Color,
+		strconv.Itoa(v.FabricationYear),
+		strconv.Itoa(v.Capacity),
+		strconv.FormatFloat(v.MaxSpeed, 'f', -1, 64),
+		v.FuelType,
+		v.Transmission,
+		strconv.FormatFloat(v.Weight, 'f', -1, 64),
+		strconv.FormatFloat(v.Height, 'f', -1, 64),
+		strconv.FormatFloat(v.Length, 'f', -1, 64),
+		strconv.FormatFloat(v.Width, 'f', -1, 64),
+	}
+}
@@ -0,0 +1,149 @@
+package loader_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	t.Run("Decode columns by header", func(t *testing.T) {
+		// Given
+		input := "brand,id,year,unknown,weight,model,registration,color,passengers,max_speed,fuel_type,transmission,height,length,width\n" +
+			"A, 1, 2008, x, 1.5,,,,,,,,,,\n" +
+			"B,2,,y,,,,,,,,,,,\n"
+
+		expectedResult := []loader.VehicleJSON{
+			{Id: 1, Brand: "A", FabricationYear: 2008, Weight: 1.5},
+			{Id: 2, Brand: "B"},
+		}
+		// When
+		result, err := loader.DecodeCSV(strings.NewReader(input))
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Invalid value reports line and column", func(t *testing.T) {
+		// Given
+		input := "id,brand,year,model,registration,color,passengers,max_speed,fuel_type,transmission,weight,height,length,width\n" +
+			"1,A,2008,,,,,,,,,,,\n" +
+			"2,B,two thousand,,,,,,,,,,,\n"
+
+		// When
+		_, err := loader.DecodeCSV(strings.NewReader(input))
+		// Then
+		var parseErr *loader.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.ErrorIs(t, err, loader.ErrLoaderCSVInvalidValue)
+		require.Equal(t, 3, parseErr.Line)
+		require.Equal(t, 3, parseErr.Column)
+		require.Equal(t, "year", parseErr.Field)
+		require.EqualError(t, err, `line 3, column 3 (year): loader: csv invalid value: "two thousand" is not an integer`)
+	})
+
+	t.Run("Skip the byte order mark of spreadsheets", func(t *testing.T) {
+		// Given
+		input := "\ufeffid,brand,year,model,registration,color,passengers,max_speed,fuel_type,transmission,weight,height,length,width\n" +
+			"1,A,2008,,,,,,,,,,,\n"
+
+		expectedResult := []loader.VehicleJSON{{Id: 1, Brand: "A", FabricationYear: 2008}}
+		// When
+		result, err := loader.DecodeCSV(strings.NewReader(input))
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Empty id reports line and column", func(t *testing.T) {
+		// Given
+		input := "brand,id,year,model,registration,color,passengers,max_speed,fuel_type,transmission,weight,height,length,width\n" +
+			"A,1,2008,,,,,,,,,,,\n" +
+			"B, ,2009,,,,,,,,,,,\n"
+
+		// When
+		_, err := loader.DecodeCSV(strings.NewReader(input))
+		// Then
+		var parseErr *loader.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.ErrorIs(t, err, loader.ErrLoaderCSVInvalidValue)
+		require.EqualError(t, err, "line 3, column 2 (id): loader: csv invalid value: empty value")
+	})
+
+	t.Run("Missing id column", func(t *testing.T) {
+		// Given
+		input := "brand,year\nA,2008\n"
+
+		// When
+		_, err := loader.DecodeCSV(strings.NewReader(input))
+		// Then
+		require.ErrorIs(t, err, loader.ErrLoaderCSVMissingColumn)
+	})
+
+	t.Run("Missing columns are named", func(t *testing.T) {
+		// Given
+		input := "id,model,registration,color,passengers,max_speed,fuel_type,transmission,height,length,width\n" +
+			"1,B,AB12,Red,4,180,diesel,manual,1.5,4.2,1.7\n"
+
+		// When
+		_, err := loader.DecodeCSV(strings.NewReader(input))
+		// Then
+		require.ErrorIs(t, err, loader.ErrLoaderCSVMissingColumn)
+		require.EqualError(t, err, "loader: csv missing column: brand, year, weight")
+	})
+
+	t.Run("Duplicate column", func(t *testing.T) {
+		// Given
+		input := "id,brand,Brand\n1,A,B\n"
+
+		// When
+		_, err := loader.DecodeCSV(strings.NewReader(input))
+		// Then
+		var parseErr *loader.ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.ErrorIs(t, err, loader.ErrLoaderCSVDuplicateColumn)
+		require.Equal(t, 1, parseErr.Line)
+		require.Equal(t, 3, parseErr.Column)
+	})
+}
+
+func TestLoaderVehicleCSV_Load(t *testing.T) {
+	t.Run("Load the vehicles stored by StorerVehicleCSV", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.csv")
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           "A",
+				Model:           "B, C",
+				Registration:    "0001",
+				Color:           "D",
+				FabricationYear: 2008,
+				Capacity:        4,
+				MaxSpeed:        120.5,
+				FuelType:        "diesel",
+				Transmission:    "manual",
+				Weight:          1.25,
+				Dimensions: internal.Dimensions{
+					Height: 1,
+					Length: 2,
+					Width:  3,
+				},
+			},
+		}, 2: {
+			Id: 2,
+		}}
+		err := loader.NewStorerVehicleCSV(path).Store(db)
+		require.NoError(t, err)
+
+		// When
+		result, err := loader.NewLoaderVehicleCSV(path).Load()
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, db, result)
+	})
+}
@@ -37,6 +37,50 @@ type VehicleJSON struct {
 	Width           float64 `json:"width"`
 }
 
+// Vehicle is a method that returns the vehicle represented by the JSON format
+func (v VehicleJSON) Vehicle() internal.Vehicle {
+	return internal.Vehicle{
+		Id: v.Id,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           v.Brand,
+			Model:           v.Model,
+			Registration:    v.Registration,
+			Color:           v.Color,
+			FabricationYear: v.FabricationYear,
+			Capacity:        v.Capacity,
+			MaxSpeed:        v.MaxSpeed,
+			FuelType:        v.FuelType,
+			Transmission:    v.Transmission,
+			Weight:          v.Weight,
+			Dimensions: internal.Dimensions{
+				Height: v.Height,
+				Length: v.Length,
+				Width:  v.Width,
+			},
+		},
+	}
+}
+
+// NewVehicleJSON is a function that returns the JSON format of a vehicle
+func NewVehicleJSON(v internal.Vehicle) VehicleJSON {
+	return VehicleJSON{
+		Id:              v.Id,
+		Brand:           v.Brand,
+		Model:           v.Model,
+		Registration:    v.Registration,
+		Color:           v.Color,
+		FabricationYear: v.FabricationYear,
+		Capacity:        v.Capacity,
+		MaxSpeed:        v.MaxSpeed,
+		FuelType:        v.FuelType,
+		Transmission:    v.Transmission,
+		Weight:          v.Weight,
+		Height:          v.Height,
+		Length:          v.Length,
+		Width:           v.Width,
+	}
+}
+
 // Load is a method that loads the vehicles
 func (l *LoaderVehicleJSON) Load() (v map[int]internal.Vehicle, err error) {
 	// open file
@@ -56,26 +100,7 @@ func (l *LoaderVehicleJSON) Load() (v map[int]internal.Vehicle, err error) {
 	// serialize vehicles
 	v = make(map[int]internal.Vehicle)
 	for _, vh := range vehiclesJSON {
-		v[vh.Id] = internal.Vehicle{
-			Id: vh.Id,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           vh.Brand,
-				Model:           vh.Model,
-				Registration:    vh.Registration,
-				Color:           vh.Color,
-				FabricationYear: vh.FabricationYear,
-				Capacity:        vh.Capacity,
-				MaxSpeed:        vh.MaxSpeed,
-				FuelType:        vh.FuelType,
-				Transmission:    vh.Transmission,
-				Weight:          vh.Weight,
-				Dimensions: internal.Dimensions{
-					Height: vh.Height,
-					Length: vh.Length,
-					Width:  vh.Width,
-				},
-			},
-		}
+		v[vh.Id] = vh.Vehicle()
 	}
 
 	return
@@ -0,0 +1,45 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// NewStorerVehicleJSON is a function that returns a new instance of StorerVehicleJSON
+func NewStorerVehicleJSON(path string) *StorerVehicleJSON {
+	return &StorerVehicleJSON{
+		path: path,
+	}
+}
+
+// StorerVehicleJSON is a struct that implements the StorerVehicle interface
+type StorerVehicleJSON struct {
+	// path is the path to the file that contains the vehicles in JSON format
+	path string
+}
+
+// Store is a method that stores the vehicles
+// - the file is replaced atomically, a failure never leaves a truncated file behind
+func (s *StorerVehicleJSON) Store(v map[int]internal.Vehicle) (err error) {
+	vehiclesJSON := sortedVehiclesJSON(v)
+
+	err = writeFileAtomic(s.path, func(w io.Writer) (err error) {
+		err = json.NewEncoder(w).Encode(vehiclesJSON)
+		return
+	})
+	return
+}
+
+// sortedVehiclesJSON is a function that returns the JSON format of the vehicles sorted by id
+func sortedVehiclesJSON(v map[int]internal.Vehicle) (vehiclesJSON []VehicleJSON) {
+	vehiclesJSON = make([]VehicleJSON, 0, len(v))
+	for _, vh := range v {
+		vehiclesJSON = append(vehiclesJSON, NewVehicleJSON(vh))
+	}
+	sort.Slice(vehiclesJSON, func(i, j int) bool {
+		return vehiclesJSON[i].Id < vehiclesJSON[j].Id
+	})
+	return
+}
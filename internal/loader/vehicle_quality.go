@@ -0,0 +1,235 @@
+package loader
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NewQualityCheckerVehicleFile is a function that returns a new instance of QualityCheckerVehicleFile
+func NewQualityCheckerVehicleFile(path string) *QualityCheckerVehicleFile {
+	return &QualityCheckerVehicleFile{
+		path: path,
+	}
+}
+
+// QualityCheckerVehicleFile is a struct that implements the QualityCheckerVehicle interface
+// - the file is read on each check, with the format given by the extension of the path
+type QualityCheckerVehicleFile struct {
+	// path is the path to the file that contains the vehicles
+	path string
+}
+
+// Check is a method that reads the records of the file and reports their issues
+func (c *QualityCheckerVehicleFile) Check() (r internal.QualityReport, err error) {
+	records, err := ReadVehicleRecords(c.path)
+	if err != nil {
+		return
+	}
+
+	r = CheckVehicleRecords(records)
+	return
+}
+
+// qualityRule is a struct that represents the checks of a field of a record
+type qualityRule struct {
+	// field is the name of the field, as in the JSON format
+	field string
+	// kind is the kind of the issues reported by check
+	kind internal.QualityIssueKind
+	// empty returns the description of a value that is present but empty, "" if it is not; nil if any value is accepted
+	empty func(vh VehicleJSON) string
+	// check validates the value of the field
+	check func(v *internal.Validator, vh VehicleJSON)
+}
+
+// emptyText is a function that returns the empty check of a text field
+func emptyText(value func(vh VehicleJSON) string) func(vh VehicleJSON) string {
+	return func(vh VehicleJSON) string {
+		if strings.TrimSpace(value(vh)) == "" {
+			return "is empty"
+		}
+		return ""
+	}
+}
+
+// emptyMeasure is a function that returns the empty check of a measure, a zero value is not a real measure
+func emptyMeasure(value func(vh VehicleJSON) float64) func(vh VehicleJSON) string {
+	return func(vh VehicleJSON) string {
+		if value(vh) == 0 {
+			return "is zero"
+		}
+		return ""
+	}
+}
+
+// qualityRules are the checks of the fields of a record, in the order of CSVHeader
+// - the rules of each value are the ones used to validate the vehicles in the service
+var qualityRules = []qualityRule{
+	{
+		field: "id", kind: internal.QualityIssueOutOfRange,
+		check: func(v *internal.Validator, vh VehicleJSON) { v.Check(vh.Id > 0, "id", "must be positive") },
+	},
+	{
+		field: "brand", kind: internal.QualityIssueOutOfRange,
+		empty: emptyText(func(vh VehicleJSON) string { return vh.Brand }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.Text("brand", vh.Brand) },
+	},
+	{
+		field: "model", kind: internal.QualityIssueOutOfRange,
+		empty: emptyText(func(vh VehicleJSON) string { return vh.Model }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.Text("model", vh.Model) },
+	},
+	{
+		field: "registration", kind: internal.QualityIssueOutOfRange,
+		empty: emptyText(func(vh VehicleJSON) string { return vh.Registration }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.Registration("registration", vh.Registration) },
+	},
+	{
+		field: "color", kind: internal.QualityIssueOutOfRange,
+		empty: emptyText(func(vh VehicleJSON) string { return vh.Color }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.Text("color", vh.Color) },
+	},
+	{
+		field: "year", kind: internal.QualityIssueOutOfRange,
+		check: func(v *internal.Validator, vh VehicleJSON) { v.Year("year", vh.FabricationYear) },
+	},
+	{
+		field: "passengers", kind: internal.QualityIssueOutOfRange,
+		check: func(v *internal.Validator, vh VehicleJSON) { v.Capacity("passengers", vh.Capacity) },
+	},
+	{
+		field: "max_speed", kind: internal.QualityIssueOutOfRange,
+		empty: emptyMeasure(func(vh VehicleJSON) float64 { return vh.MaxSpeed }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.NonNegative("max_speed", vh.MaxSpeed) },
+	},
+	{
+		field: "fuel_type", kind: internal.QualityIssueUnknownValue,
+		empty: emptyText(func(vh VehicleJSON) string { return vh.FuelType }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.OneOf("fuel_type", vh.FuelType, internal.FuelTypes) },
+	},
+	{
+		field: "transmission", kind: internal.QualityIssueUnknownValue,
+		empty: emptyText(func(vh VehicleJSON) string { return vh.Transmission }),
+		check: func(v *internal.Validator, vh VehicleJSON) {
+			v.OneOf("transmission", vh.Transmission, internal.Transmissions)
+		},
+	},
+	{
+		field: "weight", kind: internal.QualityIssueOutOfRange,
+		empty: emptyMeasure(func(vh VehicleJSON) float64 { return vh.Weight }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.NonNegative("weight", vh.Weight) },
+	},
+	{
+		field: "height", kind: internal.QualityIssueOutOfRange,
+		empty: emptyMeasure(func(vh VehicleJSON) float64 { return vh.Height }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.NonNegative("height", vh.Height) },
+	},
+	{
+		field: "length", kind: internal.QualityIssueOutOfRange,
+		empty: emptyMeasure(func(vh VehicleJSON) float64 { return vh.Length }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.NonNegative("length", vh.Length) },
+	},
+	{
+		field: "width", kind: internal.QualityIssueOutOfRange,
+		empty: emptyMeasure(func(vh VehicleJSON) float64 { return vh.Width }),
+		check: func(v *internal.Validator, vh VehicleJSON) { v.NonNegative("width", vh.Width) },
+	},
+}
+
+// CheckVehicleRecords is a function that reports the data-quality issues of the records
+// - duplicates point to the first record with the same id or registration, registrations are compared by internal.NormalizeRegistration
+// - a missing field is only reported as missing, its zero value is not checked
+func CheckVehicleRecords(records []VehicleRecord) (r internal.QualityReport) {
+	r.Records = len(records)
+	r.Issues = make([]internal.QualityIssue, 0)
+
+	ids := make(map[int]int)
+	registrations := make(map[string]int)
+	for i, rc := range records {
+		vh := rc.Vehicle
+		issue := func(field string, kind internal.QualityIssueKind, message string) {
+			r.Issues = append(r.Issues, internal.QualityIssue{Record: i + 1, Id: vh.Id, Field: field, Kind: kind, Message: message})
+		}
+
+		// duplicates
+		if !slices.Contains(rc.Missing, "id") {
+			if first, ok := ids[vh.Id]; ok {
+				issue("id", internal.QualityIssueDuplicateId, fmt.Sprintf("same id as record %d", first))
+			} else {
+				ids[vh.Id] = i + 1
+			}
+		}
+		if key := internal.NormalizeRegistration(vh.Registration); key != "" {
+			if first, ok := registrations[key]; ok {
+				issue("registration", internal.QualityIssueDuplicateRegistration, fmt.Sprintf("same registration as record %d", first))
+			} else {
+				registrations[key] = i + 1
+			}
+		}
+
+		// fields
+		for _, rule := range qualityRules {
+			if slices.Contains(rc.Missing, rule.field) {
+				issue(rule.field, internal.QualityIssueMissingField, "is missing")
+				continue
+			}
+			if rule.empty != nil {
+				if msg := rule.empty(vh); msg != "" {
+					issue(rule.field, internal.QualityIssueMissingField, msg)
+					continue
+				}
+			}
+			var v internal.Validator
+			rule.check(&v, vh)
+			var ve *internal.ValidationError
+			if errors.As(v.Err(internal.ErrVehicleInvalid), &ve) {
+				for _, f := range ve.Fields {
+					issue(f.Field, rule.kind, f.Message)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// QualityIssueJSON is a struct that represents a data-quality issue in JSON format
+type QualityIssueJSON struct {
+	Record  int    `json:"record"`
+	Id      int    `json:"id"`
+	Field   string `json:"field"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// QualityReportJSON is a struct that represents a data-quality report in JSON format
+type QualityReportJSON struct {
+	Records int                `json:"records"`
+	Counts  map[string]int     `json:"counts"`
+	Issues  []QualityIssueJSON `json:"issues"`
+}
+
+// NewQualityReportJSON is a function that returns the JSON format of a data-quality report
+func NewQualityReportJSON(rp internal.QualityReport) (data QualityReportJSON) {
+	data = QualityReportJSON{
+		Records: rp.Records,
+		Counts:  make(map[string]int),
+		Issues:  make([]QualityIssueJSON, len(rp.Issues)),
+	}
+	for k, n := range rp.Counts() {
+		data.Counts[string(k)] = n
+	}
+	for i, is := range rp.Issues {
+		data.Issues[i] = QualityIssueJSON{
+			Record:  is.Record,
+			Id:      is.Id,
+			Field:   is.Field,
+			Kind:    string(is.Kind),
+			Message: is.Message,
+		}
+	}
+	return
+}
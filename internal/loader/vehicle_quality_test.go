@@ -0,0 +1,109 @@
+package loader_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// validRecordJSON is a record of a vehicle without issues, with the id and registration to format
+const validRecordJSON = `{"id":%d,"brand":"Ford","model":"Fiesta","registration":"%s","color":"Red","year":2010,` +
+	`"passengers":4,"max_speed":180,"fuel_type":"gasoline","transmission":"manual",` +
+	`"weight":1100,"height":1.5,"length":4,"width":1.7}`
+
+func TestCheckVehicleRecords(t *testing.T) {
+	t.Run("Valid records have no issues", func(t *testing.T) {
+		// Given
+		input := "[" + fmtRecord(1, "AB-123") + "," + fmtRecord(2, "CD-456") + "]"
+		records, err := loader.DecodeRecordsJSON(strings.NewReader(input))
+		require.NoError(t, err)
+
+		// When
+		result := loader.CheckVehicleRecords(records)
+		// Then
+		require.Equal(t, 2, result.Records)
+		require.Empty(t, result.Issues)
+	})
+
+	t.Run("Report duplicates, missing fields, out-of-range and unknown values", func(t *testing.T) {
+		// Given
+		input := "[" +
+			fmtRecord(1, "AB-123") + "," +
+			fmtRecord(1, "ab-123") + "," +
+			`{"id":3,"brand":"Ford","model":"Fiesta","registration":"0","color":"","year":1800,` +
+			`"passengers":4,"max_speed":-1,"fuel_type":"steam","transmission":"manual",` +
+			`"weight":1100,"height":1.5,"length":0}` +
+			"]"
+		records, err := loader.DecodeRecordsJSON(strings.NewReader(input))
+		require.NoError(t, err)
+
+		expectedIssues := []internal.QualityIssue{
+			{Record: 2, Id: 1, Field: "id", Kind: internal.QualityIssueDuplicateId, Message: "same id as record 1"},
+			{Record: 2, Id: 1, Field: "registration", Kind: internal.QualityIssueDuplicateRegistration, Message: "same registration as record 1"},
+			{Record: 3, Id: 3, Field: "registration", Kind: internal.QualityIssueOutOfRange, Message: "must have 2 to 10 letters, digits or inner hyphens, not only zeros"},
+			{Record: 3, Id: 3, Field: "color", Kind: internal.QualityIssueMissingField, Message: "is empty"},
+			{Record: 3, Id: 3, Field: "year", Kind: internal.QualityIssueOutOfRange, Message: "must be between 1886 and " + maxYear()},
+			{Record: 3, Id: 3, Field: "max_speed", Kind: internal.QualityIssueOutOfRange, Message: "must not be negative"},
+			{Record: 3, Id: 3, Field: "fuel_type", Kind: internal.QualityIssueUnknownValue, Message: "must be one of gas, gasoline, diesel, biodiesel, electric, hybrid"},
+			{Record: 3, Id: 3, Field: "length", Kind: internal.QualityIssueMissingField, Message: "is zero"},
+			{Record: 3, Id: 3, Field: "width", Kind: internal.QualityIssueMissingField, Message: "is missing"},
+		}
+		expectedCounts := map[internal.QualityIssueKind]int{
+			internal.QualityIssueDuplicateId:           1,
+			internal.QualityIssueDuplicateRegistration: 1,
+			internal.QualityIssueMissingField:          3,
+			internal.QualityIssueOutOfRange:            3,
+			internal.QualityIssueUnknownValue:          1,
+		}
+		// When
+		result := loader.CheckVehicleRecords(records)
+		// Then
+		require.Equal(t, 3, result.Records)
+		require.Equal(t, expectedIssues, result.Issues)
+		require.Equal(t, expectedCounts, result.Counts())
+	})
+}
+
+func TestQualityCheckerVehicleFile_Check(t *testing.T) {
+	t.Run("Check the records of a JSON file", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		err := os.WriteFile(path, []byte("["+fmtRecord(1, "AB-123")+","+fmtRecord(1, "CD-456")+"]"), 0o644)
+		require.NoError(t, err)
+
+		// When
+		result, err := loader.NewQualityCheckerVehicleFile(path).Check()
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Records)
+		require.Len(t, result.Issues, 1)
+		require.Equal(t, internal.QualityIssueDuplicateId, result.Issues[0].Kind)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.xml")
+
+		// When
+		_, err := loader.NewQualityCheckerVehicleFile(path).Check()
+		// Then
+		require.ErrorIs(t, err, loader.ErrLoaderUnsupportedFormat)
+	})
+}
+
+// fmtRecord is a function that returns a valid record of a vehicle in JSON format
+func fmtRecord(id int, registration string) string {
+	return fmt.Sprintf(validRecordJSON, id, registration)
+}
+
+// maxYear is a function that returns the last accepted fabrication year as text
+func maxYear() string {
+	return strconv.Itoa(internal.MaxFabricationYear())
+}
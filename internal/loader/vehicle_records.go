@@ -0,0 +1,76 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VehicleRecord is a struct that represents a record of a file of vehicles as it was read
+// - unlike Load, records with the same id are all kept
+type VehicleRecord struct {
+	// Vehicle is the vehicle of the record, missing fields have their zero value
+	Vehicle VehicleJSON
+	// Missing are the names of the fields that are not in the record, as in the JSON format
+	Missing []string
+}
+
+// ReadVehicleRecords is a function that reads the records of the file of vehicles, with the format given by the extension of the path
+func ReadVehicleRecords(path string) (v []VehicleRecord, err error) {
+	var decode func(io.Reader) ([]VehicleRecord, error)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decode = DecodeRecordsJSON
+	case ".csv":
+		decode = DecodeRecordsCSV
+	default:
+		err = fmt.Errorf("%w: %q", ErrLoaderUnsupportedFormat, ext)
+		return
+	}
+
+	// open file
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	v, err = decode(file)
+	return
+}
+
+// DecodeRecordsJSON is a function that decodes the records of a JSON array of vehicles, keeping their missing fields
+// - a field is missing when its key is not in the object or its value is null
+func DecodeRecordsJSON(r io.Reader) (v []VehicleRecord, err error) {
+	var raws []json.RawMessage
+	err = json.NewDecoder(r).Decode(&raws)
+	if err != nil {
+		return
+	}
+
+	v = make([]VehicleRecord, len(raws))
+	for i, raw := range raws {
+		var fields map[string]json.RawMessage
+		err = json.Unmarshal(raw, &fields)
+		if err != nil {
+			err = fmt.Errorf("record %d: %w", i+1, err)
+			return
+		}
+		err = json.Unmarshal(raw, &v[i].Vehicle)
+		if err != nil {
+			err = fmt.Errorf("record %d: %w", i+1, err)
+			return
+		}
+		for _, name := range CSVHeader {
+			if value, ok := fields[name]; !ok || string(value) == "null" {
+				v[i].Missing = append(v[i].Missing, name)
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,55 @@
+package loader_test
+
+import (
+	"app/internal/loader"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRecordsJSON(t *testing.T) {
+	t.Run("Keep records with duplicate ids and their missing fields", func(t *testing.T) {
+		// Given
+		input := `[{"id":1,"brand":"A","length":null},{"id":1,"brand":"B"}]`
+
+		expectedMissing := []string{
+			"model", "registration", "color", "year", "passengers", "max_speed",
+			"fuel_type", "transmission", "weight", "height", "length", "width",
+		}
+		// When
+		result, err := loader.DecodeRecordsJSON(strings.NewReader(input))
+		// Then
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, loader.VehicleJSON{Id: 1, Brand: "A"}, result[0].Vehicle)
+		require.Equal(t, loader.VehicleJSON{Id: 1, Brand: "B"}, result[1].Vehicle)
+		require.Equal(t, expectedMissing, result[0].Missing)
+		require.Equal(t, expectedMissing, result[1].Missing)
+	})
+
+	t.Run("Invalid record reports its position", func(t *testing.T) {
+		// Given
+		input := `[{"id":1},{"id":"two"}]`
+
+		// When
+		_, err := loader.DecodeRecordsJSON(strings.NewReader(input))
+		// Then
+		require.ErrorContains(t, err, "record 2:")
+	})
+}
+
+func TestDecodeRecordsCSV(t *testing.T) {
+	t.Run("Columns not in the header and empty values are missing", func(t *testing.T) {
+		// Given
+		input := "id,brand,model,registration,color,year,passengers,max_speed,fuel_type,transmission,weight,height,width\n" +
+			"1,A,B,AB12,Red,2010,4,180,diesel,manual,1000,,1.7\n"
+
+		// When
+		result, err := loader.DecodeRecordsCSV(strings.NewReader(input))
+		// Then
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, []string{"height", "length"}, result[0].Missing)
+	})
+}
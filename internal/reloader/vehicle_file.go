@@ -0,0 +1,219 @@
+package reloader
+
+import (
+	"app/internal"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewReloaderVehicleFile is a function that returns a new instance of ReloaderVehicleFile
+// - st is the storer of the application that writes the file, nil if it is not written
+func NewReloaderVehicleFile(ld internal.LoaderVehicle, rp internal.RepositoryWriteVehicle, st *StorerVehicleFile, path string, interval time.Duration) *ReloaderVehicleFile {
+	return &ReloaderVehicleFile{
+		ld:       ld,
+		rp:       rp,
+		st:       st,
+		path:     path,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+}
+
+// ReloaderVehicleFile is a struct that implements the ReloaderVehicle interface
+// - method: polling. The modification time and size of the file are checked every interval,
+// and the vehicles of the repository are replaced when they change
+// - the file written by the storer is not reloaded, its vehicles are already in the repository
+type ReloaderVehicleFile struct {
+	// ld is the loader used to read the file
+	ld internal.LoaderVehicle
+	// rp is the repository whose vehicles are replaced
+	rp internal.RepositoryWriteVehicle
+	// st is the storer that writes the file, nil if it is not written
+	st *StorerVehicleFile
+	// path is the path to the file that is watched
+	path string
+	// interval is the time between checks of the file
+	interval time.Duration
+
+	// mu is the mutex that protects the state of the reloader and serializes the reloads
+	mu sync.Mutex
+	// modTime is the modification time of the file in the last reload
+	modTime time.Time
+	// size is the size of the file in the last reload
+	size int64
+	// status is the outcome of the reloads
+	status internal.ReloadStatus
+
+	// done is the channel used to stop watching
+	done chan struct{}
+	// wg waits for the watch to finish
+	wg sync.WaitGroup
+	// stopOnce guards the close of done
+	stopOnce sync.Once
+}
+
+// Reload is a method that loads the file and replaces the vehicles of the repository, whether the file changed or not
+// - on error the vehicles of the repository are kept
+func (r *ReloaderVehicleFile) Reload() (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		r.failed(err)
+		return
+	}
+
+	err = r.reload(info)
+	return
+}
+
+// Check is a method that reloads the file only if it changed since the last reload
+func (r *ReloaderVehicleFile) Check() (reloaded bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, records, stored, err := r.stat()
+	if err != nil {
+		r.failed(err)
+		return
+	}
+	if info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return
+	}
+	if stored {
+		r.adopt(info, records)
+		return
+	}
+
+	err = r.reload(info)
+	reloaded = err == nil
+	return
+}
+
+// Status is a method that returns the outcome of the reloads
+func (r *ReloaderVehicleFile) Status() (s internal.ReloadStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s = r.status
+	return
+}
+
+// Start is a method that starts watching the file in background until Stop is called
+func (r *ReloaderVehicleFile) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.Check()
+			}
+		}
+	}()
+}
+
+// Stop is a method that stops watching the file
+func (r *ReloaderVehicleFile) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+	})
+	r.wg.Wait()
+}
+
+// reload is a method that loads the file and replaces the vehicles of the repository
+// - mu must be held by the caller
+// - an invalid file is not read again until it changes, while a file the repository refused, e.g. over changes
+// not stored yet, is read again by the next check
+func (r *ReloaderVehicleFile) reload(info os.FileInfo) (err error) {
+	checksum, err := fileChecksum(r.path)
+	if err != nil {
+		r.failed(err)
+		return
+	}
+	v, err := r.ld.Load()
+	if err != nil {
+		r.modTime = info.ModTime()
+		r.size = info.Size()
+		r.failed(err)
+		return
+	}
+	err = r.rp.Replace(v)
+	if err != nil {
+		r.failed(err)
+		return
+	}
+
+	r.modTime = info.ModTime()
+	r.size = info.Size()
+	now := time.Now()
+	r.status.LastReload = now
+	r.status.LastAttempt = now
+	r.status.LastError = nil
+	r.status.Records = len(v)
+	r.status.Checksum = checksum
+	r.status.Succeeded++
+	return
+}
+
+// stat is a method that returns the state of the file, and the number of vehicles stored if it was written by the storer
+func (r *ReloaderVehicleFile) stat() (info os.FileInfo, records int, stored bool, err error) {
+	if r.st == nil {
+		info, err = os.Stat(r.path)
+		return
+	}
+	info, records, stored, err = r.st.stat()
+	return
+}
+
+// adopt is a method that takes the file written by the storer as loaded, without replacing the vehicles of the repository
+// - the file holds the vehicles of the repository, so the error of a previous reload no longer applies
+// - mu must be held by the caller
+func (r *ReloaderVehicleFile) adopt(info os.FileInfo, records int) {
+	r.modTime = info.ModTime()
+	r.size = info.Size()
+
+	checksum, err := fileChecksum(r.path)
+	if err != nil {
+		// the checksum is known again with the next store or reload
+		checksum = ""
+	}
+	r.status.LastError = nil
+	r.status.Records = records
+	r.status.Checksum = checksum
+}
+
+// fileChecksum is a function that returns the sha256 checksum of the content of a file
+func fileChecksum(path string) (checksum string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return
+	}
+	checksum = "sha256:" + hex.EncodeToString(h.Sum(nil))
+	return
+}
+
+// failed is a method that records a failed reload
+// - mu must be held by the caller
+func (r *ReloaderVehicleFile) failed(err error) {
+	r.status.LastAttempt = time.Now()
+	r.status.LastError = err
+	r.status.Failed++
+}
@@ -0,0 +1,60 @@
+package reloader
+
+import (
+	"app/internal"
+	"time"
+)
+
+// NewSeederVehicleFile is a function that returns a new instance of SeederVehicleFile
+func NewSeederVehicleFile(ld internal.LoaderVehicle, rp internal.RepositoryVehicle, path string) *SeederVehicleFile {
+	return &SeederVehicleFile{
+		rp: rp,
+		rl: NewReloaderVehicleFile(ld, rp, nil, path, 0),
+	}
+}
+
+// SeederVehicleFile is a struct that implements the ReloaderVehicle interface loading the file only in an empty repository
+// - used with databases, that keep the vehicles across starts: the file only seeds them, it is neither watched nor written
+type SeederVehicleFile struct {
+	// rp is the repository that is seeded
+	rp internal.RepositoryVehicle
+	// rl is the reloader that loads the file in rp
+	rl *ReloaderVehicleFile
+
+	// seeded is whether the file was loaded by the last Seed
+	seeded bool
+	// status is the outcome of the last Seed that did not load the file
+	status internal.ReloadStatus
+}
+
+// Seed is a method that loads the file in the repository if it has no vehicles, and returns whether it did
+// - it must be called before the vehicles are served, Status is not guarded against it
+func (s *SeederVehicleFile) Seed() (seeded bool, err error) {
+	n, err := s.rp.Count()
+	if err != nil {
+		return
+	}
+	if n > 0 {
+		now := time.Now()
+		s.seeded = false
+		s.status = internal.ReloadStatus{LastReload: now, LastAttempt: now, Records: n}
+		return
+	}
+
+	err = s.rl.Reload()
+	if err != nil {
+		return
+	}
+	s.seeded = true
+	seeded = true
+	return
+}
+
+// Status is a method that returns the outcome of the seed
+// - the vehicles of the repository count as loaded when they were already there, with no checksum since the file was not read
+func (s *SeederVehicleFile) Status() internal.ReloadStatus {
+	if s.seeded {
+		return s.rl.Status()
+	}
+	return s.status
+}
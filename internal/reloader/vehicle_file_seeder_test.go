@@ -0,0 +1,70 @@
+package reloader_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/reloader"
+	"app/internal/repository"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeederVehicleFile_Seed(t *testing.T) {
+	t.Run("Load the file in an empty repository", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, time.Now())
+		rp := repository.NewRepositoryReadVehicleMap(nil)
+		sd := reloader.NewSeederVehicleFile(loader.NewLoaderVehicleJSON(path), rp, path)
+
+		// When
+		seeded, err := sd.Seed()
+		// Then
+		require.NoError(t, err)
+		require.True(t, seeded)
+		n, _ := rp.Count()
+		require.Equal(t, 1, n)
+		require.Equal(t, 1, sd.Status().Records)
+		require.Equal(t, 1, sd.Status().Succeeded)
+		require.NotEmpty(t, sd.Status().Checksum)
+	})
+
+	t.Run("Keep the vehicles of a filled repository", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, time.Now())
+		rp := repository.NewRepositoryReadVehicleMap(map[int]internal.Vehicle{
+			7: {Id: 7, VehicleAttributes: internal.VehicleAttributes{Brand: "B"}},
+			8: {Id: 8, VehicleAttributes: internal.VehicleAttributes{Brand: "C"}},
+		})
+		sd := reloader.NewSeederVehicleFile(loader.NewLoaderVehicleJSON(path), rp, path)
+
+		// When
+		seeded, err := sd.Seed()
+		// Then
+		require.NoError(t, err)
+		require.False(t, seeded)
+		_, err = rp.FindById(1)
+		require.ErrorIs(t, err, internal.ErrRepositoryVehicleNotFound)
+		require.Equal(t, 2, sd.Status().Records)
+		require.False(t, sd.Status().LastReload.IsZero())
+		require.Nil(t, sd.Status().LastError)
+		require.Zero(t, sd.Status().Succeeded)
+	})
+
+	t.Run("Fail on a file that can not be loaded", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		rp := repository.NewRepositoryReadVehicleMap(nil)
+		sd := reloader.NewSeederVehicleFile(loader.NewLoaderVehicleJSON(path), rp, path)
+
+		// When
+		seeded, err := sd.Seed()
+		// Then
+		require.Error(t, err)
+		require.False(t, seeded)
+	})
+}
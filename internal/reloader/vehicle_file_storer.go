@@ -0,0 +1,68 @@
+package reloader
+
+import (
+	"app/internal"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewStorerVehicleFile is a function that returns a new instance of StorerVehicleFile
+func NewStorerVehicleFile(st internal.StorerVehicle, path string) *StorerVehicleFile {
+	return &StorerVehicleFile{
+		st:   st,
+		path: path,
+	}
+}
+
+// StorerVehicleFile is a struct that implements the StorerVehicle interface recording the state of the file after each store
+// - the reloader skips the file while it is as the storer left it, so the vehicles are not loaded back from their own store
+type StorerVehicleFile struct {
+	// st is the storer that writes the file
+	st internal.StorerVehicle
+	// path is the path to the file written by st
+	path string
+
+	// mu is the mutex that protects the state of the last store and serializes the stores
+	mu sync.Mutex
+	// modTime is the modification time of the file after the last store
+	modTime time.Time
+	// size is the size of the file after the last store
+	size int64
+	// records is the number of vehicles of the last store
+	records int
+}
+
+// Store is a method that stores the vehicles and records the state of the file
+func (s *StorerVehicleFile) Store(v map[int]internal.Vehicle) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.st.Store(v)
+	if err != nil {
+		return
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return
+	}
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	s.records = len(v)
+	return
+}
+
+// stat is a method that returns the state of the file, and the number of vehicles stored if it is as the last store left it
+// - a store in progress is waited for, so the file is never seen before its state is recorded
+func (s *StorerVehicleFile) stat() (info os.FileInfo, records int, stored bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err = os.Stat(s.path)
+	if err != nil {
+		return
+	}
+	stored = !s.modTime.IsZero() && info.ModTime().Equal(s.modTime) && info.Size() == s.size
+	records = s.records
+	return
+}
@@ -0,0 +1,225 @@
+package reloader_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/reloader"
+	"app/internal/repository"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes the content in the file and moves its modification time forward
+func writeFile(t *testing.T, path string, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestReloaderVehicleFile_Check(t *testing.T) {
+	t.Run("Reload when the file changes", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		now := time.Now()
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, now)
+		rp := repository.NewRepositoryReadVehicleMap(nil)
+		rl := reloader.NewReloaderVehicleFile(loader.NewLoaderVehicleJSON(path), rp, nil, path, time.Hour)
+		require.NoError(t, rl.Reload())
+
+		expectedResult := map[int]internal.Vehicle{2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "B",
+			},
+		}}
+		// When
+		unchanged, errUnchanged := rl.Check()
+		writeFile(t, path, `[{"id":2,"brand":"B"}]`, now.Add(time.Second))
+		changed, errChanged := rl.Check()
+		// Then
+		require.NoError(t, errUnchanged)
+		require.False(t, unchanged)
+		require.NoError(t, errChanged)
+		require.True(t, changed)
+		result, _ := rp.FindAll()
+		require.Equal(t, expectedResult, result)
+		require.Nil(t, rl.Status().LastError)
+		require.False(t, rl.Status().LastReload.IsZero())
+	})
+
+	t.Run("Keep the vehicles when the file is invalid", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		now := time.Now()
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, now)
+		rp := repository.NewRepositoryReadVehicleMap(nil)
+		rl := reloader.NewReloaderVehicleFile(loader.NewLoaderVehicleJSON(path), rp, nil, path, time.Hour)
+		require.NoError(t, rl.Reload())
+		lastReload := rl.Status().LastReload
+
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+			},
+		}}
+		// When
+		writeFile(t, path, `[{"id":2,"brand":`, now.Add(time.Second))
+		reloaded, err := rl.Check()
+		// Then
+		require.Error(t, err)
+		require.False(t, reloaded)
+		result, _ := rp.FindAll()
+		require.Equal(t, expectedResult, result)
+		require.Equal(t, err, rl.Status().LastError)
+		require.Equal(t, lastReload, rl.Status().LastReload)
+		require.Equal(t, 1, rl.Status().Succeeded)
+		require.Equal(t, 1, rl.Status().Failed)
+	})
+}
+
+func TestReloaderVehicleFile_Reload(t *testing.T) {
+	t.Run("Record the number of vehicles and the checksum of the file", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		content := `[{"id":1,"brand":"A"},{"id":2,"brand":"B"}]`
+		writeFile(t, path, content, time.Now())
+		rp := repository.NewRepositoryReadVehicleMap(nil)
+		rl := reloader.NewReloaderVehicleFile(loader.NewLoaderVehicleJSON(path), rp, nil, path, time.Hour)
+
+		sum := sha256.Sum256([]byte(content))
+		expectedChecksum := "sha256:" + hex.EncodeToString(sum[:])
+		// When
+		err := rl.Reload()
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, 2, rl.Status().Records)
+		require.Equal(t, expectedChecksum, rl.Status().Checksum)
+	})
+}
+
+func TestReloaderVehicleFile_Start(t *testing.T) {
+	t.Run("Reload in background until stopped", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		now := time.Now()
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, now)
+		rp := repository.NewRepositoryReadVehicleMap(nil)
+		rl := reloader.NewReloaderVehicleFile(loader.NewLoaderVehicleJSON(path), rp, nil, path, 10*time.Millisecond)
+		require.NoError(t, rl.Reload())
+
+		// When
+		rl.Start()
+		defer rl.Stop()
+		writeFile(t, path, `[{"id":2,"brand":"B"}]`, now.Add(time.Second))
+		// Then
+		require.Eventually(t, func() bool {
+			result, _ := rp.FindAll()
+			_, ok := result[2]
+			return ok
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+// refusingRepository is a repository that refuses to replace its vehicles while refuse is set
+type refusingRepository struct {
+	*repository.RepositoryReadVehicleMap
+	refuse bool
+}
+
+func (r *refusingRepository) Replace(v map[int]internal.Vehicle) (err error) {
+	if r.refuse {
+		return internal.ErrRepositoryVehiclePending
+	}
+	return r.RepositoryReadVehicleMap.Replace(v)
+}
+
+func TestReloaderVehicleFile_Refused(t *testing.T) {
+	t.Run("Read again a file the repository refused", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		now := time.Now().Add(-time.Minute)
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, now)
+		rp := &refusingRepository{RepositoryReadVehicleMap: repository.NewRepositoryReadVehicleMap(nil)}
+		rl := reloader.NewReloaderVehicleFile(loader.NewLoaderVehicleJSON(path), rp, nil, path, time.Hour)
+		require.NoError(t, rl.Reload())
+
+		// When
+		writeFile(t, path, `[{"id":2,"brand":"B"}]`, now.Add(time.Second))
+		rp.refuse = true
+		refused, errRefused := rl.Check()
+		lastError := rl.Status().LastError
+		rp.refuse = false
+		retried, errRetried := rl.Check()
+		// Then
+		require.ErrorIs(t, errRefused, internal.ErrRepositoryVehiclePending)
+		require.False(t, refused)
+		require.Equal(t, errRefused, lastError)
+		require.NoError(t, errRetried)
+		require.True(t, retried)
+		require.Nil(t, rl.Status().LastError)
+		_, err := rp.FindById(2)
+		require.NoError(t, err)
+	})
+}
+
+func TestReloaderVehicleFile_Storer(t *testing.T) {
+	t.Run("Do not reload the file written by the storer", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, time.Now().Add(-time.Minute))
+		sf := reloader.NewStorerVehicleFile(loader.NewStorerVehicleJSON(path), path)
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(nil), sf, 0)
+		rl := reloader.NewReloaderVehicleFile(loader.NewLoaderVehicleJSON(path), rp, sf, path, time.Hour)
+		require.NoError(t, rl.Reload())
+		checksum := rl.Status().Checksum
+
+		// When
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "B"}}
+		errSave := rp.Save(&v)
+		reloaded, err := rl.Check()
+		// Then
+		require.NoError(t, errSave)
+		require.NoError(t, err)
+		require.False(t, reloaded)
+		require.Equal(t, 1, rl.Status().Succeeded)
+		require.Equal(t, 2, rl.Status().Records)
+		require.NotEqual(t, checksum, rl.Status().Checksum)
+	})
+
+	t.Run("Do not replace changes not stored yet", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		now := time.Now().Add(-time.Minute)
+		writeFile(t, path, `[{"id":1,"brand":"A"}]`, now)
+		sf := reloader.NewStorerVehicleFile(loader.NewStorerVehicleJSON(path), path)
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(nil), sf, time.Hour)
+		rl := reloader.NewReloaderVehicleFile(loader.NewLoaderVehicleJSON(path), rp, sf, path, time.Hour)
+		require.NoError(t, rl.Reload())
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "B"}}
+		require.NoError(t, rp.Save(&v))
+
+		// When
+		writeFile(t, path, `[{"id":3,"brand":"C"}]`, now.Add(time.Second))
+		reloaded, err := rl.Check()
+		errFlush := rp.Flush()
+		reloadedAfterFlush, errAfterFlush := rl.Check()
+		// Then
+		require.ErrorIs(t, err, internal.ErrRepositoryVehiclePending)
+		require.False(t, reloaded)
+		require.NoError(t, errFlush)
+		require.NoError(t, errAfterFlush)
+		require.False(t, reloadedAfterFlush)
+		require.Nil(t, rl.Status().LastError)
+		result, _ := rp.FindAll()
+		require.Equal(t, map[int]internal.Vehicle{1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "A"}}, 2: v}, result)
+		stored, _ := loader.NewLoaderVehicleJSON(path).Load()
+		require.Equal(t, result, stored)
+	})
+}
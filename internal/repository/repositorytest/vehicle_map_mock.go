@@ -0,0 +1,545 @@
+// Code generated by mockgen. DO NOT EDIT.
+
+package repositorytest
+
+import (
+	"app/internal"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// NewVehicleMapMock is a function that returns a new instance of VehicleMapMock
+// - a call to a method without its function configured fails the test t
+func NewVehicleMapMock(t testing.TB) *VehicleMapMock {
+	return &VehicleMapMock{t: t}
+}
+
+// VehicleMapMock is a struct that implements the internal.RepositoryVehicle interface for tests
+// - each method records its arguments and calls the function of the field with its name and the suffix Func
+type VehicleMapMock struct {
+	// FindAllFunc is the function called by FindAll
+	FindAllFunc func() (v map[int]internal.Vehicle, err error)
+	// CountFunc is the function called by Count
+	CountFunc func() (n int, err error)
+	// CountByBrandFunc is the function called by CountByBrand
+	CountByBrandFunc func() (n map[string]int, err error)
+	// FindByIdFunc is the function called by FindById
+	FindByIdFunc func(id int) (v internal.Vehicle, err error)
+	// FindByRegistrationFunc is the function called by FindByRegistration
+	FindByRegistrationFunc func(registration string) (v internal.Vehicle, err error)
+	// FindByColorAndYearFunc is the function called by FindByColorAndYear
+	FindByColorAndYearFunc func(color string, fabricationYear int) (v map[int]internal.Vehicle, err error)
+	// FindByBrandAndYearRangeFunc is the function called by FindByBrandAndYearRange
+	FindByBrandAndYearRangeFunc func(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error)
+	// FindByBrandFunc is the function called by FindByBrand
+	FindByBrandFunc func(brand string) (v map[int]internal.Vehicle, err error)
+	// FindByWeightRangeFunc is the function called by FindByWeightRange
+	FindByWeightRangeFunc func(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error)
+	// FindByFilterFunc is the function called by FindByFilter
+	FindByFilterFunc func(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error)
+	// SaveFunc is the function called by Save
+	SaveFunc func(v *internal.Vehicle) (err error)
+	// UpdateFunc is the function called by Update
+	UpdateFunc func(v internal.Vehicle) (err error)
+	// PatchFunc is the function called by Patch
+	PatchFunc func(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error)
+	// DeleteFunc is the function called by Delete
+	DeleteFunc func(id int) (err error)
+	// ReplaceFunc is the function called by Replace
+	ReplaceFunc func(v map[int]internal.Vehicle) (err error)
+
+	// t is the test that uses the mock
+	t testing.TB
+	// mu is the mutex that guards the calls
+	mu sync.Mutex
+	// calls are the names of the methods called, in order
+	calls []string
+	// findAllCalls are the arguments of the calls to FindAll, in order
+	findAllCalls []VehicleMapMockFindAllCall
+	// countCalls are the arguments of the calls to Count, in order
+	countCalls []VehicleMapMockCountCall
+	// countByBrandCalls are the arguments of the calls to CountByBrand, in order
+	countByBrandCalls []VehicleMapMockCountByBrandCall
+	// findByIdCalls are the arguments of the calls to FindById, in order
+	findByIdCalls []VehicleMapMockFindByIdCall
+	// findByRegistrationCalls are the arguments of the calls to FindByRegistration, in order
+	findByRegistrationCalls []VehicleMapMockFindByRegistrationCall
+	// findByColorAndYearCalls are the arguments of the calls to FindByColorAndYear, in order
+	findByColorAndYearCalls []VehicleMapMockFindByColorAndYearCall
+	// findByBrandAndYearRangeCalls are the arguments of the calls to FindByBrandAndYearRange, in order
+	findByBrandAndYearRangeCalls []VehicleMapMockFindByBrandAndYearRangeCall
+	// findByBrandCalls are the arguments of the calls to FindByBrand, in order
+	findByBrandCalls []VehicleMapMockFindByBrandCall
+	// findByWeightRangeCalls are the arguments of the calls to FindByWeightRange, in order
+	findByWeightRangeCalls []VehicleMapMockFindByWeightRangeCall
+	// findByFilterCalls are the arguments of the calls to FindByFilter, in order
+	findByFilterCalls []VehicleMapMockFindByFilterCall
+	// saveCalls are the arguments of the calls to Save, in order
+	saveCalls []VehicleMapMockSaveCall
+	// updateCalls are the arguments of the calls to Update, in order
+	updateCalls []VehicleMapMockUpdateCall
+	// patchCalls are the arguments of the calls to Patch, in order
+	patchCalls []VehicleMapMockPatchCall
+	// deleteCalls are the arguments of the calls to Delete, in order
+	deleteCalls []VehicleMapMockDeleteCall
+	// replaceCalls are the arguments of the calls to Replace, in order
+	replaceCalls []VehicleMapMockReplaceCall
+}
+
+// Calls is a method that returns the names of the methods called, in order
+func (mk *VehicleMapMock) Calls() []string {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.calls)
+}
+
+// AssertCalls is a method that checks that the methods called are the methods given, in the same order
+func (mk *VehicleMapMock) AssertCalls(methods ...string) bool {
+	mk.t.Helper()
+	calls := mk.Calls()
+	if !slices.Equal(calls, methods) {
+		mk.t.Errorf("VehicleMapMock: calls %v, expected %v", calls, methods)
+		return false
+	}
+	return true
+}
+
+// VehicleMapMockFindAllCall is a struct that represents the arguments of a call to FindAll
+type VehicleMapMockFindAllCall struct {
+}
+
+// FindAll is a method that records the call and returns the results of FindAllFunc
+func (mk *VehicleMapMock) FindAll() (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindAll")
+	mk.findAllCalls = append(mk.findAllCalls, VehicleMapMockFindAllCall{})
+	fn := mk.FindAllFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindAll, FindAllFunc is not configured")
+		return
+	}
+	return fn()
+}
+
+// FindAllCalls is a method that returns the arguments of the calls to FindAll, in order
+func (mk *VehicleMapMock) FindAllCalls() []VehicleMapMockFindAllCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findAllCalls)
+}
+
+// VehicleMapMockCountCall is a struct that represents the arguments of a call to Count
+type VehicleMapMockCountCall struct {
+}
+
+// Count is a method that records the call and returns the results of CountFunc
+func (mk *VehicleMapMock) Count() (n int, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Count")
+	mk.countCalls = append(mk.countCalls, VehicleMapMockCountCall{})
+	fn := mk.CountFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to Count, CountFunc is not configured")
+		return
+	}
+	return fn()
+}
+
+// CountCalls is a method that returns the arguments of the calls to Count, in order
+func (mk *VehicleMapMock) CountCalls() []VehicleMapMockCountCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.countCalls)
+}
+
+// VehicleMapMockCountByBrandCall is a struct that represents the arguments of a call to CountByBrand
+type VehicleMapMockCountByBrandCall struct {
+}
+
+// CountByBrand is a method that records the call and returns the results of CountByBrandFunc
+func (mk *VehicleMapMock) CountByBrand() (n map[string]int, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "CountByBrand")
+	mk.countByBrandCalls = append(mk.countByBrandCalls, VehicleMapMockCountByBrandCall{})
+	fn := mk.CountByBrandFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to CountByBrand, CountByBrandFunc is not configured")
+		return
+	}
+	return fn()
+}
+
+// CountByBrandCalls is a method that returns the arguments of the calls to CountByBrand, in order
+func (mk *VehicleMapMock) CountByBrandCalls() []VehicleMapMockCountByBrandCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.countByBrandCalls)
+}
+
+// VehicleMapMockFindByIdCall is a struct that represents the arguments of a call to FindById
+type VehicleMapMockFindByIdCall struct {
+	// Id is the argument id
+	Id int
+}
+
+// FindById is a method that records the call and returns the results of FindByIdFunc
+func (mk *VehicleMapMock) FindById(id int) (v internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindById")
+	mk.findByIdCalls = append(mk.findByIdCalls, VehicleMapMockFindByIdCall{Id: id})
+	fn := mk.FindByIdFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindById, FindByIdFunc is not configured")
+		return
+	}
+	return fn(id)
+}
+
+// FindByIdCalls is a method that returns the arguments of the calls to FindById, in order
+func (mk *VehicleMapMock) FindByIdCalls() []VehicleMapMockFindByIdCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByIdCalls)
+}
+
+// VehicleMapMockFindByRegistrationCall is a struct that represents the arguments of a call to FindByRegistration
+type VehicleMapMockFindByRegistrationCall struct {
+	// Registration is the argument registration
+	Registration string
+}
+
+// FindByRegistration is a method that records the call and returns the results of FindByRegistrationFunc
+func (mk *VehicleMapMock) FindByRegistration(registration string) (v internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByRegistration")
+	mk.findByRegistrationCalls = append(mk.findByRegistrationCalls, VehicleMapMockFindByRegistrationCall{Registration: registration})
+	fn := mk.FindByRegistrationFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindByRegistration, FindByRegistrationFunc is not configured")
+		return
+	}
+	return fn(registration)
+}
+
+// FindByRegistrationCalls is a method that returns the arguments of the calls to FindByRegistration, in order
+func (mk *VehicleMapMock) FindByRegistrationCalls() []VehicleMapMockFindByRegistrationCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByRegistrationCalls)
+}
+
+// VehicleMapMockFindByColorAndYearCall is a struct that represents the arguments of a call to FindByColorAndYear
+type VehicleMapMockFindByColorAndYearCall struct {
+	// Color is the argument color
+	Color string
+	// FabricationYear is the argument fabricationYear
+	FabricationYear int
+}
+
+// FindByColorAndYear is a method that records the call and returns the results of FindByColorAndYearFunc
+func (mk *VehicleMapMock) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByColorAndYear")
+	mk.findByColorAndYearCalls = append(mk.findByColorAndYearCalls, VehicleMapMockFindByColorAndYearCall{Color: color, FabricationYear: fabricationYear})
+	fn := mk.FindByColorAndYearFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindByColorAndYear, FindByColorAndYearFunc is not configured")
+		return
+	}
+	return fn(color, fabricationYear)
+}
+
+// FindByColorAndYearCalls is a method that returns the arguments of the calls to FindByColorAndYear, in order
+func (mk *VehicleMapMock) FindByColorAndYearCalls() []VehicleMapMockFindByColorAndYearCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByColorAndYearCalls)
+}
+
+// VehicleMapMockFindByBrandAndYearRangeCall is a struct that represents the arguments of a call to FindByBrandAndYearRange
+type VehicleMapMockFindByBrandAndYearRangeCall struct {
+	// Brand is the argument brand
+	Brand string
+	// StartYear is the argument startYear
+	StartYear int
+	// EndYear is the argument endYear
+	EndYear int
+}
+
+// FindByBrandAndYearRange is a method that records the call and returns the results of FindByBrandAndYearRangeFunc
+func (mk *VehicleMapMock) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByBrandAndYearRange")
+	mk.findByBrandAndYearRangeCalls = append(mk.findByBrandAndYearRangeCalls, VehicleMapMockFindByBrandAndYearRangeCall{Brand: brand, StartYear: startYear, EndYear: endYear})
+	fn := mk.FindByBrandAndYearRangeFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindByBrandAndYearRange, FindByBrandAndYearRangeFunc is not configured")
+		return
+	}
+	return fn(brand, startYear, endYear)
+}
+
+// FindByBrandAndYearRangeCalls is a method that returns the arguments of the calls to FindByBrandAndYearRange, in order
+func (mk *VehicleMapMock) FindByBrandAndYearRangeCalls() []VehicleMapMockFindByBrandAndYearRangeCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByBrandAndYearRangeCalls)
+}
+
+// VehicleMapMockFindByBrandCall is a struct that represents the arguments of a call to FindByBrand
+type VehicleMapMockFindByBrandCall struct {
+	// Brand is the argument brand
+	Brand string
+}
+
+// FindByBrand is a method that records the call and returns the results of FindByBrandFunc
+func (mk *VehicleMapMock) FindByBrand(brand string) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByBrand")
+	mk.findByBrandCalls = append(mk.findByBrandCalls, VehicleMapMockFindByBrandCall{Brand: brand})
+	fn := mk.FindByBrandFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindByBrand, FindByBrandFunc is not configured")
+		return
+	}
+	return fn(brand)
+}
+
+// FindByBrandCalls is a method that returns the arguments of the calls to FindByBrand, in order
+func (mk *VehicleMapMock) FindByBrandCalls() []VehicleMapMockFindByBrandCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByBrandCalls)
+}
+
+// VehicleMapMockFindByWeightRangeCall is a struct that represents the arguments of a call to FindByWeightRange
+type VehicleMapMockFindByWeightRangeCall struct {
+	// FromWeight is the argument fromWeight
+	FromWeight float64
+	// ToWeight is the argument toWeight
+	ToWeight float64
+}
+
+// FindByWeightRange is a method that records the call and returns the results of FindByWeightRangeFunc
+func (mk *VehicleMapMock) FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByWeightRange")
+	mk.findByWeightRangeCalls = append(mk.findByWeightRangeCalls, VehicleMapMockFindByWeightRangeCall{FromWeight: fromWeight, ToWeight: toWeight})
+	fn := mk.FindByWeightRangeFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindByWeightRange, FindByWeightRangeFunc is not configured")
+		return
+	}
+	return fn(fromWeight, toWeight)
+}
+
+// FindByWeightRangeCalls is a method that returns the arguments of the calls to FindByWeightRange, in order
+func (mk *VehicleMapMock) FindByWeightRangeCalls() []VehicleMapMockFindByWeightRangeCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByWeightRangeCalls)
+}
+
+// VehicleMapMockFindByFilterCall is a struct that represents the arguments of a call to FindByFilter
+type VehicleMapMockFindByFilterCall struct {
+	// Filter is the argument filter
+	Filter internal.VehicleFilter
+}
+
+// FindByFilter is a method that records the call and returns the results of FindByFilterFunc
+func (mk *VehicleMapMock) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByFilter")
+	mk.findByFilterCalls = append(mk.findByFilterCalls, VehicleMapMockFindByFilterCall{Filter: filter})
+	fn := mk.FindByFilterFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to FindByFilter, FindByFilterFunc is not configured")
+		return
+	}
+	return fn(filter)
+}
+
+// FindByFilterCalls is a method that returns the arguments of the calls to FindByFilter, in order
+func (mk *VehicleMapMock) FindByFilterCalls() []VehicleMapMockFindByFilterCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByFilterCalls)
+}
+
+// VehicleMapMockSaveCall is a struct that represents the arguments of a call to Save
+type VehicleMapMockSaveCall struct {
+	// V is the argument v
+	V *internal.Vehicle
+}
+
+// Save is a method that records the call and returns the results of SaveFunc
+func (mk *VehicleMapMock) Save(v *internal.Vehicle) (err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Save")
+	mk.saveCalls = append(mk.saveCalls, VehicleMapMockSaveCall{V: v})
+	fn := mk.SaveFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to Save, SaveFunc is not configured")
+		return
+	}
+	return fn(v)
+}
+
+// SaveCalls is a method that returns the arguments of the calls to Save, in order
+func (mk *VehicleMapMock) SaveCalls() []VehicleMapMockSaveCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.saveCalls)
+}
+
+// VehicleMapMockUpdateCall is a struct that represents the arguments of a call to Update
+type VehicleMapMockUpdateCall struct {
+	// V is the argument v
+	V internal.Vehicle
+}
+
+// Update is a method that records the call and returns the results of UpdateFunc
+func (mk *VehicleMapMock) Update(v internal.Vehicle) (err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Update")
+	mk.updateCalls = append(mk.updateCalls, VehicleMapMockUpdateCall{V: v})
+	fn := mk.UpdateFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to Update, UpdateFunc is not configured")
+		return
+	}
+	return fn(v)
+}
+
+// UpdateCalls is a method that returns the arguments of the calls to Update, in order
+func (mk *VehicleMapMock) UpdateCalls() []VehicleMapMockUpdateCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.updateCalls)
+}
+
+// VehicleMapMockPatchCall is a struct that represents the arguments of a call to Patch
+type VehicleMapMockPatchCall struct {
+	// Id is the argument id
+	Id int
+	// Patch is the argument patch
+	Patch internal.VehiclePatch
+}
+
+// Patch is a method that records the call and returns the results of PatchFunc
+func (mk *VehicleMapMock) Patch(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Patch")
+	mk.patchCalls = append(mk.patchCalls, VehicleMapMockPatchCall{Id: id, Patch: patch})
+	fn := mk.PatchFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to Patch, PatchFunc is not configured")
+		return
+	}
+	return fn(id, patch)
+}
+
+// PatchCalls is a method that returns the arguments of the calls to Patch, in order
+func (mk *VehicleMapMock) PatchCalls() []VehicleMapMockPatchCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.patchCalls)
+}
+
+// VehicleMapMockDeleteCall is a struct that represents the arguments of a call to Delete
+type VehicleMapMockDeleteCall struct {
+	// Id is the argument id
+	Id int
+}
+
+// Delete is a method that records the call and returns the results of DeleteFunc
+func (mk *VehicleMapMock) Delete(id int) (err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Delete")
+	mk.deleteCalls = append(mk.deleteCalls, VehicleMapMockDeleteCall{Id: id})
+	fn := mk.DeleteFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to Delete, DeleteFunc is not configured")
+		return
+	}
+	return fn(id)
+}
+
+// DeleteCalls is a method that returns the arguments of the calls to Delete, in order
+func (mk *VehicleMapMock) DeleteCalls() []VehicleMapMockDeleteCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.deleteCalls)
+}
+
+// VehicleMapMockReplaceCall is a struct that represents the arguments of a call to Replace
+type VehicleMapMockReplaceCall struct {
+	// V is the argument v
+	V map[int]internal.Vehicle
+}
+
+// Replace is a method that records the call and returns the results of ReplaceFunc
+func (mk *VehicleMapMock) Replace(v map[int]internal.Vehicle) (err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Replace")
+	mk.replaceCalls = append(mk.replaceCalls, VehicleMapMockReplaceCall{V: v})
+	fn := mk.ReplaceFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleMapMock: unexpected call to Replace, ReplaceFunc is not configured")
+		return
+	}
+	return fn(v)
+}
+
+// ReplaceCalls is a method that returns the arguments of the calls to Replace, in order
+func (mk *VehicleMapMock) ReplaceCalls() []VehicleMapMockReplaceCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.replaceCalls)
+}
@@ -0,0 +1,795 @@
+// Package repositorytest provides the scenarios that every implementation of the vehicle repository must pass,
+// so a new implementation is tested by running them against it, and the mock of the repository
+// - it imports testing, so it must only be imported by tests
+package repositorytest
+
+import (
+	"app/internal"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Factory is a function that returns a new repository with the vehicles of db
+// - db may be nil, the repository is then empty
+// - the repository must not be affected by later changes of db
+type Factory func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle
+
+// RunReadVehicleContract is a function that runs the scenarios of every method of internal.RepositoryReadVehicle
+// against the repositories returned by factory, each scenario as a subtest
+func RunReadVehicleContract(t *testing.T, factory Factory) {
+	t.Run("FindAll", func(t *testing.T) { runFindAll(t, factory) })
+	t.Run("Count", func(t *testing.T) { runCount(t, factory) })
+	t.Run("FindById", func(t *testing.T) { runFindById(t, factory) })
+	t.Run("FindByRegistration", func(t *testing.T) { runFindByRegistration(t, factory) })
+	t.Run("FindByColorAndYear", func(t *testing.T) { runFindByColorAndYear(t, factory) })
+	t.Run("FindByBrandAndYearRange", func(t *testing.T) { runFindByBrandAndYearRange(t, factory) })
+	t.Run("FindByBrand", func(t *testing.T) { runFindByBrand(t, factory) })
+	t.Run("FindByWeightRange", func(t *testing.T) { runFindByWeightRange(t, factory) })
+	t.Run("FindByFilter", func(t *testing.T) { runFindByFilter(t, factory) })
+	t.Run("EmptyDb", func(t *testing.T) { runEmptyDb(t, factory) })
+	t.Run("InclusiveBounds", func(t *testing.T) { runInclusiveBounds(t, factory) })
+	t.Run("Results", func(t *testing.T) { runResults(t, factory) })
+}
+
+// runFindAll is a function that runs the scenarios of FindAll
+func runFindAll(t *testing.T, factory Factory) {
+	t.Run("Find all", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 1,
+			},
+		}, 2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 10,
+			},
+		}, 3: {
+			Id: 3,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 4,
+			},
+		}}
+
+		rp := factory(t, db)
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 1,
+			},
+		}, 2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 10,
+			},
+		}, 3: {
+			Id: 3,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 4,
+			},
+		}}
+		// When
+		result, err := rp.FindAll()
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("No vehicles found", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{}
+		rp := factory(t, db)
+
+		expectedResult := map[int]internal.Vehicle{}
+		// When
+		result, err := rp.FindAll()
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// runCount is a function that runs the scenarios of Count and CountByBrand
+func runCount(t *testing.T, factory Factory) {
+	t.Run("Count all and by brand", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "A"}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "B"}},
+			3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "A"}},
+		}
+		rp := factory(t, db)
+
+		expectedResult := map[string]int{"A": 2, "B": 1}
+		// When
+		n, err := rp.Count()
+		result, errByBrand := rp.CountByBrand()
+		// Then
+		assert.Nil(t, err)
+		assert.Nil(t, errByBrand)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("No vehicles", func(t *testing.T) {
+		// Given
+		rp := factory(t, nil)
+
+		// When
+		n, err := rp.Count()
+		result, errByBrand := rp.CountByBrand()
+		// Then
+		assert.Nil(t, err)
+		assert.Nil(t, errByBrand)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, map[string]int{}, result)
+	})
+}
+
+// runFindById is a function that runs the scenarios of FindById
+func runFindById(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id:                1,
+		VehicleAttributes: internal.VehicleAttributes{Brand: "A"},
+	}}
+	rp := factory(t, db)
+
+	t.Run("Find an existing vehicle", func(t *testing.T) {
+		// When
+		result, err := rp.FindById(1)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, db[1], result)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// When
+		_, err := rp.FindById(2)
+		// Then
+		assert.ErrorIs(t, err, internal.ErrRepositoryVehicleNotFound)
+		assert.ErrorIs(t, err, internal.ErrNotFound)
+	})
+}
+
+// runFindByRegistration is a function that runs the scenarios of FindByRegistration
+func runFindByRegistration(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id:                1,
+		VehicleAttributes: internal.VehicleAttributes{Registration: "AB-123"},
+	}, 2: {
+		Id:                2,
+		VehicleAttributes: internal.VehicleAttributes{Registration: "CD 456"},
+	}, 3: {
+		Id:                3,
+		VehicleAttributes: internal.VehicleAttributes{Registration: "cd456"},
+	}}
+
+	t.Run("Find ignoring case and whitespace", func(t *testing.T) {
+		// Given
+		rp := factory(t, db)
+
+		// When
+		result, err := rp.FindByRegistration(" ab-123 ")
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, db[1], result)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		rp := factory(t, db)
+
+		// When
+		_, err := rp.FindByRegistration("AB-124")
+		// Then
+		assert.ErrorIs(t, err, internal.ErrRepositoryVehicleNotFound)
+	})
+
+	t.Run("Registration shared by several vehicles", func(t *testing.T) {
+		// Given
+		rp := factory(t, db)
+
+		// When
+		_, err := rp.FindByRegistration("CD456")
+		// Then
+		assert.ErrorIs(t, err, internal.ErrRepositoryRegistrationAmbiguous)
+		assert.ErrorIs(t, err, internal.ErrConflict)
+		assert.EqualError(t, err, "repository: registration matches several vehicles: ids [2 3]")
+	})
+}
+
+// runFindByColorAndYear is a function that runs the scenarios of FindByColorAndYear
+func runFindByColorAndYear(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Color:           "A",
+			FabricationYear: 2008,
+		},
+	}, 2: {
+		Id: 2,
+		VehicleAttributes: internal.VehicleAttributes{
+			Color:           "A",
+			FabricationYear: 2007,
+		},
+	}, 3: {
+		Id: 3,
+		VehicleAttributes: internal.VehicleAttributes{
+			Color:           "B",
+			FabricationYear: 2008,
+		},
+	}}
+	rp := factory(t, db)
+
+	t.Run("Find by A color and 2008 as year", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Color:           "A",
+				FabricationYear: 2008,
+			},
+		}}
+		// When
+		result, err := rp.FindByColorAndYear("A", 2008)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Find by B color and 2008 as year", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{3: {
+			Id: 3,
+			VehicleAttributes: internal.VehicleAttributes{
+				Color:           "B",
+				FabricationYear: 2008,
+			},
+		}}
+		// When
+		result, err := rp.FindByColorAndYear("B", 2008)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("No vehicles found", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{}
+		// When
+		result, err := rp.FindByColorAndYear("C", 2008)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// runFindByBrandAndYearRange is a function that runs the scenarios of FindByBrandAndYearRange
+func runFindByBrandAndYearRange(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           "A",
+			FabricationYear: 2008,
+		},
+	}, 2: {
+		Id: 2,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           "A",
+			FabricationYear: 2005,
+		},
+	}, 3: {
+		Id: 3,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           "B",
+			FabricationYear: 2010,
+		},
+	}}
+	rp := factory(t, db)
+
+	t.Run("Find by brand A and with the year between 2000 and 2010", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           "A",
+				FabricationYear: 2008,
+			},
+		}, 2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           "A",
+				FabricationYear: 2005,
+			},
+		}}
+		// When
+		result, err := rp.FindByBrandAndYearRange("A", 2000, 2010)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Find by brand A and with the year between 2000 and 2006", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           "A",
+				FabricationYear: 2005,
+			},
+		}}
+		// When
+		result, err := rp.FindByBrandAndYearRange("A", 2000, 2006)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Find by brand B and with the year between 2000 and 2010", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{3: {
+			Id: 3,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           "B",
+				FabricationYear: 2010,
+			},
+		}}
+		// When
+		result, err := rp.FindByBrandAndYearRange("B", 2000, 2010)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Brand not found", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{}
+		// When
+		result, err := rp.FindByBrandAndYearRange("C", 2000, 2010)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Year range not found", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{}
+		// When
+		result, err := rp.FindByBrandAndYearRange("A", 2000, 2002)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// runFindByBrand is a function that runs the scenarios of FindByBrand
+func runFindByBrand(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand: "A",
+		},
+	}, 2: {
+		Id: 2,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand: "A",
+		},
+	}, 3: {
+		Id: 3,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand: "B",
+		},
+	}}
+	rp := factory(t, db)
+
+	t.Run("Find by brand A", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+			},
+		}, 2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+			},
+		}}
+		// When
+		result, err := rp.FindByBrand("A")
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Find by brand B", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{3: {
+			Id: 3,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "B",
+			},
+		}}
+		// When
+		result, err := rp.FindByBrand("B")
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Brand not found", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{}
+		// When
+		result, err := rp.FindByBrand("C")
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// runFindByWeightRange is a function that runs the scenarios of FindByWeightRange
+func runFindByWeightRange(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Weight: 1,
+		},
+	}, 2: {
+		Id: 2,
+		VehicleAttributes: internal.VehicleAttributes{
+			Weight: 10,
+		},
+	}, 3: {
+		Id: 3,
+		VehicleAttributes: internal.VehicleAttributes{
+			Weight: 4,
+		},
+	}}
+	rp := factory(t, db)
+
+	t.Run("Find by 0 to 10 weight range", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 1,
+			},
+		}, 2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 10,
+			},
+		}, 3: {
+			Id: 3,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 4,
+			},
+		}}
+		// When
+		result, err := rp.FindByWeightRange(0, 10)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Find by 5 to 10 weight range", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Weight: 10,
+			},
+		}}
+		// When
+		result, err := rp.FindByWeightRange(5, 10)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Vehicles not found", func(t *testing.T) {
+		// Given
+		expectedResult := map[int]internal.Vehicle{}
+		// When
+		result, err := rp.FindByWeightRange(11, 30)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+}
+
+// runFindByFilter is a function that runs the scenarios of FindByFilter
+func runFindByFilter(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:    "A",
+			FuelType: "diesel",
+			Dimensions: internal.Dimensions{
+				Length: 2,
+			},
+		},
+	}, 2: {
+		Id: 2,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:    "A",
+			FuelType: "gas",
+			Dimensions: internal.Dimensions{
+				Length: 4,
+			},
+		},
+	}, 3: {
+		Id: 3,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:    "B",
+			FuelType: "biodiesel",
+			Dimensions: internal.Dimensions{
+				Length: 3,
+			},
+		},
+	}}
+	rp := factory(t, db)
+
+	t.Run("Find by fuel type in diesel and biodiesel and length up to 3", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldFuelType, Operator: internal.FilterOperatorIn, Strings: []string{"diesel", "biodiesel"}},
+			{Field: internal.VehicleFieldLength, Operator: internal.FilterOperatorLte, Numbers: []float64{3}},
+		}}
+		expectedResult := map[int]internal.Vehicle{1: db[1], 3: db[3]}
+		// When
+		result, err := rp.FindByFilter(filter)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Empty filter", func(t *testing.T) {
+		// Given
+		expectedResult := db
+		// When
+		result, err := rp.FindByFilter(internal.VehicleFilter{})
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("No vehicles found", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{"C"}},
+		}}
+		expectedResult := map[int]internal.Vehicle{}
+		// When
+		result, err := rp.FindByFilter(filter)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Same vehicles as a scan", func(t *testing.T) {
+		// Given
+		db := randomVehicles(1000)
+		rp := factory(t, db)
+		filters := []internal.VehicleFilter{
+			{Conditions: []internal.VehicleCondition{
+				{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorIn, Strings: []string{"brand-1", "brand-2"}},
+			}},
+			{Conditions: []internal.VehicleCondition{
+				{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorGt, Numbers: []float64{1990}},
+				{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorLte, Numbers: []float64{1995}},
+				{Field: internal.VehicleFieldColor, Operator: internal.FilterOperatorEq, Strings: []string{"color-3"}},
+			}},
+			{Conditions: []internal.VehicleCondition{
+				{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorGte, Numbers: []float64{100}},
+				{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorLt, Numbers: []float64{200}},
+			}},
+		}
+
+		for _, filter := range filters {
+			// When
+			result, err := rp.FindByFilter(filter)
+			// Then
+			assert.Nil(t, err)
+			assert.Equal(t, scan(db, filter), result)
+		}
+	})
+}
+
+// runEmptyDb is a function that runs the scenarios of a repository without vehicles
+// - every finder succeeds with an empty, not nil, map, and the finders of one vehicle report it as not found
+func runEmptyDb(t *testing.T, factory Factory) {
+	dbs := map[string]map[int]internal.Vehicle{
+		"Nil db":   nil,
+		"Empty db": {},
+	}
+
+	for name, db := range dbs {
+		t.Run(name, func(t *testing.T) {
+			// Given
+			rp := factory(t, db)
+
+			// When
+			all, errAll := rp.FindAll()
+			byColorAndYear, errByColorAndYear := rp.FindByColorAndYear("A", 2000)
+			byBrandAndYearRange, errByBrandAndYearRange := rp.FindByBrandAndYearRange("A", 2000, 2010)
+			byBrand, errByBrand := rp.FindByBrand("A")
+			byWeightRange, errByWeightRange := rp.FindByWeightRange(0, 100)
+			byFilter, errByFilter := rp.FindByFilter(internal.VehicleFilter{})
+			_, errById := rp.FindById(1)
+			_, errByRegistration := rp.FindByRegistration("AB-123")
+			// Then
+			for _, err := range []error{errAll, errByColorAndYear, errByBrandAndYearRange, errByBrand, errByWeightRange, errByFilter} {
+				assert.Nil(t, err)
+			}
+			for _, result := range []map[int]internal.Vehicle{all, byColorAndYear, byBrandAndYearRange, byBrand, byWeightRange, byFilter} {
+				assert.NotNil(t, result)
+				assert.Empty(t, result)
+			}
+			assert.ErrorIs(t, errById, internal.ErrRepositoryVehicleNotFound)
+			assert.ErrorIs(t, errByRegistration, internal.ErrRepositoryVehicleNotFound)
+		})
+	}
+}
+
+// runInclusiveBounds is a function that runs the scenarios of the bounds of the ranges
+// - the ranges of the finders include both bounds, the filter includes them only with gte and lte
+func runInclusiveBounds(t *testing.T, factory Factory) {
+	// Given
+	db := map[int]internal.Vehicle{1: {
+		Id:                1,
+		VehicleAttributes: internal.VehicleAttributes{Brand: "A", FabricationYear: 2000, Weight: 10},
+	}, 2: {
+		Id:                2,
+		VehicleAttributes: internal.VehicleAttributes{Brand: "A", FabricationYear: 2005, Weight: 15},
+	}, 3: {
+		Id:                3,
+		VehicleAttributes: internal.VehicleAttributes{Brand: "A", FabricationYear: 2010, Weight: 20},
+	}}
+	rp := factory(t, db)
+
+	t.Run("Year range includes the start and end years", func(t *testing.T) {
+		// When
+		result, err := rp.FindByBrandAndYearRange("A", 2000, 2010)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ids(result))
+	})
+
+	t.Run("Year range of a single year", func(t *testing.T) {
+		// When
+		result, err := rp.FindByBrandAndYearRange("A", 2005, 2005)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2}, ids(result))
+	})
+
+	t.Run("Year range with the start after the end", func(t *testing.T) {
+		// When
+		result, err := rp.FindByBrandAndYearRange("A", 2010, 2000)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, []int{}, ids(result))
+	})
+
+	t.Run("Weight range includes the bounds", func(t *testing.T) {
+		// When
+		result, err := rp.FindByWeightRange(10, 20)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ids(result))
+	})
+
+	t.Run("Weight range of a single weight", func(t *testing.T) {
+		// When
+		result, err := rp.FindByWeightRange(15, 15)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2}, ids(result))
+	})
+
+	t.Run("Filter includes the bounds with gte and lte only", func(t *testing.T) {
+		// Given
+		inclusive := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorGte, Numbers: []float64{10}},
+			{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorLte, Numbers: []float64{20}},
+		}}
+		exclusive := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorGt, Numbers: []float64{10}},
+			{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorLt, Numbers: []float64{20}},
+		}}
+		// When
+		resultInclusive, errInclusive := rp.FindByFilter(inclusive)
+		resultExclusive, errExclusive := rp.FindByFilter(exclusive)
+		// Then
+		assert.Nil(t, errInclusive)
+		assert.Equal(t, []int{1, 2, 3}, ids(resultInclusive))
+		assert.Nil(t, errExclusive)
+		assert.Equal(t, []int{2}, ids(resultExclusive))
+	})
+}
+
+// runResults is a function that runs the scenarios of the ownership of the maps
+// - the maps returned belong to the caller, and the repository does not keep the map it was created with
+func runResults(t *testing.T, factory Factory) {
+	t.Run("Changes of a result do not affect the repository", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "A"}}}
+		rp := factory(t, db)
+		all, _ := rp.FindAll()
+		byBrand, _ := rp.FindByBrand("A")
+
+		// When
+		delete(all, 1)
+		byBrand[2] = internal.Vehicle{Id: 2}
+		result, err := rp.FindAll()
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, db, result)
+	})
+
+	t.Run("Changes of the db do not affect the repository", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {Id: 1}}
+		rp := factory(t, db)
+
+		// When
+		db[2] = internal.Vehicle{Id: 2}
+		result, err := rp.FindAll()
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, map[int]internal.Vehicle{1: {Id: 1}}, result)
+	})
+}
+
+// ids returns the sorted ids of the vehicles
+func ids(v map[int]internal.Vehicle) (s []int) {
+	s = make([]int, 0, len(v))
+	for id := range v {
+		s = append(s, id)
+	}
+	sort.Ints(s)
+	return
+}
+
+// scan returns the vehicles that match the filter checking every vehicle of the db
+func scan(db map[int]internal.Vehicle, filter internal.VehicleFilter) (v map[int]internal.Vehicle) {
+	v = make(map[int]internal.Vehicle)
+	for key, value := range db {
+		if filter.Match(value) {
+			v[key] = value
+		}
+	}
+	return
+}
+
+// randomVehicles returns a db of n vehicles with 50 brands, 20 colors, 50 years and weights from 0 to 1000
+func randomVehicles(n int) (db map[int]internal.Vehicle) {
+	rnd := rand.New(rand.NewSource(1))
+	db = make(map[int]internal.Vehicle, n)
+	for id := 1; id <= n; id++ {
+		db[id] = internal.Vehicle{
+			Id: id,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           fmt.Sprintf("brand-%d", rnd.Intn(50)),
+				Color:           fmt.Sprintf("color-%d", rnd.Intn(20)),
+				FabricationYear: 1970 + rnd.Intn(50),
+				Weight:          rnd.Float64() * 1000,
+			},
+		}
+	}
+	return
+}
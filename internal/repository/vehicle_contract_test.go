@@ -0,0 +1,411 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"testing"
+)
+
+// repositoryFactory is a function that returns a new repository with the vehicles of db
+type repositoryFactory func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryVehicle
+
+// testRepositoryVehicle is a function that runs the scenarios that every implementation of internal.RepositoryVehicle must pass
+// - reads: the contract of repositorytest, writes: the scenarios below
+func testRepositoryVehicle(t *testing.T, newRepository repositoryFactory) {
+	repositorytest.RunReadVehicleContract(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle {
+		return newRepository(t, db)
+	})
+	t.Run("Save", func(t *testing.T) { testRepositoryVehicleSave(t, newRepository) })
+	t.Run("Update", func(t *testing.T) { testRepositoryVehicleUpdate(t, newRepository) })
+	t.Run("Patch", func(t *testing.T) { testRepositoryVehiclePatch(t, newRepository) })
+	t.Run("Delete", func(t *testing.T) { testRepositoryVehicleDelete(t, newRepository) })
+	t.Run("Replace", func(t *testing.T) { testRepositoryVehicleReplace(t, newRepository) })
+	t.Run("Indexes", func(t *testing.T) { testRepositoryVehicleIndexes(t, newRepository) })
+	t.Run("Registrations", func(t *testing.T) { testRepositoryVehicleRegistrations(t, newRepository) })
+}
+
+func testRepositoryVehicleSave(t *testing.T, newRepository repositoryFactory) {
+	t.Run("Save a vehicle with the next id", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+			},
+		}, 5: {
+			Id: 5,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "B",
+			},
+		}}
+		rp := newRepository(t, db)
+
+		vehicle := internal.Vehicle{
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "C",
+			},
+		}
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+			},
+		}, 5: {
+			Id: 5,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "B",
+			},
+		}, 6: {
+			Id: 6,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "C",
+			},
+		}}
+		// When
+		err := rp.Save(&vehicle)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, 6, vehicle.Id)
+		result, _ := rp.FindAll()
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Save a vehicle in an empty db", func(t *testing.T) {
+		// Given
+		rp := newRepository(t, nil)
+
+		vehicle := internal.Vehicle{}
+		// When
+		err := rp.Save(&vehicle)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, 1, vehicle.Id)
+	})
+}
+
+func testRepositoryVehicleUpdate(t *testing.T, newRepository repositoryFactory) {
+	t.Run("Update a vehicle", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+				Color: "B",
+			},
+		}}
+		rp := newRepository(t, db)
+
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "C",
+			},
+		}}
+		// When
+		err := rp.Update(internal.Vehicle{
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "C",
+			},
+		})
+		// Then
+		assert.Nil(t, err)
+		result, _ := rp.FindAll()
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		rp := newRepository(t, nil)
+
+		expectedError := internal.ErrRepositoryVehicleNotFound
+		// When
+		err := rp.Update(internal.Vehicle{Id: 1})
+		// Then
+		assert.ErrorIs(t, err, expectedError)
+	})
+}
+
+func testRepositoryVehiclePatch(t *testing.T, newRepository repositoryFactory) {
+	t.Run("Patch the color and width of a vehicle", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+				Color: "B",
+				Dimensions: internal.Dimensions{
+					Height: 1,
+					Width:  1,
+				},
+			},
+		}}
+		rp := newRepository(t, db)
+
+		color := "C"
+		width := 2.0
+		expectedResult := internal.Vehicle{
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "A",
+				Color: "C",
+				Dimensions: internal.Dimensions{
+					Height: 1,
+					Width:  2,
+				},
+			},
+		}
+		// When
+		result, err := rp.Patch(1, internal.VehiclePatch{
+			Color:           &color,
+			DimensionsPatch: internal.DimensionsPatch{Width: &width},
+		})
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+		all, _ := rp.FindAll()
+		assert.Equal(t, map[int]internal.Vehicle{1: expectedResult}, all)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		rp := newRepository(t, nil)
+
+		expectedError := internal.ErrRepositoryVehicleNotFound
+		// When
+		_, err := rp.Patch(1, internal.VehiclePatch{})
+		// Then
+		assert.ErrorIs(t, err, expectedError)
+	})
+}
+
+func testRepositoryVehicleDelete(t *testing.T, newRepository repositoryFactory) {
+	t.Run("Delete a vehicle", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+		}, 2: {
+			Id: 2,
+		}}
+		rp := newRepository(t, db)
+
+		expectedResult := map[int]internal.Vehicle{2: {
+			Id: 2,
+		}}
+		// When
+		err := rp.Delete(1)
+		// Then
+		assert.Nil(t, err)
+		result, _ := rp.FindAll()
+		assert.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		rp := newRepository(t, nil)
+
+		expectedError := internal.ErrRepositoryVehicleNotFound
+		// When
+		err := rp.Delete(1)
+		// Then
+		assert.ErrorIs(t, err, expectedError)
+	})
+}
+
+func testRepositoryVehicleReplace(t *testing.T, newRepository repositoryFactory) {
+	t.Run("Replace all the vehicles", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+		}, 2: {
+			Id: 2,
+		}}
+		rp := newRepository(t, db)
+
+		expectedResult := map[int]internal.Vehicle{3: {
+			Id: 3,
+		}}
+		// When
+		err := rp.Replace(map[int]internal.Vehicle{3: {Id: 3}})
+		// Then
+		assert.Nil(t, err)
+		result, _ := rp.FindAll()
+		assert.Equal(t, expectedResult, result)
+		v := internal.Vehicle{}
+		_ = rp.Save(&v)
+		assert.Equal(t, 4, v.Id)
+	})
+
+	t.Run("Ids of replaced vehicles are not reused", func(t *testing.T) {
+		// Given
+		rp := newRepository(t, map[int]internal.Vehicle{1: {Id: 1}, 5: {Id: 5}})
+		require.NoError(t, rp.Delete(5))
+
+		// When
+		err := rp.Replace(map[int]internal.Vehicle{1: {Id: 1}})
+		v := internal.Vehicle{}
+		errSave := rp.Save(&v)
+		// Then
+		assert.Nil(t, err)
+		assert.Nil(t, errSave)
+		assert.Equal(t, 6, v.Id)
+	})
+}
+
+func testRepositoryVehicleIndexes(t *testing.T, newRepository repositoryFactory) {
+	t.Run("Indexes are kept consistent on writes", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           "A",
+				Color:           "X",
+				FabricationYear: 2000,
+				Weight:          10,
+			},
+		}, 2: {
+			Id: 2,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           "A",
+				Color:           "Y",
+				FabricationYear: 2005,
+				Weight:          20,
+			},
+		}}
+		rp := newRepository(t, db)
+
+		// When
+		brand, weight := "B", 30.0
+		_, errPatch := rp.Patch(1, internal.VehiclePatch{Brand: &brand, Weight: &weight})
+		errUpdate := rp.Update(internal.Vehicle{Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "A", Color: "Z", FabricationYear: 2010, Weight: 5}})
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "A", Color: "X", FabricationYear: 2000, Weight: 10}}
+		errSave := rp.Save(&v)
+		errDelete := rp.Delete(v.Id)
+		// Then
+		assert.Nil(t, errPatch)
+		assert.Nil(t, errUpdate)
+		assert.Nil(t, errSave)
+		assert.Nil(t, errDelete)
+
+		byBrandA, _ := rp.FindByBrand("A")
+		assert.Equal(t, []int{2}, ids(byBrandA))
+		byBrandB, _ := rp.FindByBrand("B")
+		assert.Equal(t, []int{1}, ids(byBrandB))
+		byColorAndYear, _ := rp.FindByColorAndYear("X", 2000)
+		assert.Equal(t, []int{1}, ids(byColorAndYear))
+		byYearRange, _ := rp.FindByBrandAndYearRange("A", 2006, 2010)
+		assert.Equal(t, []int{2}, ids(byYearRange))
+		byWeightRange, _ := rp.FindByWeightRange(0, 10)
+		assert.Equal(t, []int{2}, ids(byWeightRange))
+		byWeightExclusive, _ := rp.FindByFilter(internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorGt, Numbers: []float64{5}},
+			{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorLt, Numbers: []float64{30}},
+		}})
+		assert.Equal(t, []int{}, ids(byWeightExclusive))
+	})
+
+	t.Run("Registration index follows the writes", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id:                1,
+			VehicleAttributes: internal.VehicleAttributes{Registration: "AB-123"},
+		}, 2: {
+			Id:                2,
+			VehicleAttributes: internal.VehicleAttributes{Registration: "CD 456"},
+		}, 3: {
+			Id:                3,
+			VehicleAttributes: internal.VehicleAttributes{Registration: "cd456"},
+		}}
+		rp := newRepository(t, db)
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Registration: "EF-789"}}
+		assert.Nil(t, rp.Save(&v))
+		assert.Nil(t, rp.Delete(3))
+		registration := "GH-012"
+		_, err := rp.Patch(1, internal.VehiclePatch{Registration: &registration})
+		assert.Nil(t, err)
+
+		// When
+		saved, errSaved := rp.FindByRegistration("ef-789")
+		patched, errPatched := rp.FindByRegistration("gh-012")
+		_, errOld := rp.FindByRegistration("AB-123")
+		remaining, errRemaining := rp.FindByRegistration("CD456")
+		// Then
+		assert.Nil(t, errSaved)
+		assert.Equal(t, v.Id, saved.Id)
+		assert.Nil(t, errPatched)
+		assert.Equal(t, 1, patched.Id)
+		assert.ErrorIs(t, errOld, internal.ErrRepositoryVehicleNotFound)
+		assert.Nil(t, errRemaining)
+		assert.Equal(t, 2, remaining.Id)
+	})
+
+	t.Run("Counts follow the writes", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "A"}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "B"}},
+		}
+		rp := newRepository(t, db)
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "C"}}
+		assert.Nil(t, rp.Save(&v))
+		assert.Nil(t, rp.Delete(2))
+		brand := "C"
+		_, err := rp.Patch(1, internal.VehiclePatch{Brand: &brand})
+		assert.Nil(t, err)
+
+		// When
+		n, errCount := rp.Count()
+		result, errByBrand := rp.CountByBrand()
+		// Then
+		assert.Nil(t, errCount)
+		assert.Nil(t, errByBrand)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, map[string]int{"C": 2}, result)
+	})
+}
+
+// ids returns the sorted ids of the vehicles
+func testRepositoryVehicleRegistrations(t *testing.T, newRepository repositoryFactory) {
+	t.Run("Registrations used by another vehicle are rejected", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {
+			Id:                1,
+			VehicleAttributes: internal.VehicleAttributes{Registration: "AB-123", Color: "red"},
+		}, 2: {
+			Id:                2,
+			VehicleAttributes: internal.VehicleAttributes{Registration: "CD-456"},
+		}}
+		rp := newRepository(t, db)
+		taken := " ab-1 23"
+
+		// When
+		errSave := rp.Save(&internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Registration: "ab-123"}})
+		errUpdate := rp.Update(internal.Vehicle{Id: 2, VehicleAttributes: internal.VehicleAttributes{Registration: "AB-123"}})
+		_, errPatch := rp.Patch(2, internal.VehiclePatch{Registration: &taken})
+		errSame := rp.Update(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "ab-123", Color: "blue"}})
+		// Then
+		assert.ErrorIs(t, errSave, internal.ErrRepositoryRegistrationTaken)
+		assert.ErrorIs(t, errSave, internal.ErrConflict)
+		assert.ErrorIs(t, errUpdate, internal.ErrRepositoryRegistrationTaken)
+		assert.ErrorIs(t, errPatch, internal.ErrRepositoryRegistrationTaken)
+		assert.Nil(t, errSame)
+		all, err := rp.FindAll()
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, ids(all))
+		assert.Equal(t, "CD-456", all[2].Registration)
+		n, _ := rp.Count()
+		assert.Equal(t, 2, n)
+	})
+}
+
+func ids(v map[int]internal.Vehicle) (s []int) {
+	s = make([]int, 0, len(v))
+	for id := range v {
+		s = append(s, id)
+	}
+	sort.Ints(s)
+	return
+}
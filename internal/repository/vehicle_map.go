@@ -2,22 +2,261 @@ package repository
 
 import (
 	"app/internal"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
 )
 
+//go:generate go run app/cmd/mockgen -source .. -interface RepositoryVehicle -type VehicleMapMock -package repositorytest -output repositorytest/vehicle_map_mock.go
+
 // NewRepositoryReadVehicleMap is a function that returns a new instance of RepositoryReadVehicleMap
 func NewRepositoryReadVehicleMap(db map[int]internal.Vehicle) *RepositoryReadVehicleMap {
-	// default db
-	defaultDb := make(map[int]internal.Vehicle)
-	if db != nil {
-		defaultDb = db
+	rp := &RepositoryReadVehicleMap{}
+	rp.snapshot.Store(newVehicleMapSnapshot(db))
+	return rp
+}
+
+// vehicleMapSnapshot is a struct that represents an immutable state of the repository
+type vehicleMapSnapshot struct {
+	// db is a map of vehicles
+	db map[int]internal.Vehicle
+	// lastId is the highest id in the db, used to generate the id of new vehicles
+	lastId int
+
+	// byBrand is the index of the vehicles by brand
+	byBrand bucketIndex[string]
+	// byColor is the index of the vehicles by color
+	byColor bucketIndex[string]
+	// byYear is the index of the vehicles by fabrication year
+	byYear bucketIndex[int]
+	// byRegistration is the index of the vehicles by registration, normalized by internal.NormalizeRegistration
+	byRegistration bucketIndex[string]
+	// byWeight is the index of the vehicles sorted by weight
+	byWeight sortedIndex
+}
+
+// newVehicleMapSnapshot is a function that returns a snapshot with a copy of db
+func newVehicleMapSnapshot(db map[int]internal.Vehicle) (s *vehicleMapSnapshot) {
+	s = &vehicleMapSnapshot{
+		db:             make(map[int]internal.Vehicle, len(db)),
+		byBrand:        make(bucketIndex[string]),
+		byColor:        make(bucketIndex[string]),
+		byYear:         make(bucketIndex[int]),
+		byRegistration: make(bucketIndex[string]),
+	}
+	for key, value := range db {
+		s.db[key] = value
+		if key > s.lastId {
+			s.lastId = key
+		}
+		// buckets are not shared yet, so they can be appended in place
+		s.byBrand[value.Brand] = append(s.byBrand[value.Brand], key)
+		s.byColor[value.Color] = append(s.byColor[value.Color], key)
+		s.byYear[value.FabricationYear] = append(s.byYear[value.FabricationYear], key)
+		registration := internal.NormalizeRegistration(value.Registration)
+		s.byRegistration[registration] = append(s.byRegistration[registration], key)
+	}
+	s.byWeight = newSortedIndex(s.db, internal.VehicleFieldWeight)
+	return
+}
+
+// clone is a method that returns a copy of the snapshot that can be modified
+func (s *vehicleMapSnapshot) clone() (c *vehicleMapSnapshot) {
+	c = &vehicleMapSnapshot{
+		db:             make(map[int]internal.Vehicle, len(s.db)),
+		lastId:         s.lastId,
+		byBrand:        s.byBrand.clone(),
+		byColor:        s.byColor.clone(),
+		byYear:         s.byYear.clone(),
+		byRegistration: s.byRegistration.clone(),
+		byWeight:       s.byWeight,
+	}
+	for key, value := range s.db {
+		c.db[key] = value
+	}
+	return
+}
+
+// put is a method that saves the vehicle, replacing the vehicle with the same id, and keeps the indexes updated
+func (s *vehicleMapSnapshot) put(v internal.Vehicle) {
+	s.remove(v.Id)
+
+	s.db[v.Id] = v
+	if v.Id > s.lastId {
+		s.lastId = v.Id
+	}
+	s.byBrand.add(v.Brand, v.Id)
+	s.byColor.add(v.Color, v.Id)
+	s.byYear.add(v.FabricationYear, v.Id)
+	s.byRegistration.add(internal.NormalizeRegistration(v.Registration), v.Id)
+	s.byWeight = s.byWeight.add(v.Weight, v.Id)
+}
+
+// remove is a method that deletes the vehicle and keeps the indexes updated
+func (s *vehicleMapSnapshot) remove(id int) {
+	v, ok := s.db[id]
+	if !ok {
+		return
+	}
+
+	delete(s.db, id)
+	s.byBrand.remove(v.Brand, id)
+	s.byColor.remove(v.Color, id)
+	s.byYear.remove(v.FabricationYear, id)
+	s.byRegistration.remove(internal.NormalizeRegistration(v.Registration), id)
+	s.byWeight = s.byWeight.remove(v.Weight, id)
+}
+
+// checkRegistration is a method that returns ErrRepositoryRegistrationTaken if another vehicle has the registration of v
+// - an empty registration is not checked
+func (s *vehicleMapSnapshot) checkRegistration(v internal.Vehicle) (err error) {
+	registration := internal.NormalizeRegistration(v.Registration)
+	if registration == "" {
+		return
+	}
+	for _, id := range s.byRegistration[registration] {
+		if id != v.Id {
+			err = fmt.Errorf("%w: id %d", internal.ErrRepositoryRegistrationTaken, id)
+			return
+		}
+	}
+	return
+}
+
+// numberRange is a struct that represents the range of values accepted by the conditions over a numeric field
+type numberRange struct {
+	// set is a flag that indicates that some condition restricts the range
+	set bool
+	// min is the lower bound
+	min float64
+	// minInclusive is a flag that indicates that min is in the range
+	minInclusive bool
+	// max is the upper bound
+	max float64
+	// maxInclusive is a flag that indicates that max is in the range
+	maxInclusive bool
+}
+
+// newNumberRange is a function that returns a range without bounds
+func newNumberRange() numberRange {
+	return numberRange{min: math.Inf(-1), minInclusive: true, max: math.Inf(1), maxInclusive: true}
+}
+
+// restrict is a method that narrows the range with a range condition
+func (r *numberRange) restrict(operator internal.FilterOperator, value float64) {
+	r.set = true
+	switch operator {
+	case internal.FilterOperatorGt:
+		if value >= r.min {
+			r.min, r.minInclusive = value, false
+		}
+	case internal.FilterOperatorGte:
+		if value > r.min {
+			r.min, r.minInclusive = value, true
+		}
+	case internal.FilterOperatorLt:
+		if value <= r.max {
+			r.max, r.maxInclusive = value, false
+		}
+	case internal.FilterOperatorLte:
+		if value < r.max {
+			r.max, r.maxInclusive = value, true
+		}
+	}
+}
+
+// contains is a method that returns if the value is in the range
+func (r numberRange) contains(value float64) bool {
+	if value < r.min || (value == r.min && !r.minInclusive) {
+		return false
+	}
+	if value > r.max || (value == r.max && !r.maxInclusive) {
+		return false
+	}
+	return true
+}
+
+// candidates is a method that returns the ids of the vehicles that may match the filter, using the most selective index
+// - ok is false when no index can be used and all the vehicles must be scanned
+func (s *vehicleMapSnapshot) candidates(filter internal.VehicleFilter) (ids []int, ok bool) {
+	// choose keeps the smallest list of candidates
+	choose := func(c []int) {
+		if !ok || len(c) < len(ids) {
+			ids, ok = c, true
+		}
+	}
+
+	years, weights := newNumberRange(), newNumberRange()
+	for _, c := range filter.Conditions {
+		switch c.Field {
+		case internal.VehicleFieldBrand:
+			choose(s.byBrand.lookup(c.Strings))
+		case internal.VehicleFieldColor:
+			choose(s.byColor.lookup(c.Strings))
+		case internal.VehicleFieldFabricationYear:
+			if c.Operator == internal.FilterOperatorEq || c.Operator == internal.FilterOperatorIn {
+				keys := make([]int, 0, len(c.Numbers))
+				for _, n := range c.Numbers {
+					keys = append(keys, int(n))
+				}
+				choose(s.byYear.lookup(keys))
+				continue
+			}
+			years.restrict(c.Operator, c.Numbers[0])
+		case internal.VehicleFieldWeight:
+			if c.Operator == internal.FilterOperatorEq || c.Operator == internal.FilterOperatorIn {
+				var c2 []int
+				for _, n := range c.Numbers {
+					c2 = append(c2, s.byWeight.between(n, true, n, true)...)
+				}
+				choose(c2)
+				continue
+			}
+			weights.restrict(c.Operator, c.Numbers[0])
+		}
+	}
+
+	if years.set {
+		var c []int
+		for year, bucket := range s.byYear {
+			if years.contains(float64(year)) {
+				c = append(c, bucket...)
+			}
+		}
+		choose(c)
+	}
+	if weights.set {
+		choose(s.byWeight.between(weights.min, weights.minInclusive, weights.max, weights.maxInclusive))
 	}
-	return &RepositoryReadVehicleMap{db: defaultDb}
+
+	return
 }
 
 // RepositoryReadVehicleMap is a struct that represents a vehicle repository
+// - concurrency: copy-on-write. Reads work over an immutable snapshot and never block,
+// writes are serialized, copy the current snapshot and swap it once modified
 type RepositoryReadVehicleMap struct {
-	// db is a map of vehicles
-	db map[int]internal.Vehicle
+	// snapshot is the current state of the repository
+	snapshot atomic.Pointer[vehicleMapSnapshot]
+	// mu is the mutex that serializes the writes
+	mu sync.Mutex
+}
+
+// write is a method that applies fn over a copy of the current snapshot and swaps it if fn succeeds
+func (r *RepositoryReadVehicleMap) write(fn func(sn *vehicleMapSnapshot) (err error)) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sn := r.snapshot.Load().clone()
+	err = fn(sn)
+	if err != nil {
+		return
+	}
+	r.snapshot.Store(sn)
+
+	return
 }
 
 // FindAll is a method that returns a map of all vehicles
@@ -25,48 +264,117 @@ func (r *RepositoryReadVehicleMap) FindAll() (v map[int]internal.Vehicle, err er
 	v = make(map[int]internal.Vehicle)
 
 	// copy db
-	for key, value := range r.db {
+	for key, value := range r.snapshot.Load().db {
 		v[key] = value
 	}
 
 	return
 }
 
-// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
-func (r *RepositoryReadVehicleMap) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
-	v = make(map[int]internal.Vehicle)
+// Count is a method that returns the number of vehicles, without reading them
+func (r *RepositoryReadVehicleMap) Count() (n int, err error) {
+	n = len(r.snapshot.Load().db)
+	return
+}
 
-	// filter db
-	for key, value := range r.db {
-		if value.Color == color && value.FabricationYear == fabricationYear {
-			v[key] = value
-		}
+// CountByBrand is a method that returns the number of vehicles of each brand, from the sizes of the buckets of the index
+func (r *RepositoryReadVehicleMap) CountByBrand() (n map[string]int, err error) {
+	sn := r.snapshot.Load()
+
+	n = make(map[string]int, len(sn.byBrand))
+	for brand, ids := range sn.byBrand {
+		n[brand] = len(ids)
+	}
+	return
+}
+
+// FindById is a method that returns the vehicle with the id
+func (r *RepositoryReadVehicleMap) FindById(id int) (v internal.Vehicle, err error) {
+	v, ok := r.snapshot.Load().db[id]
+	if !ok {
+		err = internal.ErrRepositoryVehicleNotFound
+		return
 	}
 
 	return
 }
 
-// FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
-func (r *RepositoryReadVehicleMap) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
-	v = make(map[int]internal.Vehicle)
+// FindByRegistration is a method that returns the vehicle with the registration, compared by internal.NormalizeRegistration
+func (r *RepositoryReadVehicleMap) FindByRegistration(registration string) (v internal.Vehicle, err error) {
+	sn := r.snapshot.Load()
 
-	// filter db
-	for key, value := range r.db {
-		if value.Brand == brand && value.FabricationYear >= startYear && value.FabricationYear <= endYear {
-			v[key] = value
-		}
+	ids := sn.byRegistration[internal.NormalizeRegistration(registration)]
+	switch len(ids) {
+	case 0:
+		err = internal.ErrRepositoryVehicleNotFound
+	case 1:
+		v = sn.db[ids[0]]
+	default:
+		// buckets are shared between snapshots, so they are sorted in a copy
+		ids = slices.Clone(ids)
+		slices.Sort(ids)
+		err = fmt.Errorf("%w: ids %v", internal.ErrRepositoryRegistrationAmbiguous, ids)
 	}
 
 	return
 }
 
+// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
+func (r *RepositoryReadVehicleMap) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+	v, err = r.FindByFilter(internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+		{Field: internal.VehicleFieldColor, Operator: internal.FilterOperatorEq, Strings: []string{color}},
+		{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorEq, Numbers: []float64{float64(fabricationYear)}},
+	}})
+	return
+}
+
+// FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
+func (r *RepositoryReadVehicleMap) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	v, err = r.FindByFilter(internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+		{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{brand}},
+		{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorGte, Numbers: []float64{float64(startYear)}},
+		{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorLte, Numbers: []float64{float64(endYear)}},
+	}})
+	return
+}
+
 // FindByBrand is a method that returns a map of vehicles that match the brand
 func (r *RepositoryReadVehicleMap) FindByBrand(brand string) (v map[int]internal.Vehicle, err error) {
+	v, err = r.FindByFilter(internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+		{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{brand}},
+	}})
+	return
+}
+
+// FindByWeightRange is a method that returns a map of vehicles that match the weight range
+func (r *RepositoryReadVehicleMap) FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error) {
+	v, err = r.FindByFilter(internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+		{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorGte, Numbers: []float64{fromWeight}},
+		{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorLte, Numbers: []float64{toWeight}},
+	}})
+	return
+}
+
+// FindByFilter is a method that returns a map of vehicles that match the filter
+// - the most selective condition over an indexed field (brand, color, fabrication year or weight) narrows the vehicles
+// to check, otherwise all the vehicles are scanned
+func (r *RepositoryReadVehicleMap) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
 	v = make(map[int]internal.Vehicle)
+	sn := r.snapshot.Load()
+
+	// filter candidates of the indexes
+	if ids, ok := sn.candidates(filter); ok {
+		for _, id := range ids {
+			if value := sn.db[id]; filter.Match(value) {
+				v[id] = value
+			}
+		}
+		return
+	}
 
 	// filter db
-	for key, value := range r.db {
-		if value.Brand == brand {
+	for key, value := range sn.db {
+		if filter.Match(value) {
 			v[key] = value
 		}
 	}
@@ -74,16 +382,90 @@ func (r *RepositoryReadVehicleMap) FindByBrand(brand string) (v map[int]internal
 	return
 }
 
-// FindByWeightRange is a method that returns a map of vehicles that match the weight range
-func (r *RepositoryReadVehicleMap) FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error) {
-	v = make(map[int]internal.Vehicle)
+// Save is a method that saves a new vehicle and sets its id
+func (r *RepositoryReadVehicleMap) Save(v *internal.Vehicle) (err error) {
+	err = r.write(func(sn *vehicleMapSnapshot) (err error) {
+		// set id
+		v.Id = sn.lastId + 1
 
-	// filter db
-	for key, value := range r.db {
-		if value.Weight >= fromWeight && value.Weight <= toWeight {
-			v[key] = value
+		// save vehicle
+		sn.put(*v)
+
+		err = sn.checkRegistration(*v)
+		return
+	})
+	return
+}
+
+// Update is a method that replaces all the attributes of an existing vehicle
+func (r *RepositoryReadVehicleMap) Update(v internal.Vehicle) (err error) {
+	err = r.write(func(sn *vehicleMapSnapshot) (err error) {
+		// check if vehicle exists
+		if _, ok := sn.db[v.Id]; !ok {
+			err = internal.ErrRepositoryVehicleNotFound
+			return
 		}
-	}
+
+		// update vehicle
+		sn.put(v)
+
+		err = sn.checkRegistration(v)
+		return
+	})
+	return
+}
+
+// Patch is a method that updates only the set attributes of an existing vehicle
+func (r *RepositoryReadVehicleMap) Patch(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	err = r.write(func(sn *vehicleMapSnapshot) (err error) {
+		// check if vehicle exists
+		vh, ok := sn.db[id]
+		if !ok {
+			err = internal.ErrRepositoryVehicleNotFound
+			return
+		}
+
+		// patch vehicle
+		patch.Apply(&vh.VehicleAttributes)
+		sn.put(vh)
+		err = sn.checkRegistration(vh)
+		if err != nil {
+			return
+		}
+		v = vh
+
+		return
+	})
+	return
+}
+
+// Delete is a method that deletes an existing vehicle
+func (r *RepositoryReadVehicleMap) Delete(id int) (err error) {
+	err = r.write(func(sn *vehicleMapSnapshot) (err error) {
+		// check if vehicle exists
+		if _, ok := sn.db[id]; !ok {
+			err = internal.ErrRepositoryVehicleNotFound
+			return
+		}
+
+		// delete vehicle
+		sn.remove(id)
+
+		return
+	})
+	return
+}
+
+// Replace is a method that replaces all the vehicles at once
+// - readers see either the previous or the new vehicles, never a mix of both
+// - the ids of the previous vehicles are not given again to new vehicles
+func (r *RepositoryReadVehicleMap) Replace(v map[int]internal.Vehicle) (err error) {
+	sn := newVehicleMapSnapshot(v)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sn.lastId = max(sn.lastId, r.snapshot.Load().lastId)
+	r.snapshot.Store(sn)
 
 	return
 }
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"app/internal"
+	"sort"
+)
+
+// bucketIndex is an index from a value to the ids of the vehicles with that value
+// - immutable buckets: add and remove never modify a bucket in place, so an index can share buckets with its clones
+type bucketIndex[K comparable] map[K][]int
+
+// clone is a method that returns a copy of the index that shares the buckets
+func (x bucketIndex[K]) clone() (c bucketIndex[K]) {
+	c = make(bucketIndex[K], len(x))
+	for key, ids := range x {
+		c[key] = ids
+	}
+	return
+}
+
+// add is a method that adds the id to the bucket of the key
+func (x bucketIndex[K]) add(key K, id int) {
+	ids := x[key]
+	bucket := make([]int, len(ids), len(ids)+1)
+	copy(bucket, ids)
+	x[key] = append(bucket, id)
+}
+
+// remove is a method that removes the id from the bucket of the key
+func (x bucketIndex[K]) remove(key K, id int) {
+	ids := x[key]
+	bucket := make([]int, 0, len(ids))
+	for _, value := range ids {
+		if value != id {
+			bucket = append(bucket, value)
+		}
+	}
+	if len(bucket) == 0 {
+		delete(x, key)
+		return
+	}
+	x[key] = bucket
+}
+
+// sortedIndex is an index of the ids of the vehicles sorted by a numeric field, ties sorted by id
+// - immutable: add and remove return a new index
+type sortedIndex struct {
+	// ids are the ids of the vehicles sorted
+	ids []int
+	// values are the values of the field of the vehicles, in the same order than ids
+	values []float64
+}
+
+// newSortedIndex is a function that returns the index of the field for the vehicles
+func newSortedIndex(db map[int]internal.Vehicle, field internal.VehicleField) (x sortedIndex) {
+	x.ids = make([]int, 0, len(db))
+	for id := range db {
+		x.ids = append(x.ids, id)
+	}
+	sort.Slice(x.ids, func(i, j int) bool {
+		vi, vj := db[x.ids[i]].Number(field), db[x.ids[j]].Number(field)
+		if vi != vj {
+			return vi < vj
+		}
+		return x.ids[i] < x.ids[j]
+	})
+	x.values = make([]float64, len(x.ids))
+	for i, id := range x.ids {
+		x.values[i] = db[id].Number(field)
+	}
+	return
+}
+
+// search is a method that returns the position of the value and id in the index
+func (x sortedIndex) search(value float64, id int) int {
+	return sort.Search(len(x.ids), func(i int) bool {
+		if x.values[i] != value {
+			return x.values[i] > value
+		}
+		return x.ids[i] >= id
+	})
+}
+
+// add is a method that returns a new index with the id
+func (x sortedIndex) add(value float64, id int) (n sortedIndex) {
+	i := x.search(value, id)
+	n.ids = make([]int, 0, len(x.ids)+1)
+	n.ids = append(append(append(n.ids, x.ids[:i]...), id), x.ids[i:]...)
+	n.values = make([]float64, 0, len(x.values)+1)
+	n.values = append(append(append(n.values, x.values[:i]...), value), x.values[i:]...)
+	return
+}
+
+// remove is a method that returns a new index without the id
+func (x sortedIndex) remove(value float64, id int) (n sortedIndex) {
+	i := x.search(value, id)
+	if i == len(x.ids) || x.ids[i] != id {
+		n = x
+		return
+	}
+	n.ids = make([]int, 0, len(x.ids)-1)
+	n.ids = append(append(n.ids, x.ids[:i]...), x.ids[i+1:]...)
+	n.values = make([]float64, 0, len(x.values)-1)
+	n.values = append(append(n.values, x.values[:i]...), x.values[i+1:]...)
+	return
+}
+
+// between is a method that returns the ids of the vehicles with a value in the range
+func (x sortedIndex) between(min float64, minInclusive bool, max float64, maxInclusive bool) []int {
+	from := sort.Search(len(x.values), func(i int) bool {
+		if minInclusive {
+			return x.values[i] >= min
+		}
+		return x.values[i] > min
+	})
+	to := sort.Search(len(x.values), func(i int) bool {
+		if maxInclusive {
+			return x.values[i] > max
+		}
+		return x.values[i] >= max
+	})
+	if from >= to {
+		return nil
+	}
+	return x.ids[from:to]
+}
+
+// lookup is a method that returns the ids of the vehicles in the buckets of the keys
+func (x bucketIndex[K]) lookup(keys []K) (ids []int) {
+	if len(keys) == 1 {
+		ids = x[keys[0]]
+		return
+	}
+	for _, key := range keys {
+		ids = append(ids, x[key]...)
+	}
+	return
+}
@@ -3,365 +3,160 @@ package repository_test
 import (
 	"app/internal"
 	"app/internal/repository"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sync"
 	"testing"
 )
 
-func TestRepositoryReadVehicleMap_FindByColorAndYear(t *testing.T) {
-	// Given
-	db := map[int]internal.Vehicle{1: {
-		Id: 1,
-		VehicleAttributes: internal.VehicleAttributes{
-			Color:           "A",
-			FabricationYear: 2008,
-		},
-	}, 2: {
-		Id: 2,
-		VehicleAttributes: internal.VehicleAttributes{
-			Color:           "A",
-			FabricationYear: 2007,
-		},
-	}, 3: {
-		Id: 3,
-		VehicleAttributes: internal.VehicleAttributes{
-			Color:           "B",
-			FabricationYear: 2008,
-		},
-	}}
-	rp := repository.NewRepositoryReadVehicleMap(db)
-
-	t.Run("Find by A color and 2008 as year", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{1: {
-			Id: 1,
-			VehicleAttributes: internal.VehicleAttributes{
-				Color:           "A",
-				FabricationYear: 2008,
-			},
-		}}
-		// When
-		result, err := rp.FindByColorAndYear("A", 2008)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Find by B color and 2008 as year", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{3: {
-			Id: 3,
-			VehicleAttributes: internal.VehicleAttributes{
-				Color:           "B",
-				FabricationYear: 2008,
-			},
-		}}
-		// When
-		result, err := rp.FindByColorAndYear("B", 2008)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("No vehicles found", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{}
-		// When
-		result, err := rp.FindByColorAndYear("C", 2008)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
+func TestRepositoryReadVehicleMap(t *testing.T) {
+	testRepositoryVehicle(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryVehicle {
+		return repository.NewRepositoryReadVehicleMap(db)
 	})
 }
 
-func TestRepositoryReadVehicleMap_FindByBrandAndYearRange(t *testing.T) {
-	// Given
-	db := map[int]internal.Vehicle{1: {
-		Id: 1,
-		VehicleAttributes: internal.VehicleAttributes{
-			Brand:           "A",
-			FabricationYear: 2008,
-		},
-	}, 2: {
-		Id: 2,
-		VehicleAttributes: internal.VehicleAttributes{
-			Brand:           "A",
-			FabricationYear: 2005,
-		},
-	}, 3: {
-		Id: 3,
-		VehicleAttributes: internal.VehicleAttributes{
-			Brand:           "B",
-			FabricationYear: 2010,
-		},
-	}}
-	rp := repository.NewRepositoryReadVehicleMap(db)
-
-	t.Run("Find by brand A and with the year between 2000 and 2010", func(t *testing.T) {
+func TestRepositoryReadVehicleMap_Concurrency(t *testing.T) {
+	t.Run("Parallel reads and writes", func(t *testing.T) {
 		// Given
-		expectedResult := map[int]internal.Vehicle{1: {
-			Id: 1,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           "A",
-				FabricationYear: 2008,
-			},
-		}, 2: {
-			Id: 2,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           "A",
-				FabricationYear: 2005,
-			},
-		}}
-		// When
-		result, err := rp.FindByBrandAndYearRange("A", 2000, 2010)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Find by brand A and with the year between 2000 and 2006", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{2: {
-			Id: 2,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           "A",
-				FabricationYear: 2005,
-			},
-		}}
-		// When
-		result, err := rp.FindByBrandAndYearRange("A", 2000, 2006)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Find by brand B and with the year between 2000 and 2010", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{3: {
-			Id: 3,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           "B",
-				FabricationYear: 2010,
-			},
-		}}
-		// When
-		result, err := rp.FindByBrandAndYearRange("B", 2000, 2010)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Brand not found", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{}
-		// When
-		result, err := rp.FindByBrandAndYearRange("C", 2000, 2010)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Year range not found", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{}
-		// When
-		result, err := rp.FindByBrandAndYearRange("A", 2000, 2002)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-}
-
-func TestRepositoryReadVehicleMap_FindByBrand(t *testing.T) {
-	// Given
-	db := map[int]internal.Vehicle{1: {
-		Id: 1,
-		VehicleAttributes: internal.VehicleAttributes{
-			Brand: "A",
-		},
-	}, 2: {
-		Id: 2,
-		VehicleAttributes: internal.VehicleAttributes{
-			Brand: "A",
-		},
-	}, 3: {
-		Id: 3,
-		VehicleAttributes: internal.VehicleAttributes{
-			Brand: "B",
-		},
-	}}
-	rp := repository.NewRepositoryReadVehicleMap(db)
-
-	t.Run("Find by brand A", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{1: {
+		db := map[int]internal.Vehicle{1: {
 			Id: 1,
 			VehicleAttributes: internal.VehicleAttributes{
-				Brand: "A",
-			},
-		}, 2: {
-			Id: 2,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand: "A",
-			},
-		}}
-		// When
-		result, err := rp.FindByBrand("A")
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Find by brand B", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{3: {
-			Id: 3,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand: "B",
+				Brand:  "A",
+				Weight: 1,
 			},
 		}}
-		// When
-		result, err := rp.FindByBrand("B")
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
+		rp := repository.NewRepositoryReadVehicleMap(db)
 
-	t.Run("Brand not found", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{}
 		// When
-		result, err := rp.FindByBrand("C")
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "A", Weight: 2}}
+					_ = rp.Save(&v)
+					brand := "B"
+					_, _ = rp.Patch(v.Id, internal.VehiclePatch{Brand: &brand})
+					_ = rp.Delete(v.Id)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, _ = rp.FindAll()
+					_, _ = rp.FindByBrand("A")
+					_, _ = rp.FindByWeightRange(0, 10)
+				}
+			}()
+		}
+		wg.Wait()
 		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-}
-
-func TestRepositoryReadVehicleMap_FindByWeightRange(t *testing.T) {
-	// Given
-	db := map[int]internal.Vehicle{1: {
-		Id: 1,
-		VehicleAttributes: internal.VehicleAttributes{
-			Weight: 1,
-		},
-	}, 2: {
-		Id: 2,
-		VehicleAttributes: internal.VehicleAttributes{
-			Weight: 10,
-		},
-	}, 3: {
-		Id: 3,
-		VehicleAttributes: internal.VehicleAttributes{
-			Weight: 4,
-		},
-	}}
-	rp := repository.NewRepositoryReadVehicleMap(db)
-
-	t.Run("Find by 0 to 10 weight range", func(t *testing.T) {
-		// Given
 		expectedResult := map[int]internal.Vehicle{1: {
 			Id: 1,
 			VehicleAttributes: internal.VehicleAttributes{
+				Brand:  "A",
 				Weight: 1,
 			},
-		}, 2: {
-			Id: 2,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 10,
-			},
-		}, 3: {
-			Id: 3,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 4,
-			},
 		}}
-		// When
-		result, err := rp.FindByWeightRange(0, 10)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Find by 5 to 10 weight range", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{2: {
-			Id: 2,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 10,
-			},
-		}}
-		// When
-		result, err := rp.FindByWeightRange(5, 10)
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
-	})
-
-	t.Run("Vehicles not found", func(t *testing.T) {
-		// Given
-		expectedResult := map[int]internal.Vehicle{}
-		// When
-		result, err := rp.FindByWeightRange(11, 30)
-		// Then
+		result, err := rp.FindAll()
 		assert.Nil(t, err)
 		assert.Equal(t, expectedResult, result)
 	})
-}
 
-func TestRepositoryReadVehicleMap_FindAll(t *testing.T) {
-	t.Run("Find all", func(t *testing.T) {
+	t.Run("Reads are not affected by the db used to create the repository", func(t *testing.T) {
 		// Given
 		db := map[int]internal.Vehicle{1: {
 			Id: 1,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 1,
-			},
-		}, 2: {
-			Id: 2,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 10,
-			},
-		}, 3: {
-			Id: 3,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 4,
-			},
 		}}
-
 		rp := repository.NewRepositoryReadVehicleMap(db)
+
 		expectedResult := map[int]internal.Vehicle{1: {
 			Id: 1,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 1,
-			},
-		}, 2: {
-			Id: 2,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 10,
-			},
-		}, 3: {
-			Id: 3,
-			VehicleAttributes: internal.VehicleAttributes{
-				Weight: 4,
-			},
 		}}
 		// When
+		db[2] = internal.Vehicle{Id: 2}
 		result, err := rp.FindAll()
 		// Then
 		assert.Nil(t, err)
 		assert.Equal(t, expectedResult, result)
 	})
+}
 
-	t.Run("No vehicles found", func(t *testing.T) {
-		// Given
-		db := map[int]internal.Vehicle{}
-		rp := repository.NewRepositoryReadVehicleMap(db)
+// scan returns the vehicles that match the filter checking every vehicle of the db
+func scan(db map[int]internal.Vehicle, filter internal.VehicleFilter) (v map[int]internal.Vehicle) {
+	v = make(map[int]internal.Vehicle)
+	for key, value := range db {
+		if filter.Match(value) {
+			v[key] = value
+		}
+	}
+	return
+}
 
-		expectedResult := map[int]internal.Vehicle{}
-		// When
-		result, err := rp.FindAll()
-		// Then
-		assert.Nil(t, err)
-		assert.Equal(t, expectedResult, result)
+// benchmarkDb returns a db of n vehicles with 50 brands, 20 colors, 50 years and weights from 0 to 1000
+func benchmarkDb(n int) (db map[int]internal.Vehicle) {
+	rnd := rand.New(rand.NewSource(1))
+	db = make(map[int]internal.Vehicle, n)
+	for id := 1; id <= n; id++ {
+		db[id] = internal.Vehicle{
+			Id: id,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           fmt.Sprintf("brand-%d", rnd.Intn(50)),
+				Color:           fmt.Sprintf("color-%d", rnd.Intn(20)),
+				FabricationYear: 1970 + rnd.Intn(50),
+				Weight:          rnd.Float64() * 1000,
+			},
+		}
+	}
+	return
+}
+
+func BenchmarkRepositoryReadVehicleMap(b *testing.B) {
+	db := benchmarkDb(200000)
+	rp := repository.NewRepositoryReadVehicleMap(db)
+
+	byBrand := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+		{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{"brand-7"}},
+	}}
+	byColorAndYear := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+		{Field: internal.VehicleFieldColor, Operator: internal.FilterOperatorEq, Strings: []string{"color-7"}},
+		{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorEq, Numbers: []float64{2000}},
+	}}
+	byWeightRange := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+		{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorGte, Numbers: []float64{100}},
+		{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorLte, Numbers: []float64{101}},
+	}}
+
+	b.Run("FindByBrand/scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			scan(db, byBrand)
+		}
+	})
+	b.Run("FindByBrand/index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rp.FindByBrand("brand-7")
+		}
+	})
+	b.Run("FindByColorAndYear/scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			scan(db, byColorAndYear)
+		}
+	})
+	b.Run("FindByColorAndYear/index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rp.FindByColorAndYear("color-7", 2000)
+		}
+	})
+	b.Run("FindByWeightRange/scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			scan(db, byWeightRange)
+		}
+	})
+	b.Run("FindByWeightRange/index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rp.FindByWeightRange(100, 101)
+		}
 	})
 }
@@ -0,0 +1,492 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	// driver: pure Go SQLite, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations are the changes of the schema of the database, in order
+// - the number of migrations applied is kept in PRAGMA user_version, so each one runs once
+// - migrations are never edited once released, a change of the schema is a new migration
+var sqliteMigrations = []string{
+	// 1: vehicles, with the columns named as the fields of internal.VehicleField
+	// - registration_key is the registration normalized by internal.NormalizeRegistration
+	// - indexes: one for each finder, brand also serves FindByBrand
+	`CREATE TABLE vehicles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		brand TEXT NOT NULL,
+		model TEXT NOT NULL,
+		registration TEXT NOT NULL,
+		registration_key TEXT NOT NULL,
+		color TEXT NOT NULL,
+		year INTEGER NOT NULL,
+		passengers INTEGER NOT NULL,
+		max_speed REAL NOT NULL,
+		fuel_type TEXT NOT NULL,
+		transmission TEXT NOT NULL,
+		weight REAL NOT NULL,
+		height REAL NOT NULL,
+		length REAL NOT NULL,
+		width REAL NOT NULL
+	);
+	CREATE INDEX vehicles_color_year ON vehicles (color, year);
+	CREATE INDEX vehicles_brand_year ON vehicles (brand, year);
+	CREATE INDEX vehicles_weight ON vehicles (weight);
+	CREATE INDEX vehicles_registration_key ON vehicles (registration_key);`,
+}
+
+// sqliteColumns are the columns of a vehicle, in the order they are read and written
+const sqliteColumns = "id, brand, model, registration, color, year, passengers, max_speed, fuel_type, transmission, weight, height, length, width"
+
+// NewRepositoryVehicleSQLite is a function that returns a new instance of RepositoryVehicleSQLite
+// - dsn is the path to the database file, created if it does not exist, or ":memory:"
+// - the pending migrations are applied before returning
+func NewRepositoryVehicleSQLite(dsn string) (rp *RepositoryVehicleSQLite, err error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+		return
+	}
+	// one connection: SQLite serializes the writes anyway, and each connection to ":memory:" is a different database
+	db.SetMaxOpenConns(1)
+
+	rp = &RepositoryVehicleSQLite{db: db}
+	err = rp.migrate()
+	if err != nil {
+		_ = db.Close()
+		rp = nil
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+		return
+	}
+
+	return
+}
+
+// RepositoryVehicleSQLite is a struct that implements the RepositoryVehicle interface over a SQLite database
+// - concurrency: the database is used through a single connection, every write runs in a transaction
+type RepositoryVehicleSQLite struct {
+	// db is the database of the vehicles
+	db *sql.DB
+}
+
+// migrate is a method that applies the migrations not yet applied to the database
+func (r *RepositoryVehicleSQLite) migrate() (err error) {
+	var version int
+	err = r.db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		err = r.tx(func(tx *sql.Tx) (err error) {
+			if _, err = tx.Exec(sqliteMigrations[i]); err != nil {
+				return
+			}
+			// pragmas can not take parameters
+			_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return
+		})
+		if err != nil {
+			err = fmt.Errorf("migration %d: %w", i+1, err)
+			return
+		}
+	}
+
+	return
+}
+
+// Close is a method that closes the database
+func (r *RepositoryVehicleSQLite) Close() (err error) {
+	err = r.db.Close()
+	return
+}
+
+// tx is a method that runs fn in a transaction, committed if fn succeeds and rolled back otherwise
+func (r *RepositoryVehicleSQLite) tx(fn func(tx *sql.Tx) (err error)) (err error) {
+	tx, err := r.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return
+	}
+
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return
+	}
+	err = tx.Commit()
+	return
+}
+
+// queryer is an interface that represents a database or a transaction that can be queried
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// query is a function that returns a map of the vehicles of the rows of the query
+func query(q queryer, where string, args ...any) (v map[int]internal.Vehicle, err error) {
+	rows, err := q.Query("SELECT "+sqliteColumns+" FROM vehicles "+where, args...)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+		return
+	}
+	defer rows.Close()
+
+	v = make(map[int]internal.Vehicle)
+	for rows.Next() {
+		var vh internal.Vehicle
+		err = rows.Scan(&vh.Id, &vh.Brand, &vh.Model, &vh.Registration, &vh.Color, &vh.FabricationYear, &vh.Capacity,
+			&vh.MaxSpeed, &vh.FuelType, &vh.Transmission, &vh.Weight, &vh.Height, &vh.Length, &vh.Width)
+		if err != nil {
+			v = nil
+			err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+			return
+		}
+		v[vh.Id] = vh
+	}
+	if err = rows.Err(); err != nil {
+		v = nil
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+	}
+
+	return
+}
+
+// insert is a function that inserts the vehicle with the id, or with a new id if it is nil, and returns the id
+func insert(tx *sql.Tx, vid any, v internal.Vehicle) (id int, err error) {
+	res, err := tx.Exec("INSERT INTO vehicles ("+sqliteColumns+", registration_key) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		vid, v.Brand, v.Model, v.Registration, v.Color, v.FabricationYear, v.Capacity,
+		v.MaxSpeed, v.FuelType, v.Transmission, v.Weight, v.Height, v.Length, v.Width,
+		internal.NormalizeRegistration(v.Registration))
+	if err != nil {
+		return
+	}
+
+	lastId, err := res.LastInsertId()
+	id = int(lastId)
+	return
+}
+
+// update is a function that replaces the attributes of an existing vehicle
+func update(tx *sql.Tx, v internal.Vehicle) (err error) {
+	res, err := tx.Exec("UPDATE vehicles SET brand = ?, model = ?, registration = ?, color = ?, year = ?, passengers = ?, "+
+		"max_speed = ?, fuel_type = ?, transmission = ?, weight = ?, height = ?, length = ?, width = ?, registration_key = ? WHERE id = ?",
+		v.Brand, v.Model, v.Registration, v.Color, v.FabricationYear, v.Capacity,
+		v.MaxSpeed, v.FuelType, v.Transmission, v.Weight, v.Height, v.Length, v.Width,
+		internal.NormalizeRegistration(v.Registration), v.Id)
+	if err != nil {
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = internal.ErrRepositoryVehicleNotFound
+	}
+	return
+}
+
+// checkRegistration is a function that returns ErrRepositoryRegistrationTaken if a vehicle other than id has the registration
+// - an empty registration is not checked
+func checkRegistration(tx *sql.Tx, id int, registration string) (err error) {
+	key := internal.NormalizeRegistration(registration)
+	if key == "" {
+		return
+	}
+
+	var other int
+	err = tx.QueryRow("SELECT id FROM vehicles WHERE registration_key = ? AND id <> ? LIMIT 1", key, id).Scan(&other)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = nil
+	case err == nil:
+		err = fmt.Errorf("%w: id %d", internal.ErrRepositoryRegistrationTaken, other)
+	}
+	return
+}
+
+// write is a method that runs fn in a transaction, errors of the database are wrapped with ErrRepositoryDatabase
+func (r *RepositoryVehicleSQLite) write(fn func(tx *sql.Tx) (err error)) (err error) {
+	err = r.tx(fn)
+	if err != nil && !errors.Is(err, internal.ErrRepositoryVehicleNotFound) && !errors.Is(err, internal.ErrRepositoryRegistrationTaken) &&
+		!errors.Is(err, internal.ErrRepositoryDatabase) {
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+	}
+	return
+}
+
+// FindAll is a method that returns a map of all vehicles
+func (r *RepositoryVehicleSQLite) FindAll() (v map[int]internal.Vehicle, err error) {
+	v, err = query(r.db, "")
+	return
+}
+
+// Count is a method that returns the number of vehicles, without reading them
+func (r *RepositoryVehicleSQLite) Count() (n int, err error) {
+	err = r.db.QueryRow("SELECT COUNT(*) FROM vehicles").Scan(&n)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+	}
+	return
+}
+
+// CountByBrand is a method that returns the number of vehicles of each brand, without reading them
+func (r *RepositoryVehicleSQLite) CountByBrand() (n map[string]int, err error) {
+	rows, err := r.db.Query("SELECT brand, COUNT(*) FROM vehicles GROUP BY brand")
+	if err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+		return
+	}
+	defer rows.Close()
+
+	n = make(map[string]int)
+	for rows.Next() {
+		var brand string
+		var count int
+		if err = rows.Scan(&brand, &count); err != nil {
+			n = nil
+			err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+			return
+		}
+		n[brand] = count
+	}
+	if err = rows.Err(); err != nil {
+		n = nil
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryDatabase, err)
+	}
+
+	return
+}
+
+// FindById is a method that returns the vehicle with the id
+func (r *RepositoryVehicleSQLite) FindById(id int) (v internal.Vehicle, err error) {
+	vs, err := query(r.db, "WHERE id = ?", id)
+	if err != nil {
+		return
+	}
+
+	v, ok := vs[id]
+	if !ok {
+		err = internal.ErrRepositoryVehicleNotFound
+		return
+	}
+
+	return
+}
+
+// FindByRegistration is a method that returns the vehicle with the registration, compared by internal.NormalizeRegistration
+func (r *RepositoryVehicleSQLite) FindByRegistration(registration string) (v internal.Vehicle, err error) {
+	vs, err := query(r.db, "WHERE registration_key = ?", internal.NormalizeRegistration(registration))
+	if err != nil {
+		return
+	}
+
+	switch len(vs) {
+	case 0:
+		err = internal.ErrRepositoryVehicleNotFound
+	case 1:
+		for _, vh := range vs {
+			v = vh
+		}
+	default:
+		ids := make([]int, 0, len(vs))
+		for id := range vs {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		err = fmt.Errorf("%w: ids %v", internal.ErrRepositoryRegistrationAmbiguous, ids)
+	}
+
+	return
+}
+
+// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
+func (r *RepositoryVehicleSQLite) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+	v, err = query(r.db, "WHERE color = ? AND year = ?", color, fabricationYear)
+	return
+}
+
+// FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
+func (r *RepositoryVehicleSQLite) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	v, err = query(r.db, "WHERE brand = ? AND year BETWEEN ? AND ?", brand, startYear, endYear)
+	return
+}
+
+// FindByBrand is a method that returns a map of vehicles that match the brand
+func (r *RepositoryVehicleSQLite) FindByBrand(brand string) (v map[int]internal.Vehicle, err error) {
+	v, err = query(r.db, "WHERE brand = ?", brand)
+	return
+}
+
+// FindByWeightRange is a method that returns a map of vehicles that match the weight range
+func (r *RepositoryVehicleSQLite) FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error) {
+	v, err = query(r.db, "WHERE weight BETWEEN ? AND ?", fromWeight, toWeight)
+	return
+}
+
+// sqliteOperators are the SQL comparisons of the operators that take one value
+var sqliteOperators = map[internal.FilterOperator]string{
+	internal.FilterOperatorEq:  "=",
+	internal.FilterOperatorGt:  ">",
+	internal.FilterOperatorGte: ">=",
+	internal.FilterOperatorLt:  "<",
+	internal.FilterOperatorLte: "<=",
+}
+
+// FindByFilter is a method that returns a map of vehicles that match the filter
+// - the conditions are translated to a WHERE clause, so the indexes of the table are used by SQLite
+func (r *RepositoryVehicleSQLite) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	// where
+	var where []string
+	var args []any
+	for _, c := range filter.Conditions {
+		// fields are columns, only known fields are written in the query
+		if !c.Field.Valid() {
+			err = fmt.Errorf("%w: unknown field %q", internal.ErrRepositoryInvalidFind, c.Field)
+			return
+		}
+
+		var values []any
+		if c.Field.Numeric() {
+			for _, n := range c.Numbers {
+				values = append(values, n)
+			}
+		} else {
+			for _, s := range c.Strings {
+				values = append(values, s)
+			}
+		}
+
+		if c.Operator == internal.FilterOperatorIn {
+			where = append(where, string(c.Field)+" IN (?"+strings.Repeat(", ?", len(values)-1)+")")
+			args = append(args, values...)
+			continue
+		}
+		where = append(where, string(c.Field)+" "+sqliteOperators[c.Operator]+" ?")
+		args = append(args, values[0])
+	}
+
+	// query
+	clause := ""
+	if len(where) > 0 {
+		clause = "WHERE " + strings.Join(where, " AND ")
+	}
+	v, err = query(r.db, clause, args...)
+	return
+}
+
+// Save is a method that saves a new vehicle and sets its id
+// - ids are never reused, a new vehicle takes the highest id ever saved plus one
+func (r *RepositoryVehicleSQLite) Save(v *internal.Vehicle) (err error) {
+	err = r.write(func(tx *sql.Tx) (err error) {
+		id, err := insert(tx, nil, *v)
+		if err != nil {
+			return
+		}
+		err = checkRegistration(tx, id, v.Registration)
+		if err != nil {
+			return
+		}
+
+		// set id
+		v.Id = id
+
+		return
+	})
+	return
+}
+
+// Update is a method that replaces all the attributes of an existing vehicle
+func (r *RepositoryVehicleSQLite) Update(v internal.Vehicle) (err error) {
+	err = r.write(func(tx *sql.Tx) (err error) {
+		err = update(tx, v)
+		if err != nil {
+			return
+		}
+		err = checkRegistration(tx, v.Id, v.Registration)
+		return
+	})
+	return
+}
+
+// Patch is a method that updates only the set attributes of an existing vehicle
+func (r *RepositoryVehicleSQLite) Patch(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	err = r.write(func(tx *sql.Tx) (err error) {
+		// check if vehicle exists
+		vs, err := query(tx, "WHERE id = ?", id)
+		if err != nil {
+			return
+		}
+		vh, ok := vs[id]
+		if !ok {
+			err = internal.ErrRepositoryVehicleNotFound
+			return
+		}
+
+		// patch vehicle
+		patch.Apply(&vh.VehicleAttributes)
+		err = update(tx, vh)
+		if err != nil {
+			return
+		}
+		err = checkRegistration(tx, id, vh.Registration)
+		if err != nil {
+			return
+		}
+		v = vh
+
+		return
+	})
+	return
+}
+
+// Delete is a method that deletes an existing vehicle
+func (r *RepositoryVehicleSQLite) Delete(id int) (err error) {
+	err = r.write(func(tx *sql.Tx) (err error) {
+		res, err := tx.Exec("DELETE FROM vehicles WHERE id = ?", id)
+		if err != nil {
+			return
+		}
+
+		// check if vehicle existed
+		n, err := res.RowsAffected()
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			err = internal.ErrRepositoryVehicleNotFound
+		}
+
+		return
+	})
+	return
+}
+
+// Replace is a method that replaces all the vehicles at once
+// - readers see either the previous or the new vehicles, never a mix of both
+// - the ids of the previous vehicles are not given again to new vehicles
+func (r *RepositoryVehicleSQLite) Replace(v map[int]internal.Vehicle) (err error) {
+	err = r.write(func(tx *sql.Tx) (err error) {
+		if _, err = tx.Exec("DELETE FROM vehicles"); err != nil {
+			return
+		}
+
+		for key, value := range v {
+			if _, err = insert(tx, key, value); err != nil {
+				return
+			}
+		}
+
+		return
+	})
+	return
+}
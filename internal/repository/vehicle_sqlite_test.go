@@ -0,0 +1,69 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newRepositoryVehicleSQLite is a function that returns a repository over a new in-memory database with the vehicles of db
+func newRepositoryVehicleSQLite(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryVehicle {
+	t.Helper()
+	rp, err := repository.NewRepositoryVehicleSQLite(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rp.Close() })
+	require.NoError(t, rp.Replace(db))
+	return rp
+}
+
+func TestRepositoryVehicleSQLite(t *testing.T) {
+	testRepositoryVehicle(t, newRepositoryVehicleSQLite)
+}
+
+func TestRepositoryVehicleSQLite_File(t *testing.T) {
+	t.Run("Vehicles persist and migrations are applied once", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "vehicles.db")
+		rp, err := repository.NewRepositoryVehicleSQLite(path)
+		require.NoError(t, err)
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "A", Registration: "AB 123", Weight: 1.5}}
+		require.NoError(t, rp.Save(&v))
+		require.NoError(t, rp.Close())
+
+		// When
+		rp, err = repository.NewRepositoryVehicleSQLite(path)
+		require.NoError(t, err)
+		defer rp.Close()
+		result, errFind := rp.FindByRegistration("ab123")
+		// Then
+		require.NoError(t, errFind)
+		require.Equal(t, v, result)
+	})
+
+	t.Run("Ids of deleted vehicles are not reused", func(t *testing.T) {
+		// Given
+		rp := newRepositoryVehicleSQLite(t, map[int]internal.Vehicle{1: {Id: 1}, 2: {Id: 2}})
+		require.NoError(t, rp.Delete(2))
+
+		// When
+		v := internal.Vehicle{}
+		err := rp.Save(&v)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, 3, v.Id)
+	})
+
+	t.Run("Invalid database", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "missing", "vehicles.db")
+
+		// When
+		_, err := repository.NewRepositoryVehicleSQLite(path)
+		// Then
+		require.ErrorIs(t, err, internal.ErrRepositoryDatabase)
+		require.ErrorIs(t, err, internal.ErrUnavailable)
+	})
+}
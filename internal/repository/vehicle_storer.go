@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NewRepositoryVehicleStorer is a function that returns a new instance of RepositoryVehicleStorer
+// - flushInterval: 0 stores the vehicles after each write, otherwise they are stored periodically
+func NewRepositoryVehicleStorer(rp internal.RepositoryVehicle, st internal.StorerVehicle, flushInterval time.Duration) *RepositoryVehicleStorer {
+	r := &RepositoryVehicleStorer{
+		RepositoryVehicle: rp,
+		st:                st,
+		flushInterval:     flushInterval,
+		done:              make(chan struct{}),
+	}
+
+	// flush periodically
+	if flushInterval > 0 {
+		r.wg.Add(1)
+		go r.flushLoop()
+	}
+
+	return r
+}
+
+// RepositoryVehicleStorer is a struct that represents a vehicle repository that stores the vehicles after being modified
+// - reads are delegated to the underlying repository
+// - with no flush interval, a write that can not be stored is rolled back, so an error means the vehicles did not change
+type RepositoryVehicleStorer struct {
+	// RepositoryVehicle is the underlying repository
+	internal.RepositoryVehicle
+	// st is the storer used to persist the vehicles
+	st internal.StorerVehicle
+	// flushInterval is the interval between stores, 0 stores after each write
+	flushInterval time.Duration
+
+	// mu is the mutex that protects the dirty flag and serializes the writes and the stores
+	mu sync.Mutex
+	// dirty is a flag that indicates that there are changes not stored yet
+	dirty bool
+	// lastErr is the error of the last periodic store
+	lastErr error
+
+	// done is the channel used to stop the periodic flush
+	done chan struct{}
+	// wg waits for the periodic flush to finish
+	wg sync.WaitGroup
+	// closeOnce guards the close of done
+	closeOnce sync.Once
+}
+
+// Save is a method that saves a new vehicle and sets its id
+func (r *RepositoryVehicleStorer) Save(v *internal.Vehicle) (err error) {
+	err = r.write(func() error { return r.RepositoryVehicle.Save(v) })
+	return
+}
+
+// Update is a method that replaces all the attributes of an existing vehicle
+func (r *RepositoryVehicleStorer) Update(v internal.Vehicle) (err error) {
+	err = r.write(func() error { return r.RepositoryVehicle.Update(v) })
+	return
+}
+
+// Patch is a method that updates only the set attributes of an existing vehicle
+func (r *RepositoryVehicleStorer) Patch(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	err = r.write(func() (err error) {
+		v, err = r.RepositoryVehicle.Patch(id, patch)
+		return
+	})
+	return
+}
+
+// Delete is a method that deletes an existing vehicle
+func (r *RepositoryVehicleStorer) Delete(id int) (err error) {
+	err = r.write(func() error { return r.RepositoryVehicle.Delete(id) })
+	return
+}
+
+// Replace is a method that replaces all the vehicles at once with the ones read from where they are stored
+// - the vehicles are not stored again, and they are not replaced while there are changes not stored yet, since they would be lost
+func (r *RepositoryVehicleStorer) Replace(v map[int]internal.Vehicle) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.dirty {
+		err = internal.ErrRepositoryVehiclePending
+		return
+	}
+	err = r.RepositoryVehicle.Replace(v)
+	return
+}
+
+// Flush is a method that stores the vehicles if there are changes not stored yet
+func (r *RepositoryVehicleStorer) Flush() (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = r.flush()
+	return
+}
+
+// Close is a method that stops the periodic flush and stores the pending changes
+func (r *RepositoryVehicleStorer) Close() (err error) {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+	r.wg.Wait()
+
+	err = r.Flush()
+	return
+}
+
+// LastError is a method that returns the error of the last periodic store
+func (r *RepositoryVehicleStorer) LastError() (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = r.lastErr
+	return
+}
+
+// write is a method that applies a write to the underlying repository and marks it as modified
+// - with no flush interval the vehicles are stored, and restored as they were if the store fails
+func (r *RepositoryVehicleStorer) write(fn func() error) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.flushInterval > 0 {
+		err = fn()
+		if err != nil {
+			return
+		}
+		r.dirty = true
+		return
+	}
+
+	before, err := r.RepositoryVehicle.FindAll()
+	if err != nil {
+		return
+	}
+	dirty := r.dirty
+	err = fn()
+	if err != nil {
+		return
+	}
+	r.dirty = true
+	err = r.flush()
+	if err == nil {
+		return
+	}
+	if e := r.RepositoryVehicle.Replace(before); e != nil {
+		// the write is kept dirty, to be stored again with the next change or flush
+		err = errors.Join(err, e)
+		return
+	}
+	r.dirty = dirty
+	return
+}
+
+// flush is a method that stores the vehicles if dirty
+// - mu must be held by the caller
+func (r *RepositoryVehicleStorer) flush() (err error) {
+	if !r.dirty {
+		return
+	}
+
+	v, err := r.RepositoryVehicle.FindAll()
+	if err != nil {
+		return
+	}
+	err = r.st.Store(v)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrRepositoryVehicleStore, err)
+		return
+	}
+	r.dirty = false
+
+	return
+}
+
+// flushLoop is a method that stores the vehicles every flush interval until closed
+func (r *RepositoryVehicleStorer) flushLoop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			r.lastErr = r.flush()
+			r.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// storerVehicleStub is a storer that keeps the last stored vehicles
+type storerVehicleStub struct {
+	stored map[int]internal.Vehicle
+	calls  int
+	err    error
+}
+
+func (s *storerVehicleStub) Store(v map[int]internal.Vehicle) (err error) {
+	s.calls++
+	if s.err != nil {
+		return s.err
+	}
+	s.stored = v
+	return
+}
+
+func TestRepositoryVehicleStorer_Write(t *testing.T) {
+	t.Run("Store after each write", func(t *testing.T) {
+		// Given
+		st := &storerVehicleStub{}
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(nil), st, 0)
+
+		expectedResult := map[int]internal.Vehicle{1: {
+			Id: 1,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand: "B",
+			},
+		}}
+		// When
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "A"}}
+		err1 := rp.Save(&v)
+		brand := "B"
+		_, err2 := rp.Patch(1, internal.VehiclePatch{Brand: &brand})
+		// Then
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, 2, st.calls)
+		assert.Equal(t, expectedResult, st.stored)
+	})
+
+	t.Run("Do not store if the write fails", func(t *testing.T) {
+		// Given
+		st := &storerVehicleStub{}
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(nil), st, 0)
+
+		expectedError := internal.ErrRepositoryVehicleNotFound
+		// When
+		err := rp.Delete(1)
+		// Then
+		assert.ErrorIs(t, err, expectedError)
+		assert.Equal(t, 0, st.calls)
+	})
+
+	t.Run("Roll back a write that can not be stored", func(t *testing.T) {
+		// Given
+		db := map[int]internal.Vehicle{1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "A"}}}
+		st := &storerVehicleStub{err: errors.New("disk full")}
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(db), st, 0)
+
+		// When
+		v := internal.Vehicle{}
+		errSave := rp.Save(&v)
+		errDelete := rp.Delete(1)
+		st.err = nil
+		errFlush := rp.Flush()
+		result, errFind := rp.FindAll()
+		// Then
+		assert.EqualError(t, errSave, "repository: vehicles could not be stored: disk full")
+		assert.ErrorIs(t, errSave, internal.ErrRepositoryVehicleStore)
+		assert.ErrorIs(t, errSave, internal.ErrUnavailable)
+		assert.ErrorIs(t, errDelete, internal.ErrRepositoryVehicleStore)
+		assert.Nil(t, errFlush)
+		assert.Nil(t, errFind)
+		assert.Equal(t, db, result)
+		assert.Equal(t, 2, st.calls)
+		assert.Nil(t, st.stored)
+	})
+
+	t.Run("Store the write after a rolled back one", func(t *testing.T) {
+		// Given
+		st := &storerVehicleStub{err: errors.New("disk full")}
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(nil), st, 0)
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "A"}}
+		_ = rp.Save(&v)
+		st.err = nil
+
+		// When
+		w := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "B"}}
+		err := rp.Save(&w)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, map[int]internal.Vehicle{w.Id: w}, st.stored)
+	})
+}
+
+func TestRepositoryVehicleStorer_FlushInterval(t *testing.T) {
+	t.Run("Store pending changes on close", func(t *testing.T) {
+		// Given
+		st := &storerVehicleStub{}
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(nil), st, time.Hour)
+
+		// When
+		v := internal.Vehicle{}
+		err := rp.Save(&v)
+		callsBeforeClose := st.calls
+		errClose := rp.Close()
+		// Then
+		assert.Nil(t, err)
+		assert.Nil(t, errClose)
+		assert.Equal(t, 0, callsBeforeClose)
+		assert.Equal(t, 1, st.calls)
+		assert.Equal(t, map[int]internal.Vehicle{1: {Id: 1}}, st.stored)
+	})
+
+	t.Run("Nothing to store", func(t *testing.T) {
+		// Given
+		st := &storerVehicleStub{}
+		rp := repository.NewRepositoryVehicleStorer(repository.NewRepositoryReadVehicleMap(nil), st, time.Hour)
+
+		// When
+		err := rp.Close()
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, 0, st.calls)
+	})
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"app/internal"
+	"sync/atomic"
+)
+
+// NewRepositoryVehicleVersioned is a function that returns a new instance of RepositoryVehicleVersioned
+func NewRepositoryVehicleVersioned(rp internal.RepositoryVehicle) *RepositoryVehicleVersioned {
+	return &RepositoryVehicleVersioned{RepositoryVehicle: rp}
+}
+
+// RepositoryVehicleVersioned is a struct that represents a vehicle repository that counts the changes of its vehicles
+// - reads are delegated to the underlying repository
+// - the version is increased once a write succeeds, so a version read before a read may name vehicles newer than it:
+// their ETag only fails a later revalidation, while increasing it before the write could name older vehicles with the
+// new version, served from the cache until the next change
+type RepositoryVehicleVersioned struct {
+	// RepositoryVehicle is the underlying repository
+	internal.RepositoryVehicle
+	// version is the number of writes that succeeded
+	version atomic.Uint64
+}
+
+// Version is a method that returns the version of the vehicles, increased by every change
+func (r *RepositoryVehicleVersioned) Version() (v uint64) {
+	v = r.version.Load()
+	return
+}
+
+// Save is a method that saves a new vehicle and sets its id
+func (r *RepositoryVehicleVersioned) Save(v *internal.Vehicle) (err error) {
+	err = r.RepositoryVehicle.Save(v)
+	if err != nil {
+		return
+	}
+	r.version.Add(1)
+	return
+}
+
+// Update is a method that replaces all the attributes of an existing vehicle
+func (r *RepositoryVehicleVersioned) Update(v internal.Vehicle) (err error) {
+	err = r.RepositoryVehicle.Update(v)
+	if err != nil {
+		return
+	}
+	r.version.Add(1)
+	return
+}
+
+// Patch is a method that updates only the set attributes of an existing vehicle
+func (r *RepositoryVehicleVersioned) Patch(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	v, err = r.RepositoryVehicle.Patch(id, patch)
+	if err != nil {
+		return
+	}
+	r.version.Add(1)
+	return
+}
+
+// Delete is a method that deletes an existing vehicle
+func (r *RepositoryVehicleVersioned) Delete(id int) (err error) {
+	err = r.RepositoryVehicle.Delete(id)
+	if err != nil {
+		return
+	}
+	r.version.Add(1)
+	return
+}
+
+// Replace is a method that replaces all the vehicles at once
+func (r *RepositoryVehicleVersioned) Replace(v map[int]internal.Vehicle) (err error) {
+	err = r.RepositoryVehicle.Replace(v)
+	if err != nil {
+		return
+	}
+	r.version.Add(1)
+	return
+}
@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryVehicleVersioned(t *testing.T) {
+	testRepositoryVehicle(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryVehicle {
+		return repository.NewRepositoryVehicleVersioned(repository.NewRepositoryReadVehicleMap(db))
+	})
+}
+
+func TestRepositoryVehicleVersioned_Version(t *testing.T) {
+	t.Run("Increase after each write", func(t *testing.T) {
+		// Given
+		rp := repository.NewRepositoryVehicleVersioned(repository.NewRepositoryReadVehicleMap(nil))
+
+		// When
+		v0 := rp.Version()
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "A"}}
+		err1 := rp.Save(&v)
+		v.Brand = "B"
+		err2 := rp.Update(v)
+		brand := "C"
+		_, err3 := rp.Patch(v.Id, internal.VehiclePatch{Brand: &brand})
+		err4 := rp.Delete(v.Id)
+		err5 := rp.Replace(map[int]internal.Vehicle{1: {Id: 1}})
+		// Then
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Nil(t, err4)
+		assert.Nil(t, err5)
+		assert.Equal(t, uint64(0), v0)
+		assert.Equal(t, uint64(5), rp.Version())
+	})
+
+	t.Run("Keep the version if the write fails or only reads", func(t *testing.T) {
+		// Given
+		rp := repository.NewRepositoryVehicleVersioned(repository.NewRepositoryReadVehicleMap(nil))
+
+		// When
+		err1 := rp.Delete(1)
+		err2 := rp.Update(internal.Vehicle{Id: 1})
+		_, err3 := rp.FindAll()
+		// Then
+		assert.ErrorIs(t, err1, internal.ErrRepositoryVehicleNotFound)
+		assert.ErrorIs(t, err2, internal.ErrRepositoryVehicleNotFound)
+		assert.Nil(t, err3)
+		assert.Equal(t, uint64(0), rp.Version())
+	})
+}
@@ -0,0 +1,3 @@
+// Package servicetest provides the mock of the vehicle service, for the tests of the packages that use it
+// - it imports testing, so it must only be imported by tests
+package servicetest
@@ -0,0 +1,516 @@
+// Code generated by mockgen. DO NOT EDIT.
+
+package servicetest
+
+import (
+	"app/internal"
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// NewVehicleDefaultMock is a function that returns a new instance of VehicleDefaultMock
+// - a call to a method without its function configured fails the test t
+func NewVehicleDefaultMock(t testing.TB) *VehicleDefaultMock {
+	return &VehicleDefaultMock{t: t}
+}
+
+// VehicleDefaultMock is a struct that implements the internal.ServiceVehicle interface for tests
+// - each method records its arguments and calls the function of the field with its name and the suffix Func
+type VehicleDefaultMock struct {
+	// FindByIdFunc is the function called by FindById
+	FindByIdFunc func(ctx context.Context, id int) (v internal.Vehicle, err error)
+	// FindByRegistrationFunc is the function called by FindByRegistration
+	FindByRegistrationFunc func(ctx context.Context, registration string) (v internal.Vehicle, err error)
+	// FindByColorAndYearFunc is the function called by FindByColorAndYear
+	FindByColorAndYearFunc func(ctx context.Context, color string, fabricationYear int) (v map[int]internal.Vehicle, err error)
+	// FindByBrandAndYearRangeFunc is the function called by FindByBrandAndYearRange
+	FindByBrandAndYearRangeFunc func(ctx context.Context, brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error)
+	// AverageMaxSpeedByBrandFunc is the function called by AverageMaxSpeedByBrand
+	AverageMaxSpeedByBrandFunc func(ctx context.Context, brand string) (a float64, err error)
+	// AverageCapacityByBrandFunc is the function called by AverageCapacityByBrand
+	AverageCapacityByBrandFunc func(ctx context.Context, brand string) (a int, err error)
+	// SearchByWeightRangeFunc is the function called by SearchByWeightRange
+	SearchByWeightRangeFunc func(ctx context.Context, query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error)
+	// SearchFunc is the function called by Search
+	SearchFunc func(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error)
+	// StatsFunc is the function called by Stats
+	StatsFunc func(ctx context.Context, filter internal.VehicleFilter, groupBy []internal.VehicleField, metrics []internal.VehicleMetric) (rows []internal.VehicleStatsRow, err error)
+	// SaveFunc is the function called by Save
+	SaveFunc func(ctx context.Context, v *internal.Vehicle) (err error)
+	// UpdateFunc is the function called by Update
+	UpdateFunc func(ctx context.Context, v internal.Vehicle) (err error)
+	// PatchFunc is the function called by Patch
+	PatchFunc func(ctx context.Context, id int, patch internal.VehiclePatch) (v internal.Vehicle, err error)
+	// DeleteFunc is the function called by Delete
+	DeleteFunc func(ctx context.Context, id int) (err error)
+
+	// t is the test that uses the mock
+	t testing.TB
+	// mu is the mutex that guards the calls
+	mu sync.Mutex
+	// calls are the names of the methods called, in order
+	calls []string
+	// findByIdCalls are the arguments of the calls to FindById, in order
+	findByIdCalls []VehicleDefaultMockFindByIdCall
+	// findByRegistrationCalls are the arguments of the calls to FindByRegistration, in order
+	findByRegistrationCalls []VehicleDefaultMockFindByRegistrationCall
+	// findByColorAndYearCalls are the arguments of the calls to FindByColorAndYear, in order
+	findByColorAndYearCalls []VehicleDefaultMockFindByColorAndYearCall
+	// findByBrandAndYearRangeCalls are the arguments of the calls to FindByBrandAndYearRange, in order
+	findByBrandAndYearRangeCalls []VehicleDefaultMockFindByBrandAndYearRangeCall
+	// averageMaxSpeedByBrandCalls are the arguments of the calls to AverageMaxSpeedByBrand, in order
+	averageMaxSpeedByBrandCalls []VehicleDefaultMockAverageMaxSpeedByBrandCall
+	// averageCapacityByBrandCalls are the arguments of the calls to AverageCapacityByBrand, in order
+	averageCapacityByBrandCalls []VehicleDefaultMockAverageCapacityByBrandCall
+	// searchByWeightRangeCalls are the arguments of the calls to SearchByWeightRange, in order
+	searchByWeightRangeCalls []VehicleDefaultMockSearchByWeightRangeCall
+	// searchCalls are the arguments of the calls to Search, in order
+	searchCalls []VehicleDefaultMockSearchCall
+	// statsCalls are the arguments of the calls to Stats, in order
+	statsCalls []VehicleDefaultMockStatsCall
+	// saveCalls are the arguments of the calls to Save, in order
+	saveCalls []VehicleDefaultMockSaveCall
+	// updateCalls are the arguments of the calls to Update, in order
+	updateCalls []VehicleDefaultMockUpdateCall
+	// patchCalls are the arguments of the calls to Patch, in order
+	patchCalls []VehicleDefaultMockPatchCall
+	// deleteCalls are the arguments of the calls to Delete, in order
+	deleteCalls []VehicleDefaultMockDeleteCall
+}
+
+// Calls is a method that returns the names of the methods called, in order
+func (mk *VehicleDefaultMock) Calls() []string {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.calls)
+}
+
+// AssertCalls is a method that checks that the methods called are the methods given, in the same order
+func (mk *VehicleDefaultMock) AssertCalls(methods ...string) bool {
+	mk.t.Helper()
+	calls := mk.Calls()
+	if !slices.Equal(calls, methods) {
+		mk.t.Errorf("VehicleDefaultMock: calls %v, expected %v", calls, methods)
+		return false
+	}
+	return true
+}
+
+// VehicleDefaultMockFindByIdCall is a struct that represents the arguments of a call to FindById
+type VehicleDefaultMockFindByIdCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Id is the argument id
+	Id int
+}
+
+// FindById is a method that records the call and returns the results of FindByIdFunc
+func (mk *VehicleDefaultMock) FindById(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindById")
+	mk.findByIdCalls = append(mk.findByIdCalls, VehicleDefaultMockFindByIdCall{Ctx: ctx, Id: id})
+	fn := mk.FindByIdFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to FindById, FindByIdFunc is not configured")
+		return
+	}
+	return fn(ctx, id)
+}
+
+// FindByIdCalls is a method that returns the arguments of the calls to FindById, in order
+func (mk *VehicleDefaultMock) FindByIdCalls() []VehicleDefaultMockFindByIdCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByIdCalls)
+}
+
+// VehicleDefaultMockFindByRegistrationCall is a struct that represents the arguments of a call to FindByRegistration
+type VehicleDefaultMockFindByRegistrationCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Registration is the argument registration
+	Registration string
+}
+
+// FindByRegistration is a method that records the call and returns the results of FindByRegistrationFunc
+func (mk *VehicleDefaultMock) FindByRegistration(ctx context.Context, registration string) (v internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByRegistration")
+	mk.findByRegistrationCalls = append(mk.findByRegistrationCalls, VehicleDefaultMockFindByRegistrationCall{Ctx: ctx, Registration: registration})
+	fn := mk.FindByRegistrationFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to FindByRegistration, FindByRegistrationFunc is not configured")
+		return
+	}
+	return fn(ctx, registration)
+}
+
+// FindByRegistrationCalls is a method that returns the arguments of the calls to FindByRegistration, in order
+func (mk *VehicleDefaultMock) FindByRegistrationCalls() []VehicleDefaultMockFindByRegistrationCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByRegistrationCalls)
+}
+
+// VehicleDefaultMockFindByColorAndYearCall is a struct that represents the arguments of a call to FindByColorAndYear
+type VehicleDefaultMockFindByColorAndYearCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Color is the argument color
+	Color string
+	// FabricationYear is the argument fabricationYear
+	FabricationYear int
+}
+
+// FindByColorAndYear is a method that records the call and returns the results of FindByColorAndYearFunc
+func (mk *VehicleDefaultMock) FindByColorAndYear(ctx context.Context, color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByColorAndYear")
+	mk.findByColorAndYearCalls = append(mk.findByColorAndYearCalls, VehicleDefaultMockFindByColorAndYearCall{Ctx: ctx, Color: color, FabricationYear: fabricationYear})
+	fn := mk.FindByColorAndYearFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to FindByColorAndYear, FindByColorAndYearFunc is not configured")
+		return
+	}
+	return fn(ctx, color, fabricationYear)
+}
+
+// FindByColorAndYearCalls is a method that returns the arguments of the calls to FindByColorAndYear, in order
+func (mk *VehicleDefaultMock) FindByColorAndYearCalls() []VehicleDefaultMockFindByColorAndYearCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByColorAndYearCalls)
+}
+
+// VehicleDefaultMockFindByBrandAndYearRangeCall is a struct that represents the arguments of a call to FindByBrandAndYearRange
+type VehicleDefaultMockFindByBrandAndYearRangeCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Brand is the argument brand
+	Brand string
+	// StartYear is the argument startYear
+	StartYear int
+	// EndYear is the argument endYear
+	EndYear int
+}
+
+// FindByBrandAndYearRange is a method that records the call and returns the results of FindByBrandAndYearRangeFunc
+func (mk *VehicleDefaultMock) FindByBrandAndYearRange(ctx context.Context, brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "FindByBrandAndYearRange")
+	mk.findByBrandAndYearRangeCalls = append(mk.findByBrandAndYearRangeCalls, VehicleDefaultMockFindByBrandAndYearRangeCall{Ctx: ctx, Brand: brand, StartYear: startYear, EndYear: endYear})
+	fn := mk.FindByBrandAndYearRangeFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to FindByBrandAndYearRange, FindByBrandAndYearRangeFunc is not configured")
+		return
+	}
+	return fn(ctx, brand, startYear, endYear)
+}
+
+// FindByBrandAndYearRangeCalls is a method that returns the arguments of the calls to FindByBrandAndYearRange, in order
+func (mk *VehicleDefaultMock) FindByBrandAndYearRangeCalls() []VehicleDefaultMockFindByBrandAndYearRangeCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.findByBrandAndYearRangeCalls)
+}
+
+// VehicleDefaultMockAverageMaxSpeedByBrandCall is a struct that represents the arguments of a call to AverageMaxSpeedByBrand
+type VehicleDefaultMockAverageMaxSpeedByBrandCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Brand is the argument brand
+	Brand string
+}
+
+// AverageMaxSpeedByBrand is a method that records the call and returns the results of AverageMaxSpeedByBrandFunc
+func (mk *VehicleDefaultMock) AverageMaxSpeedByBrand(ctx context.Context, brand string) (a float64, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "AverageMaxSpeedByBrand")
+	mk.averageMaxSpeedByBrandCalls = append(mk.averageMaxSpeedByBrandCalls, VehicleDefaultMockAverageMaxSpeedByBrandCall{Ctx: ctx, Brand: brand})
+	fn := mk.AverageMaxSpeedByBrandFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to AverageMaxSpeedByBrand, AverageMaxSpeedByBrandFunc is not configured")
+		return
+	}
+	return fn(ctx, brand)
+}
+
+// AverageMaxSpeedByBrandCalls is a method that returns the arguments of the calls to AverageMaxSpeedByBrand, in order
+func (mk *VehicleDefaultMock) AverageMaxSpeedByBrandCalls() []VehicleDefaultMockAverageMaxSpeedByBrandCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.averageMaxSpeedByBrandCalls)
+}
+
+// VehicleDefaultMockAverageCapacityByBrandCall is a struct that represents the arguments of a call to AverageCapacityByBrand
+type VehicleDefaultMockAverageCapacityByBrandCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Brand is the argument brand
+	Brand string
+}
+
+// AverageCapacityByBrand is a method that records the call and returns the results of AverageCapacityByBrandFunc
+func (mk *VehicleDefaultMock) AverageCapacityByBrand(ctx context.Context, brand string) (a int, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "AverageCapacityByBrand")
+	mk.averageCapacityByBrandCalls = append(mk.averageCapacityByBrandCalls, VehicleDefaultMockAverageCapacityByBrandCall{Ctx: ctx, Brand: brand})
+	fn := mk.AverageCapacityByBrandFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to AverageCapacityByBrand, AverageCapacityByBrandFunc is not configured")
+		return
+	}
+	return fn(ctx, brand)
+}
+
+// AverageCapacityByBrandCalls is a method that returns the arguments of the calls to AverageCapacityByBrand, in order
+func (mk *VehicleDefaultMock) AverageCapacityByBrandCalls() []VehicleDefaultMockAverageCapacityByBrandCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.averageCapacityByBrandCalls)
+}
+
+// VehicleDefaultMockSearchByWeightRangeCall is a struct that represents the arguments of a call to SearchByWeightRange
+type VehicleDefaultMockSearchByWeightRangeCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Query is the argument query
+	Query internal.SearchQuery
+	// Ok is the argument ok
+	Ok bool
+}
+
+// SearchByWeightRange is a method that records the call and returns the results of SearchByWeightRangeFunc
+func (mk *VehicleDefaultMock) SearchByWeightRange(ctx context.Context, query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "SearchByWeightRange")
+	mk.searchByWeightRangeCalls = append(mk.searchByWeightRangeCalls, VehicleDefaultMockSearchByWeightRangeCall{Ctx: ctx, Query: query, Ok: ok})
+	fn := mk.SearchByWeightRangeFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to SearchByWeightRange, SearchByWeightRangeFunc is not configured")
+		return
+	}
+	return fn(ctx, query, ok)
+}
+
+// SearchByWeightRangeCalls is a method that returns the arguments of the calls to SearchByWeightRange, in order
+func (mk *VehicleDefaultMock) SearchByWeightRangeCalls() []VehicleDefaultMockSearchByWeightRangeCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.searchByWeightRangeCalls)
+}
+
+// VehicleDefaultMockSearchCall is a struct that represents the arguments of a call to Search
+type VehicleDefaultMockSearchCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Filter is the argument filter
+	Filter internal.VehicleFilter
+}
+
+// Search is a method that records the call and returns the results of SearchFunc
+func (mk *VehicleDefaultMock) Search(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Search")
+	mk.searchCalls = append(mk.searchCalls, VehicleDefaultMockSearchCall{Ctx: ctx, Filter: filter})
+	fn := mk.SearchFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to Search, SearchFunc is not configured")
+		return
+	}
+	return fn(ctx, filter)
+}
+
+// SearchCalls is a method that returns the arguments of the calls to Search, in order
+func (mk *VehicleDefaultMock) SearchCalls() []VehicleDefaultMockSearchCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.searchCalls)
+}
+
+// VehicleDefaultMockStatsCall is a struct that represents the arguments of a call to Stats
+type VehicleDefaultMockStatsCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Filter is the argument filter
+	Filter internal.VehicleFilter
+	// GroupBy is the argument groupBy
+	GroupBy []internal.VehicleField
+	// Metrics is the argument metrics
+	Metrics []internal.VehicleMetric
+}
+
+// Stats is a method that records the call and returns the results of StatsFunc
+func (mk *VehicleDefaultMock) Stats(ctx context.Context, filter internal.VehicleFilter, groupBy []internal.VehicleField, metrics []internal.VehicleMetric) (rows []internal.VehicleStatsRow, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Stats")
+	mk.statsCalls = append(mk.statsCalls, VehicleDefaultMockStatsCall{Ctx: ctx, Filter: filter, GroupBy: groupBy, Metrics: metrics})
+	fn := mk.StatsFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to Stats, StatsFunc is not configured")
+		return
+	}
+	return fn(ctx, filter, groupBy, metrics)
+}
+
+// StatsCalls is a method that returns the arguments of the calls to Stats, in order
+func (mk *VehicleDefaultMock) StatsCalls() []VehicleDefaultMockStatsCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.statsCalls)
+}
+
+// VehicleDefaultMockSaveCall is a struct that represents the arguments of a call to Save
+type VehicleDefaultMockSaveCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// V is the argument v
+	V *internal.Vehicle
+}
+
+// Save is a method that records the call and returns the results of SaveFunc
+func (mk *VehicleDefaultMock) Save(ctx context.Context, v *internal.Vehicle) (err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Save")
+	mk.saveCalls = append(mk.saveCalls, VehicleDefaultMockSaveCall{Ctx: ctx, V: v})
+	fn := mk.SaveFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to Save, SaveFunc is not configured")
+		return
+	}
+	return fn(ctx, v)
+}
+
+// SaveCalls is a method that returns the arguments of the calls to Save, in order
+func (mk *VehicleDefaultMock) SaveCalls() []VehicleDefaultMockSaveCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.saveCalls)
+}
+
+// VehicleDefaultMockUpdateCall is a struct that represents the arguments of a call to Update
+type VehicleDefaultMockUpdateCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// V is the argument v
+	V internal.Vehicle
+}
+
+// Update is a method that records the call and returns the results of UpdateFunc
+func (mk *VehicleDefaultMock) Update(ctx context.Context, v internal.Vehicle) (err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Update")
+	mk.updateCalls = append(mk.updateCalls, VehicleDefaultMockUpdateCall{Ctx: ctx, V: v})
+	fn := mk.UpdateFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to Update, UpdateFunc is not configured")
+		return
+	}
+	return fn(ctx, v)
+}
+
+// UpdateCalls is a method that returns the arguments of the calls to Update, in order
+func (mk *VehicleDefaultMock) UpdateCalls() []VehicleDefaultMockUpdateCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.updateCalls)
+}
+
+// VehicleDefaultMockPatchCall is a struct that represents the arguments of a call to Patch
+type VehicleDefaultMockPatchCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Id is the argument id
+	Id int
+	// Patch is the argument patch
+	Patch internal.VehiclePatch
+}
+
+// Patch is a method that records the call and returns the results of PatchFunc
+func (mk *VehicleDefaultMock) Patch(ctx context.Context, id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Patch")
+	mk.patchCalls = append(mk.patchCalls, VehicleDefaultMockPatchCall{Ctx: ctx, Id: id, Patch: patch})
+	fn := mk.PatchFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to Patch, PatchFunc is not configured")
+		return
+	}
+	return fn(ctx, id, patch)
+}
+
+// PatchCalls is a method that returns the arguments of the calls to Patch, in order
+func (mk *VehicleDefaultMock) PatchCalls() []VehicleDefaultMockPatchCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.patchCalls)
+}
+
+// VehicleDefaultMockDeleteCall is a struct that represents the arguments of a call to Delete
+type VehicleDefaultMockDeleteCall struct {
+	// Ctx is the argument ctx
+	Ctx context.Context
+	// Id is the argument id
+	Id int
+}
+
+// Delete is a method that records the call and returns the results of DeleteFunc
+func (mk *VehicleDefaultMock) Delete(ctx context.Context, id int) (err error) {
+	mk.mu.Lock()
+	mk.calls = append(mk.calls, "Delete")
+	mk.deleteCalls = append(mk.deleteCalls, VehicleDefaultMockDeleteCall{Ctx: ctx, Id: id})
+	fn := mk.DeleteFunc
+	mk.mu.Unlock()
+
+	if fn == nil {
+		mk.t.Helper()
+		mk.t.Errorf("VehicleDefaultMock: unexpected call to Delete, DeleteFunc is not configured")
+		return
+	}
+	return fn(ctx, id)
+}
+
+// DeleteCalls is a method that returns the arguments of the calls to Delete, in order
+func (mk *VehicleDefaultMock) DeleteCalls() []VehicleDefaultMockDeleteCall {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+	return slices.Clone(mk.deleteCalls)
+}
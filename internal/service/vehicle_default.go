@@ -1,32 +1,82 @@
 package service
 
-import "app/internal"
+import (
+	"app/internal"
+	"app/platform/logging"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+//go:generate go run app/cmd/mockgen -source .. -interface ServiceVehicle -type VehicleDefaultMock -package servicetest -output servicetest/vehicle_default_mock.go
 
 // ServiceVehicleDefault is a struct that represents the default service for vehicles
 type ServiceVehicleDefault struct {
 	// rp is the repository that will be used by the service
-	rp internal.RepositoryReadVehicle
+	rp internal.RepositoryVehicle
 }
 
 // NewServiceVehicleDefault is a function that returns a new instance of ServiceVehicleDefault
-func NewServiceVehicleDefault(rp internal.RepositoryReadVehicle) *ServiceVehicleDefault {
+func NewServiceVehicleDefault(rp internal.RepositoryVehicle) *ServiceVehicleDefault {
 	return &ServiceVehicleDefault{rp: rp}
 }
 
+// FindById is a method that returns the vehicle with the id
+func (s *ServiceVehicleDefault) FindById(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	// check query
+	var vl internal.Validator
+	vl.Check(id > 0, "id", "must be positive")
+	if err = vl.Err(internal.ErrServiceInvalidFind); err != nil {
+		return
+	}
+
+	v, err = s.rp.FindById(id)
+	return
+}
+
+// FindByRegistration is a method that returns the vehicle with the registration, ignoring case and whitespace
+func (s *ServiceVehicleDefault) FindByRegistration(ctx context.Context, registration string) (v internal.Vehicle, err error) {
+	// check query
+	var vl internal.Validator
+	vl.Check(internal.NormalizeRegistration(registration) != "", "registration", "is required")
+	if err = vl.Err(internal.ErrServiceInvalidFind); err != nil {
+		return
+	}
+
+	v, err = s.rp.FindByRegistration(registration)
+	return
+}
+
 // FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
-func (s *ServiceVehicleDefault) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+func (s *ServiceVehicleDefault) FindByColorAndYear(ctx context.Context, color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+	// check query
+	var vl internal.Validator
+	vl.Year("year", fabricationYear)
+	if err = vl.Err(internal.ErrServiceInvalidFind); err != nil {
+		return
+	}
+
 	v, err = s.rp.FindByColorAndYear(color, fabricationYear)
 	return
 }
 
 // FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
-func (s *ServiceVehicleDefault) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+func (s *ServiceVehicleDefault) FindByBrandAndYearRange(ctx context.Context, brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	// check query
+	var vl internal.Validator
+	vl.Year("start_year", startYear)
+	vl.Year("end_year", endYear)
+	vl.Check(startYear <= endYear, "end_year", "must not be before start_year")
+	if err = vl.Err(internal.ErrServiceInvalidFind); err != nil {
+		return
+	}
+
 	v, err = s.rp.FindByBrandAndYearRange(brand, startYear, endYear)
 	return
 }
 
 // AverageMaxSpeedByBrand is a method that returns the average speed of the vehicles by brand
-func (s *ServiceVehicleDefault) AverageMaxSpeedByBrand(brand string) (a float64, err error) {
+func (s *ServiceVehicleDefault) AverageMaxSpeedByBrand(ctx context.Context, brand string) (a float64, err error) {
 	// get vehicles by brand
 	v, err := s.rp.FindByBrand(brand)
 	if err != nil {
@@ -49,7 +99,7 @@ func (s *ServiceVehicleDefault) AverageMaxSpeedByBrand(brand string) (a float64,
 }
 		
 // AverageCapacityByBrand is a method that returns the average capacity of the vehicles by brand
-func (s *ServiceVehicleDefault) AverageCapacityByBrand(brand string) (a int, err error) {
+func (s *ServiceVehicleDefault) AverageCapacityByBrand(ctx context.Context, brand string) (a int, err error) {
 	// get vehicles by brand
 	v, err := s.rp.FindByBrand(brand)
 	if err != nil {
@@ -72,14 +122,127 @@ func (s *ServiceVehicleDefault) AverageCapacityByBrand(brand string) (a int, err
 }
 
 // SearchByWeightRange
-func (s *ServiceVehicleDefault) SearchByWeightRange(query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
+func (s *ServiceVehicleDefault) SearchByWeightRange(ctx context.Context, query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
 	// check if query is set
 	if !ok {
 		v, err = s.rp.FindAll()
 		return
 	}
 
+	// check query
+	var vl internal.Validator
+	vl.NonNegative("weight_min", query.FromWeight)
+	vl.NonNegative("weight_max", query.ToWeight)
+	vl.Check(query.FromWeight <= query.ToWeight, "weight_max", "must not be less than weight_min")
+	if err = vl.Err(internal.ErrServiceInvalidSearch); err != nil {
+		return
+	}
+
 	v, err = s.rp.FindByWeightRange(query.FromWeight, query.ToWeight)
 	return
 }
-	
\ No newline at end of file
+
+// Search is a method that returns a map of vehicles that match the filter
+func (s *ServiceVehicleDefault) Search(ctx context.Context, filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	// check filter
+	if err = filter.Validate(); err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrServiceInvalidSearch, err)
+		return
+	}
+
+	v, err = s.rp.FindByFilter(filter)
+	if err != nil {
+		return
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelDebug, "vehicles searched",
+		slog.Int("conditions", len(filter.Conditions)),
+		slog.Int("found", len(v)),
+	)
+	return
+}
+
+// Stats is a method that returns the metrics of the vehicles that match the filter, grouped by the fields
+func (s *ServiceVehicleDefault) Stats(ctx context.Context, filter internal.VehicleFilter, groupBy []internal.VehicleField, metrics []internal.VehicleMetric) (rows []internal.VehicleStatsRow, err error) {
+	// check filter
+	if err = filter.Validate(); err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrServiceInvalidSearch, err)
+		return
+	}
+
+	// get vehicles
+	v, err := s.rp.FindByFilter(filter)
+	if err != nil {
+		return
+	}
+
+	// compute metrics
+	rows, err = internal.ComputeVehicleStats(v, groupBy, metrics)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", internal.ErrServiceInvalidSearch, err)
+		return
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelDebug, "vehicle stats computed",
+		slog.Int("conditions", len(filter.Conditions)),
+		slog.Int("vehicles", len(v)),
+		slog.Int("groups", len(rows)),
+	)
+	return
+}
+
+// Save is a method that saves a new vehicle and sets its id
+func (s *ServiceVehicleDefault) Save(ctx context.Context, v *internal.Vehicle) (err error) {
+	if err = v.Validate(); err != nil {
+		return
+	}
+
+	err = s.rp.Save(v)
+	if err != nil {
+		return
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "vehicle created", slog.Int("id", v.Id))
+	return
+}
+
+// Update is a method that replaces all the attributes of an existing vehicle
+func (s *ServiceVehicleDefault) Update(ctx context.Context, v internal.Vehicle) (err error) {
+	if err = v.Validate(); err != nil {
+		return
+	}
+
+	err = s.rp.Update(v)
+	if err != nil {
+		return
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "vehicle updated", slog.Int("id", v.Id))
+	return
+}
+
+// Patch is a method that updates only the set attributes of an existing vehicle
+func (s *ServiceVehicleDefault) Patch(ctx context.Context, id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	if err = patch.Validate(); err != nil {
+		return
+	}
+
+	v, err = s.rp.Patch(id, patch)
+	if err != nil {
+		return
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "vehicle patched", slog.Int("id", id))
+	return
+}
+
+// Delete is a method that deletes an existing vehicle
+func (s *ServiceVehicleDefault) Delete(ctx context.Context, id int) (err error) {
+	err = s.rp.Delete(id)
+	if err != nil {
+		return
+	}
+
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, "vehicle deleted", slog.Int("id", id))
+	return
+}
@@ -2,15 +2,17 @@ package service_test
 
 import (
 	"app/internal"
-	"app/internal/repository"
+	"app/internal/repository/repositorytest"
 	"app/internal/service"
+	"context"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func TestServiceVehicleDefault_FindByColorAndYear(t *testing.T) {
 	// Given
-	rp := repository.NewVehicleMapMock()
+	rp := repositorytest.NewVehicleMapMock(t)
 	rp.FindByColorAndYearFunc = func(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
 		return map[int]internal.Vehicle{1: {
 			Id: 1,
@@ -56,17 +58,17 @@ func TestServiceVehicleDefault_FindByColorAndYear(t *testing.T) {
 		},
 	}}
 	// When
-	result, err := sv.FindByColorAndYear("D", 1)
+	result, err := sv.FindByColorAndYear(context.Background(), "D", 2000)
 	// Then
 	assert.Nil(t, err)
 	assert.Equal(t, expectedResult, result)
-	assert.Equal(t, 1, rp.Spy.FindByColorAndYear)
+	assert.Equal(t, []repositorytest.VehicleMapMockFindByColorAndYearCall{{Color: "D", FabricationYear: 2000}}, rp.FindByColorAndYearCalls())
 
 }
 
 func TestServiceVehicleDefault_FindByBrandAndYearRange(t *testing.T) {
 	// Given
-	rp := repository.NewVehicleMapMock()
+	rp := repositorytest.NewVehicleMapMock(t)
 	rp.FindByBrandAndYearRangeFunc = func(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
 		return map[int]internal.Vehicle{1: {
 			Id: 1,
@@ -113,17 +115,17 @@ func TestServiceVehicleDefault_FindByBrandAndYearRange(t *testing.T) {
 		},
 	}}
 	// When
-	result, err := sv.FindByBrandAndYearRange("A", 0, 2)
+	result, err := sv.FindByBrandAndYearRange(context.Background(), "A", 2000, 2002)
 	// Then
 	assert.Nil(t, err)
 	assert.Equal(t, expectedResult, result)
-	assert.Equal(t, 1, rp.Spy.FindByBrandAndYearRange)
+	assert.Len(t, rp.FindByBrandAndYearRangeCalls(), 1)
 }
 
 func TestServiceVehicleDefault_AverageMaxSpeedByBrand(t *testing.T) {
 	t.Run("find two vehicles with an average of 5", func(t *testing.T) {
 		// Given
-		rp := repository.NewVehicleMapMock()
+		rp := repositorytest.NewVehicleMapMock(t)
 		rp.FindByBrandFunc = func(brand string) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
@@ -170,16 +172,16 @@ func TestServiceVehicleDefault_AverageMaxSpeedByBrand(t *testing.T) {
 
 		expectedResult := 3.0
 		// When
-		result, err := sv.AverageMaxSpeedByBrand("A")
+		result, err := sv.AverageMaxSpeedByBrand(context.Background(), "A")
 		// Then
 		assert.Nil(t, err)
 		assert.Equal(t, expectedResult, result)
-		assert.Equal(t, 1, rp.Spy.FindByBrand)
+		assert.Len(t, rp.FindByBrandCalls(), 1)
 	})
 
 	t.Run("no vehicles found", func(t *testing.T) {
 		// Given
-		rp := repository.NewVehicleMapMock()
+		rp := repositorytest.NewVehicleMapMock(t)
 		rp.FindByBrandFunc = func(brand string) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{}, nil
 		}
@@ -188,18 +190,18 @@ func TestServiceVehicleDefault_AverageMaxSpeedByBrand(t *testing.T) {
 
 		expectedError := internal.ErrServiceNoVehicles
 		// When
-		_, err := sv.AverageMaxSpeedByBrand("A")
+		_, err := sv.AverageMaxSpeedByBrand(context.Background(), "A")
 		// Then
 		assert.NotNil(t, err)
 		assert.Equal(t, expectedError, err)
-		assert.Equal(t, 1, rp.Spy.FindByBrand)
+		assert.Len(t, rp.FindByBrandCalls(), 1)
 	})
 }
 
 func TestServiceVehicleDefault_AverageCapacityByBrand(t *testing.T) {
 	t.Run("find two vehicles with an average capacity of 5", func(t *testing.T) {
 		// Given
-		rp := repository.NewVehicleMapMock()
+		rp := repositorytest.NewVehicleMapMock(t)
 		rp.FindByBrandFunc = func(brand string) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
@@ -246,16 +248,16 @@ func TestServiceVehicleDefault_AverageCapacityByBrand(t *testing.T) {
 
 		expectedResult := 5
 		// When
-		result, err := sv.AverageCapacityByBrand("A")
+		result, err := sv.AverageCapacityByBrand(context.Background(), "A")
 		// Then
 		assert.Nil(t, err)
 		assert.Equal(t, expectedResult, result)
-		assert.Equal(t, 1, rp.Spy.FindByBrand)
+		assert.Len(t, rp.FindByBrandCalls(), 1)
 	})
 
 	t.Run("no vehicles found", func(t *testing.T) {
 		// Given
-		rp := repository.NewVehicleMapMock()
+		rp := repositorytest.NewVehicleMapMock(t)
 		rp.FindByBrandFunc = func(brand string) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{}, nil
 		}
@@ -264,18 +266,18 @@ func TestServiceVehicleDefault_AverageCapacityByBrand(t *testing.T) {
 
 		expectedError := internal.ErrServiceNoVehicles
 		// When
-		_, err := sv.AverageCapacityByBrand("A")
+		_, err := sv.AverageCapacityByBrand(context.Background(), "A")
 		// Then
 		assert.NotNil(t, err)
 		assert.Equal(t, expectedError, err)
-		assert.Equal(t, 1, rp.Spy.FindByBrand)
+		assert.Len(t, rp.FindByBrandCalls(), 1)
 	})
 }
 
 func TestServiceVehicleDefault_SearchByWeightRange(t *testing.T) {
 	t.Run("Find without query", func(t *testing.T) {
 		// Given
-		rp := repository.NewVehicleMapMock()
+		rp := repositorytest.NewVehicleMapMock(t)
 		rp.FindAllFunc = func() (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
@@ -321,16 +323,16 @@ func TestServiceVehicleDefault_SearchByWeightRange(t *testing.T) {
 			},
 		}}
 		// When
-		result, err := sv.SearchByWeightRange(internal.SearchQuery{}, false)
+		result, err := sv.SearchByWeightRange(context.Background(), internal.SearchQuery{}, false)
 		// Then
 		assert.Nil(t, err)
 		assert.Equal(t, expectedResult, result)
-		assert.Equal(t, 1, rp.Spy.FindAll)
+		rp.AssertCalls("FindAll")
 	})
 
 	t.Run("Find with query", func(t *testing.T) {
 		// Given
-		rp := repository.NewVehicleMapMock()
+		rp := repositorytest.NewVehicleMapMock(t)
 		rp.FindByWeightRangeFunc = func(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error) {
 			return map[int]internal.Vehicle{1: {
 				Id: 1,
@@ -376,11 +378,356 @@ func TestServiceVehicleDefault_SearchByWeightRange(t *testing.T) {
 			},
 		}}
 		// When
-		result, err := sv.SearchByWeightRange(internal.SearchQuery{FromWeight: 0, ToWeight: 2}, true)
+		result, err := sv.SearchByWeightRange(context.Background(), internal.SearchQuery{FromWeight: 0, ToWeight: 2}, true)
 		// Then
 		assert.Nil(t, err)
 		assert.Equal(t, expectedResult, result)
-		assert.Equal(t, 0, rp.Spy.FindAll)
-		assert.Equal(t, 1, rp.Spy.FindByWeightRange)
+		rp.AssertCalls("FindByWeightRange")
+		assert.Equal(t, []repositorytest.VehicleMapMockFindByWeightRangeCall{{FromWeight: 0, ToWeight: 2}}, rp.FindByWeightRangeCalls())
+	})
+}
+
+// validAttributes is a helper that returns attributes of a vehicle that pass the validation
+func validAttributes() internal.VehicleAttributes {
+	return internal.VehicleAttributes{
+		Brand:           "A",
+		Model:           "B",
+		Registration:    "AB-123",
+		Color:           "D",
+		FabricationYear: 2000,
+		Capacity:        4,
+		MaxSpeed:        180,
+		FuelType:        "gasoline",
+		Transmission:    "manual",
+		Weight:          1200,
+	}
+}
+
+func TestServiceVehicleDefault_Save(t *testing.T) {
+	// Given
+	rp := repositorytest.NewVehicleMapMock(t)
+	rp.SaveFunc = func(v *internal.Vehicle) (err error) {
+		v.Id = 1
+		return nil
+	}
+	sv := service.NewServiceVehicleDefault(rp)
+
+	vehicle := internal.Vehicle{
+		VehicleAttributes: validAttributes(),
+	}
+	expectedResult := internal.Vehicle{
+		Id:                1,
+		VehicleAttributes: validAttributes(),
+	}
+	// When
+	err := sv.Save(context.Background(), &vehicle)
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResult, vehicle)
+	assert.Len(t, rp.SaveCalls(), 1)
+}
+
+func TestServiceVehicleDefault_Update(t *testing.T) {
+	t.Run("Update a vehicle", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.UpdateFunc = func(v internal.Vehicle) (err error) {
+			return nil
+		}
+		sv := service.NewServiceVehicleDefault(rp)
+
+		// When
+		err := sv.Update(context.Background(), internal.Vehicle{Id: 1, VehicleAttributes: validAttributes()})
+		// Then
+		assert.Nil(t, err)
+		assert.Len(t, rp.UpdateCalls(), 1)
+	})
+
+	t.Run("Vehicle not found", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.UpdateFunc = func(v internal.Vehicle) (err error) {
+			return internal.ErrRepositoryVehicleNotFound
+		}
+		sv := service.NewServiceVehicleDefault(rp)
+
+		expectedError := internal.ErrRepositoryVehicleNotFound
+		// When
+		err := sv.Update(context.Background(), internal.Vehicle{Id: 1, VehicleAttributes: validAttributes()})
+		// Then
+		assert.ErrorIs(t, err, expectedError)
+		assert.Len(t, rp.UpdateCalls(), 1)
+	})
+}
+
+func TestServiceVehicleDefault_Patch(t *testing.T) {
+	// Given
+	rp := repositorytest.NewVehicleMapMock(t)
+	rp.PatchFunc = func(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+		v = internal.Vehicle{Id: id}
+		patch.Apply(&v.VehicleAttributes)
+		return
+	}
+	sv := service.NewServiceVehicleDefault(rp)
+
+	brand := "A"
+	expectedResult := internal.Vehicle{
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand: "A",
+		},
+	}
+	// When
+	result, err := sv.Patch(context.Background(), 1, internal.VehiclePatch{Brand: &brand})
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResult, result)
+	assert.Equal(t, []repositorytest.VehicleMapMockPatchCall{{Id: 1, Patch: internal.VehiclePatch{Brand: &brand}}}, rp.PatchCalls())
+}
+
+func TestServiceVehicleDefault_Delete(t *testing.T) {
+	// Given
+	rp := repositorytest.NewVehicleMapMock(t)
+	rp.DeleteFunc = func(id int) (err error) {
+		return nil
+	}
+	sv := service.NewServiceVehicleDefault(rp)
+
+	// When
+	err := sv.Delete(context.Background(), 1)
+	// Then
+	assert.Nil(t, err)
+	assert.Len(t, rp.DeleteCalls(), 1)
+}
+
+func TestServiceVehicleDefault_Search(t *testing.T) {
+	t.Run("Search with a valid filter", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.FindByFilterFunc = func(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+			return map[int]internal.Vehicle{1: {Id: 1}}, nil
+		}
+		sv := service.NewServiceVehicleDefault(rp)
+
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{"A"}},
+		}}
+		expectedResult := map[int]internal.Vehicle{1: {Id: 1}}
+		// When
+		result, err := sv.Search(context.Background(), filter)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+		assert.Len(t, rp.FindByFilterCalls(), 1)
+	})
+
+	t.Run("Search with an invalid filter", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		sv := service.NewServiceVehicleDefault(rp)
+
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorGte, Strings: []string{"A"}},
+		}}
+		// When
+		_, err := sv.Search(context.Background(), filter)
+		// Then
+		assert.ErrorIs(t, err, internal.ErrServiceInvalidSearch)
+		assert.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+		rp.AssertCalls()
+	})
+}
+
+func TestServiceVehicleDefault_Stats(t *testing.T) {
+	t.Run("Stats of the vehicles that match the filter", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.FindByFilterFunc = func(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+			return map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "A", MaxSpeed: 1}},
+				2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "A", MaxSpeed: 5}},
+			}, nil
+		}
+		sv := service.NewServiceVehicleDefault(rp)
+
+		expectedResult := []internal.VehicleStatsRow{{
+			Group:   map[internal.VehicleField]any{"brand": "A"},
+			Metrics: map[string]float64{"avg:max_speed": 3},
+		}}
+		// When
+		result, err := sv.Stats(context.Background(),
+			internal.VehicleFilter{},
+			[]internal.VehicleField{internal.VehicleFieldBrand},
+			[]internal.VehicleMetric{{Aggregate: internal.VehicleAggregateAvg, Field: internal.VehicleFieldMaxSpeed}},
+		)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+		assert.Len(t, rp.FindByFilterCalls(), 1)
+	})
+
+	t.Run("Invalid metric", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.FindByFilterFunc = func(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+			return map[int]internal.Vehicle{}, nil
+		}
+		sv := service.NewServiceVehicleDefault(rp)
+
+		// When
+		_, err := sv.Stats(context.Background(),
+			internal.VehicleFilter{},
+			nil,
+			[]internal.VehicleMetric{{Aggregate: internal.VehicleAggregateAvg, Field: internal.VehicleFieldBrand}},
+		)
+		// Then
+		assert.ErrorIs(t, err, internal.ErrServiceInvalidSearch)
+		assert.ErrorIs(t, err, internal.ErrVehicleMetricInvalid)
+	})
+}
+
+func TestServiceVehicleDefault_Validation(t *testing.T) {
+	t.Run("Save a vehicle with invalid attributes", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		sv := service.NewServiceVehicleDefault(rp)
+
+		v := internal.Vehicle{VehicleAttributes: validAttributes()}
+		v.Registration = "0"
+		v.FuelType = "steam"
+		v.Weight = -1
+
+		expectedFields := []internal.FieldError{
+			{Field: "registration", Message: "must have 2 to 10 letters, digits or inner hyphens, not only zeros"},
+			{Field: "fuel_type", Message: "must be one of gas, gasoline, diesel, biodiesel, electric, hybrid"},
+			{Field: "weight", Message: "must not be negative"},
+		}
+		// When
+		err := sv.Save(context.Background(), &v)
+		// Then
+		var ve *internal.ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.ErrorIs(t, err, internal.ErrVehicleInvalid)
+		assert.ErrorIs(t, err, internal.ErrValidation)
+		assert.Equal(t, expectedFields, ve.Fields)
+		rp.AssertCalls()
+	})
+
+	t.Run("Patch with invalid attributes", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		sv := service.NewServiceVehicleDefault(rp)
+
+		brand, capacity := " ", 0
+		expectedFields := []internal.FieldError{
+			{Field: "brand", Message: "is required"},
+			{Field: "passengers", Message: "must be between 1 and 100"},
+		}
+		// When
+		_, err := sv.Patch(context.Background(), 1, internal.VehiclePatch{Brand: &brand, Capacity: &capacity})
+		// Then
+		var ve *internal.ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.Equal(t, expectedFields, ve.Fields)
+		rp.AssertCalls()
+	})
+
+	t.Run("Find by an inverted range of years", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		sv := service.NewServiceVehicleDefault(rp)
+
+		expectedFields := []internal.FieldError{
+			{Field: "end_year", Message: "must not be before start_year"},
+		}
+		// When
+		_, err := sv.FindByBrandAndYearRange(context.Background(), "A", 2010, 2000)
+		// Then
+		var ve *internal.ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.ErrorIs(t, err, internal.ErrServiceInvalidFind)
+		assert.Equal(t, expectedFields, ve.Fields)
+		rp.AssertCalls()
+	})
+
+	t.Run("Search by a negative weight range", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		sv := service.NewServiceVehicleDefault(rp)
+
+		expectedFields := []internal.FieldError{
+			{Field: "weight_min", Message: "must not be negative"},
+		}
+		// When
+		_, err := sv.SearchByWeightRange(context.Background(), internal.SearchQuery{FromWeight: -5, ToWeight: 10}, true)
+		// Then
+		var ve *internal.ValidationError
+		require.ErrorAs(t, err, &ve)
+		assert.ErrorIs(t, err, internal.ErrServiceInvalidSearch)
+		assert.Equal(t, expectedFields, ve.Fields)
+		rp.AssertCalls()
+	})
+}
+
+func TestServiceVehicleDefault_FindById(t *testing.T) {
+	t.Run("Find a vehicle", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.FindByIdFunc = func(id int) (v internal.Vehicle, err error) {
+			return internal.Vehicle{Id: id}, nil
+		}
+		sv := service.NewServiceVehicleDefault(rp)
+
+		expectedResult := internal.Vehicle{Id: 1}
+		// When
+		result, err := sv.FindById(context.Background(), 1)
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+		assert.Len(t, rp.FindByIdCalls(), 1)
+	})
+
+	t.Run("Invalid id", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		sv := service.NewServiceVehicleDefault(rp)
+
+		// When
+		_, err := sv.FindById(context.Background(), 0)
+		// Then
+		assert.ErrorIs(t, err, internal.ErrServiceInvalidFind)
+		assert.EqualError(t, err, "service: invalid find: id must be positive")
+		rp.AssertCalls()
+	})
+}
+
+func TestServiceVehicleDefault_FindByRegistration(t *testing.T) {
+	t.Run("Find a vehicle", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		rp.FindByRegistrationFunc = func(registration string) (v internal.Vehicle, err error) {
+			return internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "AB-123"}}, nil
+		}
+		sv := service.NewServiceVehicleDefault(rp)
+
+		expectedResult := internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "AB-123"}}
+		// When
+		result, err := sv.FindByRegistration(context.Background(), "ab-123")
+		// Then
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResult, result)
+		assert.Equal(t, []repositorytest.VehicleMapMockFindByRegistrationCall{{Registration: "ab-123"}}, rp.FindByRegistrationCalls())
+	})
+
+	t.Run("Blank registration", func(t *testing.T) {
+		// Given
+		rp := repositorytest.NewVehicleMapMock(t)
+		sv := service.NewServiceVehicleDefault(rp)
+
+		// When
+		_, err := sv.FindByRegistration(context.Background(), "  ")
+		// Then
+		assert.ErrorIs(t, err, internal.ErrServiceInvalidFind)
+		rp.AssertCalls()
 	})
 }
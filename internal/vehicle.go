@@ -1,5 +1,7 @@
 package internal
 
+import "strings"
+
 // Dimensions is a struct that represents a dimension in 3d
 type Dimensions struct {
 	// Height is the height of the dimension
@@ -44,3 +46,90 @@ type Vehicle struct {
 	// VehicleAttribue is the attributes of a vehicle
 	VehicleAttributes
 }
+
+// DimensionsPatch is a struct that represents a partial update of a dimension
+// - nil fields are not updated
+type DimensionsPatch struct {
+	// Height is the height of the dimension
+	Height *float64
+	// Length is the length of the dimension
+	Length *float64
+	// Width is the width of the dimension
+	Width *float64
+}
+
+// VehiclePatch is a struct that represents a partial update of the attributes of a vehicle
+// - nil fields are not updated
+type VehiclePatch struct {
+	// Brand is the brand of the vehicle
+	Brand *string
+	// Model is the model of the vehicle
+	Model *string
+	// Registration is the registration of the vehicle
+	Registration *string
+	// Color is the color of the vehicle
+	Color *string
+	// FabricationYear is the fabrication year of the vehicle
+	FabricationYear *int
+	// Capacity is the capacity of people of the vehicle
+	Capacity *int
+	// MaxSpeed is the maximum speed of the vehicle
+	MaxSpeed *float64
+	// FuelType is the fuel type of the vehicle
+	FuelType *string
+	// Transmission is the transmission of the vehicle
+	Transmission *string
+	// Weight is the weight of the vehicle
+	Weight *float64
+	// Dimensions is the dimensions of the vehicle
+	DimensionsPatch
+}
+
+// Apply is a method that updates the attributes of a vehicle with the set fields of the patch
+func (p VehiclePatch) Apply(v *VehicleAttributes) {
+	if p.Brand != nil {
+		v.Brand = *p.Brand
+	}
+	if p.Model != nil {
+		v.Model = *p.Model
+	}
+	if p.Registration != nil {
+		v.Registration = *p.Registration
+	}
+	if p.Color != nil {
+		v.Color = *p.Color
+	}
+	if p.FabricationYear != nil {
+		v.FabricationYear = *p.FabricationYear
+	}
+	if p.Capacity != nil {
+		v.Capacity = *p.Capacity
+	}
+	if p.MaxSpeed != nil {
+		v.MaxSpeed = *p.MaxSpeed
+	}
+	if p.FuelType != nil {
+		v.FuelType = *p.FuelType
+	}
+	if p.Transmission != nil {
+		v.Transmission = *p.Transmission
+	}
+	if p.Weight != nil {
+		v.Weight = *p.Weight
+	}
+	if p.Height != nil {
+		v.Height = *p.Height
+	}
+	if p.Length != nil {
+		v.Length = *p.Length
+	}
+	if p.Width != nil {
+		v.Width = *p.Width
+	}
+}
+
+// NormalizeRegistration is a function that returns the registration used to compare registrations
+// - letters are upper case and whitespace is removed, so "ab 12" and "AB12" are the same registration
+func NormalizeRegistration(registration string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(registration), ""))
+}
@@ -0,0 +1,244 @@
+package internal
+
+import (
+	"fmt"
+)
+
+var (
+	// ErrVehicleFilterInvalid is an error that represents an invalid filter
+	ErrVehicleFilterInvalid = NewError(ErrValidation, "filter: invalid filter")
+)
+
+// VehicleField is the name of a field of a vehicle, the same used by the JSON format
+type VehicleField string
+
+const (
+	VehicleFieldId              VehicleField = "id"
+	VehicleFieldBrand           VehicleField = "brand"
+	VehicleFieldModel           VehicleField = "model"
+	VehicleFieldRegistration    VehicleField = "registration"
+	VehicleFieldColor           VehicleField = "color"
+	VehicleFieldFabricationYear VehicleField = "year"
+	VehicleFieldCapacity        VehicleField = "passengers"
+	VehicleFieldMaxSpeed        VehicleField = "max_speed"
+	VehicleFieldFuelType        VehicleField = "fuel_type"
+	VehicleFieldTransmission    VehicleField = "transmission"
+	VehicleFieldWeight          VehicleField = "weight"
+	VehicleFieldHeight          VehicleField = "height"
+	VehicleFieldLength          VehicleField = "length"
+	VehicleFieldWidth           VehicleField = "width"
+)
+
+// VehicleFields is the list of all the fields of a vehicle
+var VehicleFields = []VehicleField{
+	VehicleFieldId, VehicleFieldBrand, VehicleFieldModel, VehicleFieldRegistration, VehicleFieldColor,
+	VehicleFieldFabricationYear, VehicleFieldCapacity, VehicleFieldMaxSpeed, VehicleFieldFuelType,
+	VehicleFieldTransmission, VehicleFieldWeight, VehicleFieldHeight, VehicleFieldLength, VehicleFieldWidth,
+}
+
+// vehicleFieldAliases are alternative names of the fields, the names of the attributes of the vehicle
+var vehicleFieldAliases = map[string]VehicleField{
+	"fabrication_year": VehicleFieldFabricationYear,
+	"capacity":         VehicleFieldCapacity,
+}
+
+// ParseVehicleField is a function that returns the field with the name, or one of its aliases
+func ParseVehicleField(name string) (f VehicleField, ok bool) {
+	f = VehicleField(name)
+	if f.Valid() {
+		ok = true
+		return
+	}
+	f, ok = vehicleFieldAliases[name]
+	return
+}
+
+// Valid is a method that returns if the field exists
+func (f VehicleField) Valid() bool {
+	for _, field := range VehicleFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Numeric is a method that returns if the field holds a number, otherwise it holds a string
+func (f VehicleField) Numeric() bool {
+	switch f {
+	case VehicleFieldBrand, VehicleFieldModel, VehicleFieldRegistration, VehicleFieldColor, VehicleFieldFuelType, VehicleFieldTransmission:
+		return false
+	}
+	return true
+}
+
+// String is a method that returns the value of a string field of the vehicle
+func (v Vehicle) String(f VehicleField) (s string) {
+	switch f {
+	case VehicleFieldBrand:
+		s = v.Brand
+	case VehicleFieldModel:
+		s = v.Model
+	case VehicleFieldRegistration:
+		s = v.Registration
+	case VehicleFieldColor:
+		s = v.Color
+	case VehicleFieldFuelType:
+		s = v.FuelType
+	case VehicleFieldTransmission:
+		s = v.Transmission
+	}
+	return
+}
+
+// Number is a method that returns the value of a numeric field of the vehicle
+func (v Vehicle) Number(f VehicleField) (n float64) {
+	switch f {
+	case VehicleFieldId:
+		n = float64(v.Id)
+	case VehicleFieldFabricationYear:
+		n = float64(v.FabricationYear)
+	case VehicleFieldCapacity:
+		n = float64(v.Capacity)
+	case VehicleFieldMaxSpeed:
+		n = v.MaxSpeed
+	case VehicleFieldWeight:
+		n = v.Weight
+	case VehicleFieldHeight:
+		n = v.Height
+	case VehicleFieldLength:
+		n = v.Length
+	case VehicleFieldWidth:
+		n = v.Width
+	}
+	return
+}
+
+// FilterOperator is the comparison made by a condition of a filter
+type FilterOperator string
+
+const (
+	// FilterOperatorEq matches values equal to the value of the condition
+	FilterOperatorEq FilterOperator = "eq"
+	// FilterOperatorGt matches values greater than the value of the condition (numeric fields)
+	FilterOperatorGt FilterOperator = "gt"
+	// FilterOperatorGte matches values greater than or equal to the value of the condition (numeric fields)
+	FilterOperatorGte FilterOperator = "gte"
+	// FilterOperatorLt matches values lower than the value of the condition (numeric fields)
+	FilterOperatorLt FilterOperator = "lt"
+	// FilterOperatorLte matches values lower than or equal to the value of the condition (numeric fields)
+	FilterOperatorLte FilterOperator = "lte"
+	// FilterOperatorIn matches values equal to any of the values of the condition
+	FilterOperatorIn FilterOperator = "in"
+)
+
+// VehicleCondition is a struct that represents a condition over a field of a vehicle
+// - values: Strings is used by string fields and Numbers by numeric fields.
+// All the operators take one value except FilterOperatorIn, that takes one or more
+type VehicleCondition struct {
+	// Field is the field compared
+	Field VehicleField
+	// Operator is the comparison made
+	Operator FilterOperator
+	// Strings are the values compared with a string field
+	Strings []string
+	// Numbers are the values compared with a numeric field
+	Numbers []float64
+}
+
+// Validate is a method that returns an error if the condition can not be evaluated
+func (c VehicleCondition) Validate() (err error) {
+	if !c.Field.Valid() {
+		err = fmt.Errorf("%w: unknown field %q", ErrVehicleFilterInvalid, c.Field)
+		return
+	}
+
+	count := len(c.Strings)
+	if c.Field.Numeric() {
+		count = len(c.Numbers)
+	}
+
+	switch c.Operator {
+	case FilterOperatorEq:
+	case FilterOperatorGt, FilterOperatorGte, FilterOperatorLt, FilterOperatorLte:
+		if !c.Field.Numeric() {
+			err = fmt.Errorf("%w: operator %q requires a numeric field, %q is not", ErrVehicleFilterInvalid, c.Operator, c.Field)
+			return
+		}
+	case FilterOperatorIn:
+		if count == 0 {
+			err = fmt.Errorf("%w: operator %q on %q requires at least one value", ErrVehicleFilterInvalid, c.Operator, c.Field)
+		}
+		return
+	default:
+		err = fmt.Errorf("%w: unknown operator %q", ErrVehicleFilterInvalid, c.Operator)
+		return
+	}
+
+	if count != 1 {
+		err = fmt.Errorf("%w: operator %q on %q requires one value", ErrVehicleFilterInvalid, c.Operator, c.Field)
+	}
+	return
+}
+
+// Match is a method that returns if the vehicle meets the condition
+func (c VehicleCondition) Match(v Vehicle) bool {
+	// string fields
+	if !c.Field.Numeric() {
+		s := v.String(c.Field)
+		for _, value := range c.Strings {
+			if s == value {
+				return true
+			}
+		}
+		return false
+	}
+
+	// numeric fields
+	n := v.Number(c.Field)
+	switch c.Operator {
+	case FilterOperatorGt:
+		return n > c.Numbers[0]
+	case FilterOperatorGte:
+		return n >= c.Numbers[0]
+	case FilterOperatorLt:
+		return n < c.Numbers[0]
+	case FilterOperatorLte:
+		return n <= c.Numbers[0]
+	}
+	for _, value := range c.Numbers {
+		if n == value {
+			return true
+		}
+	}
+	return false
+}
+
+// VehicleFilter is a struct that represents a filter of vehicles
+// - method: dynamic. A vehicle matches the filter when it meets all the conditions, an empty filter matches all the vehicles
+type VehicleFilter struct {
+	// Conditions are the conditions that a vehicle must meet
+	Conditions []VehicleCondition
+}
+
+// Validate is a method that returns an error if any condition of the filter can not be evaluated
+func (f VehicleFilter) Validate() (err error) {
+	for _, c := range f.Conditions {
+		err = c.Validate()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Match is a method that returns if the vehicle meets all the conditions of the filter
+// - the filter must be valid
+func (f VehicleFilter) Match(v Vehicle) bool {
+	for _, c := range f.Conditions {
+		if !c.Match(v) {
+			return false
+		}
+	}
+	return true
+}
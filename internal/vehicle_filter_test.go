@@ -0,0 +1,105 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVehicleFilter_Match(t *testing.T) {
+	// Given
+	vehicle := internal.Vehicle{
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           "A",
+			FabricationYear: 2008,
+			FuelType:        "diesel",
+			Dimensions: internal.Dimensions{
+				Height: 1.5,
+			},
+		},
+	}
+
+	t.Run("Empty filter matches all the vehicles", func(t *testing.T) {
+		// When
+		result := internal.VehicleFilter{}.Match(vehicle)
+		// Then
+		require.True(t, result)
+	})
+
+	t.Run("All the conditions must match", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{"A"}},
+			{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorGte, Numbers: []float64{2008}},
+			{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorLt, Numbers: []float64{2010}},
+			{Field: internal.VehicleFieldFuelType, Operator: internal.FilterOperatorIn, Strings: []string{"gas", "diesel"}},
+			{Field: internal.VehicleFieldHeight, Operator: internal.FilterOperatorIn, Numbers: []float64{1, 1.5}},
+		}}
+		// When
+		result := filter.Match(vehicle)
+		// Then
+		require.NoError(t, filter.Validate())
+		require.True(t, result)
+	})
+
+	t.Run("One condition does not match", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorEq, Strings: []string{"A"}},
+			{Field: internal.VehicleFieldFabricationYear, Operator: internal.FilterOperatorGt, Numbers: []float64{2008}},
+		}}
+		// When
+		result := filter.Match(vehicle)
+		// Then
+		require.False(t, result)
+	})
+}
+
+func TestVehicleFilter_Validate(t *testing.T) {
+	t.Run("Unknown field", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: "wheels", Operator: internal.FilterOperatorEq, Numbers: []float64{4}},
+		}}
+		// When
+		err := filter.Validate()
+		// Then
+		require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+		require.EqualError(t, err, `filter: invalid filter: unknown field "wheels"`)
+	})
+
+	t.Run("Range over a string field", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldBrand, Operator: internal.FilterOperatorGte, Strings: []string{"A"}},
+		}}
+		// When
+		err := filter.Validate()
+		// Then
+		require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+	})
+
+	t.Run("Equality without value", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldWeight, Operator: internal.FilterOperatorEq},
+		}}
+		// When
+		err := filter.Validate()
+		// Then
+		require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+	})
+
+	t.Run("Unknown operator", func(t *testing.T) {
+		// Given
+		filter := internal.VehicleFilter{Conditions: []internal.VehicleCondition{
+			{Field: internal.VehicleFieldWeight, Operator: "like", Numbers: []float64{1}},
+		}}
+		// When
+		err := filter.Validate()
+		// Then
+		require.ErrorIs(t, err, internal.ErrVehicleFilterInvalid)
+	})
+}
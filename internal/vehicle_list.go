@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrVehicleSortInvalid is an error that represents an invalid sort
+	ErrVehicleSortInvalid = NewError(ErrValidation, "sort: invalid sort")
+)
+
+// VehicleSort is a struct that represents the order of vehicles by a field
+type VehicleSort struct {
+	// Field is the field compared
+	Field VehicleField
+	// Descending is a flag that reverses the order, ascending by default
+	Descending bool
+}
+
+// VehiclePage is a struct that represents a page of an ordered list of vehicles
+type VehiclePage struct {
+	// Offset is the number of vehicles skipped
+	Offset int
+	// Limit is the maximum number of vehicles in the page, 0 means no limit
+	Limit int
+}
+
+// SortVehicles is a function that returns the vehicles ordered by the sorts, in order of priority
+// - ties are broken by id, so the order is always the same
+func SortVehicles(v map[int]Vehicle, sorts []VehicleSort) (s []Vehicle, err error) {
+	for _, st := range sorts {
+		if !st.Field.Valid() {
+			err = fmt.Errorf("%w: unknown field %q", ErrVehicleSortInvalid, st.Field)
+			return
+		}
+	}
+
+	s = make([]Vehicle, 0, len(v))
+	for _, vh := range v {
+		s = append(s, vh)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		for _, st := range sorts {
+			c := compareVehicles(s[i], s[j], st.Field)
+			if c == 0 {
+				continue
+			}
+			if st.Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return s[i].Id < s[j].Id
+	})
+	return
+}
+
+// PaginateVehicles is a function that returns the page of the ordered list of vehicles
+func PaginateVehicles(v []Vehicle, page VehiclePage) (p []Vehicle) {
+	if page.Offset >= len(v) {
+		p = []Vehicle{}
+		return
+	}
+	if page.Offset > 0 {
+		v = v[page.Offset:]
+	}
+	if page.Limit > 0 && page.Limit < len(v) {
+		v = v[:page.Limit]
+	}
+	p = v
+	return
+}
+
+// compareVehicles is a function that compares the field of two vehicles, returning -1, 0 or +1
+func compareVehicles(a Vehicle, b Vehicle, f VehicleField) int {
+	if f.Numeric() {
+		na, nb := a.Number(f), b.Number(f)
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	}
+
+	sa, sb := a.String(f), b.String(f)
+	switch {
+	case sa < sb:
+		return -1
+	case sa > sb:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortVehicles(t *testing.T) {
+	// Given
+	v := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "B", FabricationYear: 2000}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "A", FabricationYear: 2000}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "A", FabricationYear: 2010}},
+	}
+
+	t.Run("By id when there are no sorts", func(t *testing.T) {
+		// When
+		result, err := internal.SortVehicles(v, nil)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, []internal.Vehicle{v[1], v[2], v[3]}, result)
+	})
+
+	t.Run("By year descending and brand ascending", func(t *testing.T) {
+		// Given
+		sorts := []internal.VehicleSort{
+			{Field: internal.VehicleFieldFabricationYear, Descending: true},
+			{Field: internal.VehicleFieldBrand},
+		}
+		// When
+		result, err := internal.SortVehicles(v, sorts)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, []internal.Vehicle{v[3], v[2], v[1]}, result)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		// Given
+		sorts := []internal.VehicleSort{{Field: "wheels"}}
+		// When
+		_, err := internal.SortVehicles(v, sorts)
+		// Then
+		require.ErrorIs(t, err, internal.ErrVehicleSortInvalid)
+	})
+}
+
+func TestPaginateVehicles(t *testing.T) {
+	// Given
+	v := []internal.Vehicle{{Id: 1}, {Id: 2}, {Id: 3}}
+
+	t.Run("First page", func(t *testing.T) {
+		// When
+		result := internal.PaginateVehicles(v, internal.VehiclePage{Limit: 2})
+		// Then
+		require.Equal(t, []internal.Vehicle{{Id: 1}, {Id: 2}}, result)
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		// When
+		result := internal.PaginateVehicles(v, internal.VehiclePage{Offset: 2, Limit: 2})
+		// Then
+		require.Equal(t, []internal.Vehicle{{Id: 3}}, result)
+	})
+
+	t.Run("Offset out of range", func(t *testing.T) {
+		// When
+		result := internal.PaginateVehicles(v, internal.VehiclePage{Offset: 5, Limit: 2})
+		// Then
+		require.Equal(t, []internal.Vehicle{}, result)
+	})
+}
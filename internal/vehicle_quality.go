@@ -0,0 +1,66 @@
+package internal
+
+// QualityIssueKind is the kind of a data-quality issue found in the records of the vehicles
+type QualityIssueKind string
+
+const (
+	// QualityIssueDuplicateId is a record with the id of a previous record, the later one wins when loaded
+	QualityIssueDuplicateId QualityIssueKind = "duplicate_id"
+	// QualityIssueDuplicateRegistration is a record with the registration of a previous record, ignoring case and whitespace
+	QualityIssueDuplicateRegistration QualityIssueKind = "duplicate_registration"
+	// QualityIssueMissingField is a record without a field, or with an empty or zero value
+	QualityIssueMissingField QualityIssueKind = "missing_field"
+	// QualityIssueOutOfRange is a record with a value out of the accepted range or format
+	QualityIssueOutOfRange QualityIssueKind = "out_of_range"
+	// QualityIssueUnknownValue is a record with a value that is not part of an enumeration
+	QualityIssueUnknownValue QualityIssueKind = "unknown_value"
+)
+
+// QualityIssueKinds are the kinds of data-quality issues, in the order they are reported
+var QualityIssueKinds = []QualityIssueKind{
+	QualityIssueDuplicateId,
+	QualityIssueDuplicateRegistration,
+	QualityIssueMissingField,
+	QualityIssueOutOfRange,
+	QualityIssueUnknownValue,
+}
+
+// QualityIssue is a struct that represents a data-quality issue of a record
+type QualityIssue struct {
+	// Record is the position of the record in the file, starting at 1
+	Record int
+	// Id is the id of the vehicle of the record
+	Id int
+	// Field is the name of the field, as in the JSON format
+	Field string
+	// Kind is the kind of the issue
+	Kind QualityIssueKind
+	// Message is the description of the issue
+	Message string
+}
+
+// QualityReport is a struct that represents the data-quality issues of the records of the vehicles
+type QualityReport struct {
+	// Records is the number of records checked
+	Records int
+	// Issues are the issues found, in the order of the records
+	Issues []QualityIssue
+}
+
+// Counts is a method that returns the number of issues of each kind, with every kind present
+func (r QualityReport) Counts() (c map[QualityIssueKind]int) {
+	c = make(map[QualityIssueKind]int, len(QualityIssueKinds))
+	for _, k := range QualityIssueKinds {
+		c[k] = 0
+	}
+	for _, is := range r.Issues {
+		c[is.Kind]++
+	}
+	return
+}
+
+// QualityCheckerVehicle is an interface that represents the checker of the data quality of the vehicles
+type QualityCheckerVehicle interface {
+	// Check is a method that reads the records of the vehicles and reports their issues
+	Check() (r QualityReport, err error)
+}
@@ -0,0 +1,27 @@
+package internal
+
+import "time"
+
+// ReloadStatus is a struct that represents the outcome of the reloads of the vehicles
+type ReloadStatus struct {
+	// LastReload is the time of the last successful reload
+	LastReload time.Time
+	// LastAttempt is the time of the last reload, successful or not
+	LastAttempt time.Time
+	// LastError is the error of the last reload, nil if it succeeded
+	LastError error
+	// Records is the number of vehicles of the file, as loaded in the last successful reload or written by the last store
+	Records int
+	// Checksum is the checksum of the file, as loaded in the last successful reload or written by the last store, as "sha256:<hex>"
+	Checksum string
+	// Succeeded is the number of successful reloads
+	Succeeded int
+	// Failed is the number of failed reloads
+	Failed int
+}
+
+// ReloaderVehicle is an interface that represents the reloader of the vehicles
+type ReloaderVehicle interface {
+	// Status is a method that returns the outcome of the reloads
+	Status() (s ReloadStatus)
+}
@@ -1,10 +1,20 @@
 package internal
 
-import "errors"
-
 var (
 	// ErrRepositoryInvalidFind is an error that represents an invalid find
-	ErrRepositoryInvalidFind = errors.New("repository: invalid find")
+	ErrRepositoryInvalidFind = NewError(ErrValidation, "repository: invalid find")
+	// ErrRepositoryVehicleNotFound is an error that represents a vehicle that does not exist
+	ErrRepositoryVehicleNotFound = NewError(ErrNotFound, "repository: vehicle not found")
+	// ErrRepositoryRegistrationAmbiguous is an error that represents a registration shared by several vehicles
+	ErrRepositoryRegistrationAmbiguous = NewError(ErrConflict, "repository: registration matches several vehicles")
+	// ErrRepositoryRegistrationTaken is an error that represents a registration already used by another vehicle
+	ErrRepositoryRegistrationTaken = NewError(ErrConflict, "repository: registration used by another vehicle")
+	// ErrRepositoryVehicleStore is an error that represents vehicles that could not be stored, the change that caused it is rolled back
+	ErrRepositoryVehicleStore = NewError(ErrUnavailable, "repository: vehicles could not be stored")
+	// ErrRepositoryVehiclePending is an error that represents vehicles that can not be replaced since they have changes not stored yet
+	ErrRepositoryVehiclePending = NewError(ErrConflict, "repository: vehicles have changes not stored yet")
+	// ErrRepositoryDatabase is an error that represents a database that could not be read or written
+	ErrRepositoryDatabase = NewError(ErrUnavailable, "repository: database failed")
 )
 
 // RepositoryReadVehicle is an interface that represents a vehicle repository
@@ -13,6 +23,20 @@ type RepositoryReadVehicle interface {
 	// FindAll is a method that returns a map of all vehicles
 	FindAll() (v map[int]Vehicle, err error)
 
+	// Count is a method that returns the number of vehicles, without reading them
+	Count() (n int, err error)
+
+	// CountByBrand is a method that returns the number of vehicles of each brand, without reading them
+	CountByBrand() (n map[string]int, err error)
+
+	// FindById is a method that returns the vehicle with the id
+	// - a missing vehicle returns ErrRepositoryVehicleNotFound
+	FindById(id int) (v Vehicle, err error)
+
+	// FindByRegistration is a method that returns the vehicle with the registration, compared by NormalizeRegistration
+	// - a missing vehicle returns ErrRepositoryVehicleNotFound, several vehicles ErrRepositoryRegistrationAmbiguous
+	FindByRegistration(registration string) (v Vehicle, err error)
+
 	// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
 	FindByColorAndYear(color string, fabricationYear int) (v map[int]Vehicle, err error)
 
@@ -24,4 +48,44 @@ type RepositoryReadVehicle interface {
 
 	// FindByWeightRange is a method that returns a map of vehicles that match the weight range
 	FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]Vehicle, err error)
+
+	// FindByFilter is a method that returns a map of vehicles that match the filter
+	// - method: dynamic. The filter must be valid
+	FindByFilter(filter VehicleFilter) (v map[int]Vehicle, err error)
+}
+
+// RepositoryWriteVehicle is an interface that represents a vehicle repository that can be modified
+type RepositoryWriteVehicle interface {
+	// Save is a method that saves a new vehicle and sets its id
+	// - a registration used by another vehicle, compared by NormalizeRegistration, returns ErrRepositoryRegistrationTaken
+	Save(v *Vehicle) (err error)
+
+	// Update is a method that replaces all the attributes of an existing vehicle
+	// - a registration used by another vehicle returns ErrRepositoryRegistrationTaken
+	Update(v Vehicle) (err error)
+
+	// Patch is a method that updates only the set attributes of an existing vehicle
+	// - a registration used by another vehicle returns ErrRepositoryRegistrationTaken
+	Patch(id int, patch VehiclePatch) (v Vehicle, err error)
+
+	// Delete is a method that deletes an existing vehicle
+	Delete(id int) (err error)
+
+	// Replace is a method that replaces all the vehicles at once
+	// - the registrations are not checked, vehicles loaded from a file may share them
+	Replace(v map[int]Vehicle) (err error)
+}
+
+// RepositoryVehicle is an interface that represents a vehicle repository that can be read and modified
+type RepositoryVehicle interface {
+	RepositoryReadVehicle
+	RepositoryWriteVehicle
+}
+
+
+// RepositoryVersionVehicle is an interface that represents a vehicle repository that tells when its vehicles change
+type RepositoryVersionVehicle interface {
+	// Version is a method that returns the version of the vehicles, increased by every change
+	// - the same version always holds the same vehicles, so it can be used to validate what was built from them
+	Version() (v uint64)
 }
\ No newline at end of file
@@ -1,14 +1,14 @@
 package internal
 
-import "errors"
+import "context"
 
 var (
 	// ErrServiceInvalidFind is an error that represents an invalid find
-	ErrServiceInvalidFind = errors.New("service: invalid find")
+	ErrServiceInvalidFind = NewError(ErrValidation, "service: invalid find")
 	// ErrServiceInvalidSearch is an error that represents an invalid search
-	ErrServiceInvalidSearch = errors.New("service: invalid search")
+	ErrServiceInvalidSearch = NewError(ErrValidation, "service: invalid search")
 	// ErrServiceNoVehicles is an error that represents no vehicles
-	ErrServiceNoVehicles = errors.New("service: no vehicles")
+	ErrServiceNoVehicles = NewError(ErrNotFound, "service: no vehicles")
 )
 
 // SearchQuery is a struct that represents a search query
@@ -21,22 +21,49 @@ type SearchQuery struct {
 
 // ServiceVehicle is an interface that represents a vehicle service
 type ServiceVehicle interface {
+	// FindById is a method that returns the vehicle with the id
+	FindById(ctx context.Context, id int) (v Vehicle, err error)
+
+	// FindByRegistration is a method that returns the vehicle with the registration, ignoring case and whitespace
+	FindByRegistration(ctx context.Context, registration string) (v Vehicle, err error)
+
 	// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
-	FindByColorAndYear(color string, fabricationYear int) (v map[int]Vehicle, err error)
+	FindByColorAndYear(ctx context.Context, color string, fabricationYear int) (v map[int]Vehicle, err error)
 
 	// FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
-	FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]Vehicle, err error)
+	FindByBrandAndYearRange(ctx context.Context, brand string, startYear int, endYear int) (v map[int]Vehicle, err error)
 
 	// AverageMaxSpeedByBrand is a method that returns the average speed of the vehicles by brand
-	AverageMaxSpeedByBrand(brand string) (a float64, err error)
+	AverageMaxSpeedByBrand(ctx context.Context, brand string) (a float64, err error)
 
 	// AverageCapacityByBrand is a method that returns the average capacity of the vehicles by brand
-	AverageCapacityByBrand(brand string) (a int, err error)
+	AverageCapacityByBrand(ctx context.Context, brand string) (a int, err error)
 
 	// SearchByWeightRange
 	// - method: hybrid. usage of static procedure and static optional (not dynamic types such as maps or slices)
 	// - query:
 	// 	 !ok -> will return all vehicles
 	// 	 ok  -> will return filtered vehicles
-	SearchByWeightRange(query SearchQuery, ok bool) (v map[int]Vehicle, err error)
+	SearchByWeightRange(ctx context.Context, query SearchQuery, ok bool) (v map[int]Vehicle, err error)
+
+	// Search is a method that returns a map of vehicles that match the filter
+	// - method: dynamic. An invalid filter returns ErrServiceInvalidSearch
+	Search(ctx context.Context, filter VehicleFilter) (v map[int]Vehicle, err error)
+
+	// Stats is a method that returns the metrics of the vehicles that match the filter, grouped by the fields
+	// - method: dynamic. An invalid filter, group by field or metric returns ErrServiceInvalidSearch
+	Stats(ctx context.Context, filter VehicleFilter, groupBy []VehicleField, metrics []VehicleMetric) (rows []VehicleStatsRow, err error)
+
+	// Save is a method that saves a new vehicle and sets its id
+	// - invalid attributes return a ValidationError wrapping ErrVehicleInvalid
+	Save(ctx context.Context, v *Vehicle) (err error)
+
+	// Update is a method that replaces all the attributes of an existing vehicle
+	Update(ctx context.Context, v Vehicle) (err error)
+
+	// Patch is a method that updates only the set attributes of an existing vehicle
+	Patch(ctx context.Context, id int, patch VehiclePatch) (v Vehicle, err error)
+
+	// Delete is a method that deletes an existing vehicle
+	Delete(ctx context.Context, id int) (err error)
 }
\ No newline at end of file
@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrVehicleMetricInvalid is an error that represents an invalid metric
+	ErrVehicleMetricInvalid = NewError(ErrValidation, "stats: invalid metric")
+	// ErrVehicleGroupInvalid is an error that represents an invalid group by field
+	ErrVehicleGroupInvalid = NewError(ErrValidation, "stats: invalid group by")
+)
+
+// VehicleAggregate is the function applied over the values of a field of a group of vehicles
+type VehicleAggregate string
+
+const (
+	// VehicleAggregateCount is the number of vehicles, it takes no field
+	VehicleAggregateCount VehicleAggregate = "count"
+	// VehicleAggregateSum is the sum of the values
+	VehicleAggregateSum VehicleAggregate = "sum"
+	// VehicleAggregateAvg is the average of the values
+	VehicleAggregateAvg VehicleAggregate = "avg"
+	// VehicleAggregateMin is the lowest value
+	VehicleAggregateMin VehicleAggregate = "min"
+	// VehicleAggregateMax is the highest value
+	VehicleAggregateMax VehicleAggregate = "max"
+	// VehicleAggregatePercentile is the value below which the given percentage of values fall (nearest rank)
+	VehicleAggregatePercentile VehicleAggregate = "p"
+)
+
+// VehicleMetric is a struct that represents an aggregate over a numeric field of a group of vehicles
+type VehicleMetric struct {
+	// Aggregate is the function applied
+	Aggregate VehicleAggregate
+	// Percentile is the percentage of VehicleAggregatePercentile, in (0, 100]
+	Percentile float64
+	// Field is the numeric field aggregated, empty for VehicleAggregateCount
+	Field VehicleField
+}
+
+// ParseVehicleMetric is a function that returns the metric described by aggregate:field, e.g. avg:max_speed, p90:weight or count
+func ParseVehicleMetric(s string) (m VehicleMetric, err error) {
+	aggregate, name, _ := strings.Cut(strings.TrimSpace(s), ":")
+
+	// aggregate
+	switch a := VehicleAggregate(aggregate); a {
+	case VehicleAggregateCount, VehicleAggregateSum, VehicleAggregateAvg, VehicleAggregateMin, VehicleAggregateMax:
+		m.Aggregate = a
+	default:
+		p, found := strings.CutPrefix(aggregate, string(VehicleAggregatePercentile))
+		if !found {
+			err = fmt.Errorf("%w: unknown aggregate %q", ErrVehicleMetricInvalid, aggregate)
+			return
+		}
+		m.Aggregate = VehicleAggregatePercentile
+		m.Percentile, err = strconv.ParseFloat(p, 64)
+		if err != nil {
+			err = fmt.Errorf("%w: unknown aggregate %q", ErrVehicleMetricInvalid, aggregate)
+			return
+		}
+	}
+
+	// field
+	if name != "" {
+		var ok bool
+		m.Field, ok = ParseVehicleField(name)
+		if !ok {
+			m.Field = VehicleField(name)
+		}
+	}
+
+	err = m.Validate()
+	return
+}
+
+// String is a method that returns the name of the metric, the format read by ParseVehicleMetric
+func (m VehicleMetric) String() string {
+	aggregate := string(m.Aggregate)
+	if m.Aggregate == VehicleAggregatePercentile {
+		aggregate += strconv.FormatFloat(m.Percentile, 'f', -1, 64)
+	}
+	if m.Field == "" {
+		return aggregate
+	}
+	return aggregate + ":" + string(m.Field)
+}
+
+// Validate is a method that returns an error if the metric can not be computed
+func (m VehicleMetric) Validate() (err error) {
+	switch m.Aggregate {
+	case VehicleAggregateCount:
+		if m.Field != "" {
+			err = fmt.Errorf("%w: %q takes no field", ErrVehicleMetricInvalid, m.Aggregate)
+		}
+		return
+	case VehicleAggregateSum, VehicleAggregateAvg, VehicleAggregateMin, VehicleAggregateMax:
+	case VehicleAggregatePercentile:
+		// written so that NaN, which fails every comparison, is invalid too
+		if !(m.Percentile > 0 && m.Percentile <= 100) {
+			err = fmt.Errorf("%w: percentile must be in (0, 100]", ErrVehicleMetricInvalid)
+			return
+		}
+	default:
+		err = fmt.Errorf("%w: unknown aggregate %q", ErrVehicleMetricInvalid, m.Aggregate)
+		return
+	}
+
+	if !m.Field.Valid() || !m.Field.Numeric() {
+		err = fmt.Errorf("%w: %q requires a numeric field, %q is not", ErrVehicleMetricInvalid, m.Aggregate, m.Field)
+	}
+	return
+}
+
+// Compute is a method that returns the value of the metric over the vehicles
+// - the metric must be valid and the vehicles not empty
+func (m VehicleMetric) Compute(v []Vehicle) (value float64) {
+	if m.Aggregate == VehicleAggregateCount {
+		value = float64(len(v))
+		return
+	}
+
+	values := make([]float64, len(v))
+	for i, vh := range v {
+		values[i] = vh.Number(m.Field)
+	}
+
+	switch m.Aggregate {
+	case VehicleAggregateSum, VehicleAggregateAvg:
+		for _, n := range values {
+			value += n
+		}
+		if m.Aggregate == VehicleAggregateAvg {
+			value /= float64(len(values))
+		}
+	case VehicleAggregateMin:
+		value = values[0]
+		for _, n := range values[1:] {
+			value = math.Min(value, n)
+		}
+	case VehicleAggregateMax:
+		value = values[0]
+		for _, n := range values[1:] {
+			value = math.Max(value, n)
+		}
+	case VehicleAggregatePercentile:
+		sort.Float64s(values)
+		rank := int(math.Ceil(m.Percentile / 100 * float64(len(values))))
+		value = values[max(rank, 1)-1]
+	}
+	return
+}
+
+// VehicleStatsRow is a struct that represents the metrics of a group of vehicles
+type VehicleStatsRow struct {
+	// Group is the value of each group by field shared by the vehicles of the group, string or float64
+	Group map[VehicleField]any
+	// Metrics is the value of each metric by name
+	Metrics map[string]float64
+}
+
+// ComputeVehicleStats is a function that groups the vehicles by the fields and computes the metrics of each group
+// - rows are sorted by the values of the group
+// - without group by fields all the vehicles make a single group, if there are any
+func ComputeVehicleStats(v map[int]Vehicle, groupBy []VehicleField, metrics []VehicleMetric) (rows []VehicleStatsRow, err error) {
+	// check
+	for _, f := range groupBy {
+		if !f.Valid() {
+			err = fmt.Errorf("%w: unknown field %q", ErrVehicleGroupInvalid, f)
+			return
+		}
+	}
+	for _, m := range metrics {
+		if err = m.Validate(); err != nil {
+			return
+		}
+	}
+
+	// group vehicles (sorted for a deterministic order in each group)
+	sorts := make([]VehicleSort, len(groupBy))
+	for i, f := range groupBy {
+		sorts[i] = VehicleSort{Field: f}
+	}
+	sorted, err := SortVehicles(v, sorts)
+	if err != nil {
+		return
+	}
+	var groups [][]Vehicle
+	for i, vh := range sorted {
+		if i == 0 || !sameGroup(sorted[i-1], vh, groupBy) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], vh)
+	}
+
+	// metrics
+	rows = make([]VehicleStatsRow, 0, len(groups))
+	for _, group := range groups {
+		row := VehicleStatsRow{
+			Group:   make(map[VehicleField]any, len(groupBy)),
+			Metrics: make(map[string]float64, len(metrics)),
+		}
+		for _, f := range groupBy {
+			if f.Numeric() {
+				row.Group[f] = group[0].Number(f)
+				continue
+			}
+			row.Group[f] = group[0].String(f)
+		}
+		for _, m := range metrics {
+			row.Metrics[m.String()] = m.Compute(group)
+		}
+		rows = append(rows, row)
+	}
+
+	return
+}
+
+// sameGroup is a function that returns if the vehicles share the values of the group by fields
+func sameGroup(a Vehicle, b Vehicle, groupBy []VehicleField) bool {
+	for _, f := range groupBy {
+		if compareVehicles(a, b, f) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package internal_test
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVehicleMetric(t *testing.T) {
+	t.Run("Valid metrics", func(t *testing.T) {
+		// Given
+		cases := map[string]internal.VehicleMetric{
+			"count":         {Aggregate: internal.VehicleAggregateCount},
+			"avg:max_speed": {Aggregate: internal.VehicleAggregateAvg, Field: internal.VehicleFieldMaxSpeed},
+			"p90:capacity":  {Aggregate: internal.VehicleAggregatePercentile, Percentile: 90, Field: internal.VehicleFieldCapacity},
+		}
+
+		for input, expectedResult := range cases {
+			// When
+			result, err := internal.ParseVehicleMetric(input)
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, expectedResult, result)
+		}
+	})
+
+	t.Run("Invalid metrics", func(t *testing.T) {
+		// Given
+		cases := []string{"median:weight", "avg:brand", "avg", "count:weight", "p0:weight", "p101:weight", "pNaN:capacity", "pInf:capacity", "max:wheels"}
+
+		for _, input := range cases {
+			// When
+			_, err := internal.ParseVehicleMetric(input)
+			// Then
+			require.ErrorIs(t, err, internal.ErrVehicleMetricInvalid, input)
+		}
+	})
+}
+
+func TestComputeVehicleStats(t *testing.T) {
+	// Given
+	v := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "A", FuelType: "gas", Weight: 1, Capacity: 2}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "A", FuelType: "gas", Weight: 3, Capacity: 4}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "A", FuelType: "diesel", Weight: 5, Capacity: 6}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "B", FuelType: "gas", Weight: 7, Capacity: 8}},
+	}
+	metrics := []internal.VehicleMetric{
+		{Aggregate: internal.VehicleAggregateCount},
+		{Aggregate: internal.VehicleAggregateAvg, Field: internal.VehicleFieldWeight},
+		{Aggregate: internal.VehicleAggregateMin, Field: internal.VehicleFieldWeight},
+		{Aggregate: internal.VehicleAggregateMax, Field: internal.VehicleFieldWeight},
+		{Aggregate: internal.VehicleAggregatePercentile, Percentile: 50, Field: internal.VehicleFieldCapacity},
+	}
+
+	t.Run("Group by brand and fuel type", func(t *testing.T) {
+		// Given
+		groupBy := []internal.VehicleField{internal.VehicleFieldBrand, internal.VehicleFieldFuelType}
+
+		expectedResult := []internal.VehicleStatsRow{
+			{
+				Group:   map[internal.VehicleField]any{"brand": "A", "fuel_type": "diesel"},
+				Metrics: map[string]float64{"count": 1, "avg:weight": 5, "min:weight": 5, "max:weight": 5, "p50:passengers": 6},
+			},
+			{
+				Group:   map[internal.VehicleField]any{"brand": "A", "fuel_type": "gas"},
+				Metrics: map[string]float64{"count": 2, "avg:weight": 2, "min:weight": 1, "max:weight": 3, "p50:passengers": 2},
+			},
+			{
+				Group:   map[internal.VehicleField]any{"brand": "B", "fuel_type": "gas"},
+				Metrics: map[string]float64{"count": 1, "avg:weight": 7, "min:weight": 7, "max:weight": 7, "p50:passengers": 8},
+			},
+		}
+		// When
+		result, err := internal.ComputeVehicleStats(v, groupBy, metrics)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	})
+
+	t.Run("Without group by", func(t *testing.T) {
+		// Given
+		expectedResult := []internal.VehicleStatsRow{
+			{
+				Group:   map[internal.VehicleField]any{},
+				Metrics: map[string]float64{"count": 4, "avg:weight": 4, "min:weight": 1, "max:weight": 7, "p50:passengers": 4},
+			},
+		}
+		// When
+		result, err := internal.ComputeVehicleStats(v, nil, metrics)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedResult, result)
+	})
+
+	t.Run("No vehicles", func(t *testing.T) {
+		// When
+		result, err := internal.ComputeVehicleStats(map[int]internal.Vehicle{}, nil, metrics)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, []internal.VehicleStatsRow{}, result)
+	})
+
+	t.Run("Invalid group by", func(t *testing.T) {
+		// When
+		_, err := internal.ComputeVehicleStats(v, []internal.VehicleField{"wheels"}, metrics)
+		// Then
+		require.ErrorIs(t, err, internal.ErrVehicleGroupInvalid)
+	})
+}
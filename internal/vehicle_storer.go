@@ -0,0 +1,7 @@
+package internal
+
+// StorerVehicle is an interface that represents the storer for vehicles
+type StorerVehicle interface {
+	// Store is a method that stores the vehicles
+	Store(v map[int]Vehicle) (err error)
+}
@@ -0,0 +1,246 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrVehicleInvalid is an error that represents a vehicle with invalid attributes
+	ErrVehicleInvalid = NewError(ErrValidation, "vehicle: invalid attributes")
+)
+
+const (
+	// MinFabricationYear is the first year a vehicle can be fabricated in
+	MinFabricationYear = 1886
+	// MaxVehicleTextLength is the maximum number of characters of the text attributes of a vehicle
+	MaxVehicleTextLength = 64
+	// MaxVehicleCapacity is the maximum number of passengers of a vehicle
+	MaxVehicleCapacity = 100
+)
+
+var (
+	// FuelTypes are the accepted fuel types of a vehicle
+	FuelTypes = []string{"gas", "gasoline", "diesel", "biodiesel", "electric", "hybrid"}
+	// Transmissions are the accepted transmissions of a vehicle
+	Transmissions = []string{"automatic", "manual", "semi-automatic"}
+	// registrationPattern is the format of a registration: 2 to 10 letters, digits or inner hyphens
+	registrationPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,8}[A-Za-z0-9]$`)
+)
+
+// MaxFabricationYear is a function that returns the last year a vehicle can be fabricated in, the next one
+func MaxFabricationYear() int {
+	return time.Now().Year() + 1
+}
+
+// FieldError is a struct that represents why the value of a field is invalid
+type FieldError struct {
+	// Field is the name of the field, as in VehicleField or the name of the query parameter
+	Field string
+	// Message is the description of the error
+	Message string
+}
+
+// ValidationError is an error that represents an input with invalid fields
+type ValidationError struct {
+	// Err is the error of the input, of kind ErrValidation
+	Err error
+	// Fields are the invalid fields, in the order they were checked
+	Fields []FieldError
+}
+
+// Error is a method that returns the error message with every invalid field
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = f.Field + " " + f.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Err, strings.Join(fields, "; "))
+}
+
+// Unwrap is a method that returns the error of the input
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate is a method that checks every attribute of the vehicle
+// - it returns a ValidationError wrapping ErrVehicleInvalid with the invalid attributes
+func (a VehicleAttributes) Validate() (err error) {
+	var v Validator
+	v.Text(string(VehicleFieldBrand), a.Brand)
+	v.Text(string(VehicleFieldModel), a.Model)
+	v.Registration(string(VehicleFieldRegistration), a.Registration)
+	v.Text(string(VehicleFieldColor), a.Color)
+	v.Year(string(VehicleFieldFabricationYear), a.FabricationYear)
+	v.Capacity(string(VehicleFieldCapacity), a.Capacity)
+	v.NonNegative(string(VehicleFieldMaxSpeed), a.MaxSpeed)
+	v.OneOf(string(VehicleFieldFuelType), a.FuelType, FuelTypes)
+	v.OneOf(string(VehicleFieldTransmission), a.Transmission, Transmissions)
+	v.NonNegative(string(VehicleFieldWeight), a.Weight)
+	v.NonNegative(string(VehicleFieldHeight), a.Height)
+	v.NonNegative(string(VehicleFieldLength), a.Length)
+	v.NonNegative(string(VehicleFieldWidth), a.Width)
+
+	err = v.Err(ErrVehicleInvalid)
+	return
+}
+
+// Validate is a method that checks the attributes set by the patch, with the same rules as VehicleAttributes
+func (p VehiclePatch) Validate() (err error) {
+	var v Validator
+	if p.Brand != nil {
+		v.Text(string(VehicleFieldBrand), *p.Brand)
+	}
+	if p.Model != nil {
+		v.Text(string(VehicleFieldModel), *p.Model)
+	}
+	if p.Registration != nil {
+		v.Registration(string(VehicleFieldRegistration), *p.Registration)
+	}
+	if p.Color != nil {
+		v.Text(string(VehicleFieldColor), *p.Color)
+	}
+	if p.FabricationYear != nil {
+		v.Year(string(VehicleFieldFabricationYear), *p.FabricationYear)
+	}
+	if p.Capacity != nil {
+		v.Capacity(string(VehicleFieldCapacity), *p.Capacity)
+	}
+	if p.MaxSpeed != nil {
+		v.NonNegative(string(VehicleFieldMaxSpeed), *p.MaxSpeed)
+	}
+	if p.FuelType != nil {
+		v.OneOf(string(VehicleFieldFuelType), *p.FuelType, FuelTypes)
+	}
+	if p.Transmission != nil {
+		v.OneOf(string(VehicleFieldTransmission), *p.Transmission, Transmissions)
+	}
+	if p.Weight != nil {
+		v.NonNegative(string(VehicleFieldWeight), *p.Weight)
+	}
+	if p.Height != nil {
+		v.NonNegative(string(VehicleFieldHeight), *p.Height)
+	}
+	if p.Length != nil {
+		v.NonNegative(string(VehicleFieldLength), *p.Length)
+	}
+	if p.Width != nil {
+		v.NonNegative(string(VehicleFieldWidth), *p.Width)
+	}
+
+	err = v.Err(ErrVehicleInvalid)
+	return
+}
+
+// Validator is a struct that collects the invalid fields of an input
+// - the zero value is ready to use
+type Validator struct {
+	// fields are the invalid fields
+	fields []FieldError
+}
+
+// Add is a method that records an invalid field
+func (v *Validator) Add(field, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message})
+}
+
+// Check is a method that records an invalid field if the condition does not hold
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.Add(field, message)
+	}
+}
+
+// Text is a method that checks a required text of at most MaxVehicleTextLength characters
+func (v *Validator) Text(field, value string) {
+	switch {
+	case strings.TrimSpace(value) == "":
+		v.Add(field, "is required")
+	case len([]rune(value)) > MaxVehicleTextLength:
+		v.Add(field, fmt.Sprintf("must have at most %d characters", MaxVehicleTextLength))
+	}
+}
+
+// Registration is a method that checks the format of a registration
+func (v *Validator) Registration(field, value string) {
+	switch {
+	case value == "":
+		v.Add(field, "is required")
+	case !registrationPattern.MatchString(value) || strings.Trim(value, "0-") == "":
+		v.Add(field, "must have 2 to 10 letters, digits or inner hyphens, not only zeros")
+	}
+}
+
+// Year is a method that checks a fabrication year
+func (v *Validator) Year(field string, value int) {
+	max := MaxFabricationYear()
+	v.Check(value >= MinFabricationYear && value <= max, field, fmt.Sprintf("must be between %d and %d", MinFabricationYear, max))
+}
+
+// Capacity is a method that checks a number of passengers
+func (v *Validator) Capacity(field string, value int) {
+	v.Check(value >= 1 && value <= MaxVehicleCapacity, field, fmt.Sprintf("must be between 1 and %d", MaxVehicleCapacity))
+}
+
+// NonNegative is a method that checks a measure that can not be negative
+func (v *Validator) NonNegative(field string, value float64) {
+	v.Check(value >= 0, field, "must not be negative")
+}
+
+// Integer is a method that checks a number without a fractional part, and returns whether it is one
+func (v *Validator) Integer(field string, value float64) (ok bool) {
+	ok = value == math.Trunc(value)
+	v.Check(ok, field, "must be an integer")
+	return
+}
+
+// OneOf is a method that checks a value of an enumeration
+func (v *Validator) OneOf(field, value string, values []string) {
+	v.Check(slices.Contains(values, value), field, "must be one of "+strings.Join(values, ", "))
+}
+
+// Condition is a method that checks the values of a condition with the rules of the attributes of its field
+// - only the first invalid value is recorded
+func (v *Validator) Condition(field string, c VehicleCondition) {
+	n := len(v.fields)
+	for _, value := range c.Numbers {
+		if len(v.fields) > n {
+			return
+		}
+		switch c.Field {
+		case VehicleFieldFabricationYear:
+			if v.Integer(field, value) {
+				v.Year(field, int(value))
+			}
+		case VehicleFieldCapacity:
+			if v.Integer(field, value) {
+				v.Capacity(field, int(value))
+			}
+		case VehicleFieldMaxSpeed, VehicleFieldWeight, VehicleFieldHeight, VehicleFieldLength, VehicleFieldWidth:
+			v.NonNegative(field, value)
+		}
+	}
+	for _, value := range c.Strings {
+		if len(v.fields) > n {
+			return
+		}
+		switch c.Field {
+		case VehicleFieldFuelType:
+			v.OneOf(field, value, FuelTypes)
+		case VehicleFieldTransmission:
+			v.OneOf(field, value, Transmissions)
+		}
+	}
+}
+
+// Err is a method that returns a ValidationError wrapping err with the invalid fields, nil if there are none
+func (v *Validator) Err(err error) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Err: err, Fields: v.fields}
+}
@@ -0,0 +1,110 @@
+package internal_test
+
+import (
+	"app/internal"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVehicleAttributes_Validate(t *testing.T) {
+	valid := internal.VehicleAttributes{
+		Brand:           "Hummer",
+		Model:           "H2",
+		Registration:    "AB-123",
+		Color:           "Orange",
+		FabricationYear: 2008,
+		Capacity:        3,
+		MaxSpeed:        143,
+		FuelType:        "biodiesel",
+		Transmission:    "automatic",
+		Weight:          244.87,
+	}
+
+	t.Run("Valid attributes", func(t *testing.T) {
+		// When
+		err := valid.Validate()
+		// Then
+		require.NoError(t, err)
+	})
+
+	t.Run("Every attribute invalid", func(t *testing.T) {
+		// Given
+		a := internal.VehicleAttributes{
+			FabricationYear: 1800,
+			MaxSpeed:        -1,
+			Weight:          -1,
+			Dimensions:      internal.Dimensions{Height: -1, Length: -1, Width: -1},
+		}
+
+		expectedFields := []string{"brand", "model", "registration", "color", "year", "passengers", "max_speed", "fuel_type", "transmission", "weight", "height", "length", "width"}
+		// When
+		err := a.Validate()
+		// Then
+		var ve *internal.ValidationError
+		require.ErrorAs(t, err, &ve)
+		require.ErrorIs(t, err, internal.ErrVehicleInvalid)
+		fields := make([]string, len(ve.Fields))
+		for i, f := range ve.Fields {
+			fields[i] = f.Field
+		}
+		require.Equal(t, expectedFields, fields)
+	})
+
+	t.Run("Registrations", func(t *testing.T) {
+		// Given
+		cases := map[string]bool{
+			"AB-123":      true,
+			"09":          true,
+			"xyz9":        true,
+			"0":           false,
+			"000":         false,
+			"-AB":         false,
+			"AB-":         false,
+			"AB 123":      false,
+			"ABCDEFGHIJK": false,
+		}
+
+		for registration, expectedValid := range cases {
+			a := valid
+			a.Registration = registration
+			// When
+			err := a.Validate()
+			// Then
+			require.Equal(t, expectedValid, err == nil, registration)
+		}
+	})
+
+	t.Run("Bounds of the fabrication year", func(t *testing.T) {
+		// Given
+		cases := map[int]bool{
+			internal.MinFabricationYear - 1:   false,
+			internal.MinFabricationYear:       true,
+			internal.MaxFabricationYear():     true,
+			internal.MaxFabricationYear() + 1: false,
+		}
+
+		for year, expectedValid := range cases {
+			a := valid
+			a.FabricationYear = year
+			// When
+			err := a.Validate()
+			// Then
+			require.Equal(t, expectedValid, err == nil, fmt.Sprint(year))
+		}
+	})
+}
+
+func TestValidationError_Error(t *testing.T) {
+	// Given
+	err := &internal.ValidationError{
+		Err: internal.ErrVehicleInvalid,
+		Fields: []internal.FieldError{
+			{Field: "brand", Message: "is required"},
+			{Field: "weight", Message: "must not be negative"},
+		},
+	}
+	// Then
+	require.EqualError(t, err, "vehicle: invalid attributes: brand is required; weight must not be negative")
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// New is a function that returns a logger that writes JSON records with at least the given level
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// loggerKey is the key of the logger in a context
+type loggerKey struct{}
+
+// requestIDKey is the key of the request ID in a context
+type requestIDKey struct{}
+
+// WithLogger is a function that returns a copy of the context that carries the logger
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext is a function that returns the logger of the context, or slog.Default if there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// WithRequestID is a function that returns a copy of the context that carries the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID is a function that returns the request ID of the context, empty if there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// RequestIDHeader is the header that carries the request ID, read from the request and written in the response
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength is the maximum length of a request ID accepted from a client
+const maxRequestIDLength = 64
+
+// Middleware is a function that returns a middleware that gives every request an ID and a logger and logs it once served
+// - the ID is taken from the X-Request-Id header if it is valid, otherwise a new one is generated
+// - the logger of the request context carries the ID, so every record logged while serving it can be correlated
+func Middleware(base *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			l := base.With(slog.String("request_id", id))
+			ctx := WithLogger(WithRequestID(r.Context(), id), l)
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			level := slog.LevelInfo
+			if sw.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			l.LogAttrs(ctx, level, "request served",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("query", r.URL.RawQuery),
+				slog.Int("status", sw.status),
+				slog.Int("bytes", sw.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// Recoverer is a middleware that logs a panic of the handler with the logger of the request and responds 500
+// - it must be registered after Middleware to log with the request ID
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				// the server aborts the response silently
+				panic(rec)
+			}
+			FromContext(r.Context()).Error("panic while serving the request",
+				slog.Any("panic", rec),
+				slog.String("stack", string(debug.Stack())),
+			)
+			w.WriteHeader(http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validRequestID is a function that returns whether a request ID given by a client can be used
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID is a function that returns a random request ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusWriter is a response writer that records the status code and the size of the body
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusWriter) Write(b []byte) (n int, err error) {
+	s.wroteHeader = true
+	n, err = s.ResponseWriter.Write(b)
+	s.bytes += n
+	return
+}
+
+// Unwrap is a method that returns the wrapped writer, used by http.ResponseController
+func (s *statusWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,102 @@
+package logging_test
+
+import (
+	"app/platform/logging"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// records is a helper that decodes the JSON records written by a logger
+func records(t *testing.T, b *bytes.Buffer) (r []map[string]any) {
+	t.Helper()
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		r = append(r, m)
+	}
+	return
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Propagate the request ID given by the client", func(t *testing.T) {
+		// Given
+		var b bytes.Buffer
+		mw := logging.Middleware(logging.New(&b, slog.LevelInfo))
+		var gotID string
+		hd := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotID = logging.RequestID(r.Context())
+			logging.FromContext(r.Context()).Info("handling")
+			w.WriteHeader(http.StatusTeapot)
+		}))
+
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?brand=Ford", nil)
+		req.Header.Set(logging.RequestIDHeader, "abc-123")
+		res := httptest.NewRecorder()
+		hd.ServeHTTP(res, req)
+		// Then
+		require.Equal(t, "abc-123", gotID)
+		require.Equal(t, "abc-123", res.Header().Get(logging.RequestIDHeader))
+		r := records(t, &b)
+		require.Len(t, r, 2)
+		require.Equal(t, "handling", r[0]["msg"])
+		require.Equal(t, "abc-123", r[0]["request_id"])
+		require.Equal(t, "request served", r[1]["msg"])
+		require.Equal(t, "abc-123", r[1]["request_id"])
+		require.Equal(t, "/vehicles", r[1]["path"])
+		require.Equal(t, "brand=Ford", r[1]["query"])
+		require.Equal(t, float64(http.StatusTeapot), r[1]["status"])
+	})
+
+	t.Run("Generate a request ID when the given one is invalid", func(t *testing.T) {
+		// Given
+		var b bytes.Buffer
+		mw := logging.Middleware(logging.New(&b, slog.LevelInfo))
+		hd := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		// When
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logging.RequestIDHeader, "not valid\n")
+		res := httptest.NewRecorder()
+		hd.ServeHTTP(res, req)
+		// Then
+		id := res.Header().Get(logging.RequestIDHeader)
+		require.Len(t, id, 32)
+		require.Equal(t, id, records(t, &b)[0]["request_id"])
+	})
+
+	t.Run("Log server errors with level error", func(t *testing.T) {
+		// Given
+		var b bytes.Buffer
+		mw := logging.Middleware(logging.New(&b, slog.LevelInfo))
+		hd := mw(logging.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})))
+
+		// When
+		res := httptest.NewRecorder()
+		hd.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+		// Then
+		require.Equal(t, http.StatusInternalServerError, res.Code)
+		r := records(t, &b)
+		require.Len(t, r, 2)
+		require.Equal(t, "panic while serving the request", r[0]["msg"])
+		require.Equal(t, "boom", r[0]["panic"])
+		require.Equal(t, r[0]["request_id"], r[1]["request_id"])
+		require.Equal(t, "ERROR", r[1]["level"])
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	// When
+	l := logging.FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context())
+	// Then
+	require.Equal(t, slog.Default(), l)
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"io"
+	"sync"
+)
+
+// NewCounterVec is a function that returns a new instance of CounterVec
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		family: family{name: name, help: help, labels: labels},
+		values: make(map[string]float64),
+		labels: make(map[string][]string),
+	}
+}
+
+// CounterVec is a struct that represents a family of counters partitioned by labels
+type CounterVec struct {
+	family
+	// mu protects values and labels
+	mu sync.Mutex
+	// values are the counters by key of label values
+	values map[string]float64
+	// labels are the label values by key
+	labels map[string][]string
+}
+
+// Inc is a method that adds one to the counter with the given label values
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add is a method that adds a non-negative delta to the counter with the given label values
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		panic("metrics: counter " + c.name + " can not decrease")
+	}
+	k := c.key(values)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.labels[k]; !ok {
+		c.labels[k] = append([]string(nil), values...)
+	}
+	c.values[k] += delta
+}
+
+// Value is a method that returns the counter with the given label values
+func (c *CounterVec) Value(values ...string) float64 {
+	k := c.key(values)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values[k]
+}
+
+// Collect is a method that writes the counters
+func (c *CounterVec) Collect(w io.Writer) (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err = c.header(w, "counter"); err != nil {
+		return
+	}
+	for _, s := range sortedSeries(c.values, c.labels) {
+		if err = writeSample(w, c.name, c.family.labels, s.values, [2]string{}, c.values[s.key]); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"io"
+	"sort"
+)
+
+// Type of the metric families whose samples are computed when collected
+const (
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+)
+
+// NewFunc is a function that returns a new instance of Func
+// - typ is TypeGauge or TypeCounter
+func NewFunc(name, help, typ string, labels []string, samples func() []Sample) *Func {
+	return &Func{
+		family:  family{name: name, help: help, labels: labels},
+		typ:     typ,
+		samples: samples,
+	}
+}
+
+// Func is a struct that represents a metric family whose samples are computed when collected,
+// used to expose values owned by other components, like the size of the repository
+type Func struct {
+	family
+	// typ is the type of the family
+	typ string
+	// samples is the function that computes the samples
+	samples func() []Sample
+}
+
+// Collect is a method that computes and writes the samples
+func (f *Func) Collect(w io.Writer) (err error) {
+	samples := f.samples()
+
+	type row struct {
+		values []string
+		value  float64
+	}
+	rows := make([]row, 0, len(samples))
+	for _, s := range samples {
+		values := make([]string, len(f.labels))
+		for i, l := range f.labels {
+			values[i] = s.Labels[l]
+		}
+		rows = append(rows, row{values: values, value: s.Value})
+	}
+	sort.Slice(rows, func(i, j int) bool { return f.key(rows[i].values) < f.key(rows[j].values) })
+
+	if err = f.header(w, f.typ); err != nil {
+		return
+	}
+	for _, r := range rows {
+		if err = writeSample(w, f.name, f.labels, r.values, [2]string{}, r.value); err != nil {
+			return
+		}
+	}
+	return
+}
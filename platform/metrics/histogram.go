@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds of the buckets of a histogram of latencies in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewHistogramVec is a function that returns a new instance of HistogramVec
+// - buckets are the upper bounds of the buckets, DefaultBuckets if nil. The +Inf bucket is implicit
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &HistogramVec{
+		family:  family{name: name, help: help, labels: labels},
+		buckets: buckets,
+		values:  make(map[string]*histogram),
+		labels:  make(map[string][]string),
+	}
+}
+
+// HistogramVec is a struct that represents a family of histograms partitioned by labels
+type HistogramVec struct {
+	family
+	// buckets are the upper bounds of the buckets, sorted
+	buckets []float64
+	// mu protects values and labels
+	mu sync.Mutex
+	// values are the histograms by key of label values
+	values map[string]*histogram
+	// labels are the label values by key
+	labels map[string][]string
+}
+
+// histogram is a struct with the observations of a single series
+type histogram struct {
+	// counts are the observations by bucket, not cumulative
+	counts []uint64
+	// count is the total number of observations
+	count uint64
+	// sum is the sum of the observations
+	sum float64
+}
+
+// Observe is a method that adds an observation to the histogram with the given label values
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	k := h.key(values)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.values[k]
+	if !ok {
+		s = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[k] = s
+		h.labels[k] = append([]string(nil), values...)
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// Collect is a method that writes the cumulative buckets, the sum and the count of each histogram
+func (h *HistogramVec) Collect(w io.Writer) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err = h.header(w, "histogram"); err != nil {
+		return
+	}
+	for _, s := range sortedSeries(h.values, h.labels) {
+		v := h.values[s.key]
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += v.counts[i]
+			if err = writeSample(w, h.name+"_bucket", h.family.labels, s.values, [2]string{"le", formatValue(le)}, float64(cumulative)); err != nil {
+				return
+			}
+		}
+		if err = writeSample(w, h.name+"_bucket", h.family.labels, s.values, [2]string{"le", formatValue(math.Inf(1))}, float64(v.count)); err != nil {
+			return
+		}
+		if err = writeSample(w, h.name+"_sum", h.family.labels, s.values, [2]string{}, v.sum); err != nil {
+			return
+		}
+		if err = writeSample(w, h.name+"_count", h.family.labels, s.values, [2]string{}, float64(v.count)); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewHTTPMetrics is a function that returns a new instance of HTTPMetrics, registering its families in the registry
+// - route returns the pattern of the route that served the request, so that paths with ids share a series
+func NewHTTPMetrics(reg *Registry, route func(r *http.Request) string) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requests: NewCounterVec("http_requests_total", "Number of HTTP requests by method, route and status code.", "method", "route", "status"),
+		duration: NewHistogramVec("http_request_duration_seconds", "Latency of the HTTP requests by method and route.", nil, "method", "route"),
+		route:    route,
+	}
+	reg.Register(m.requests, m.duration)
+	return m
+}
+
+// HTTPMetrics is a struct that instruments the requests served by an http.Handler
+type HTTPMetrics struct {
+	// requests counts the requests by method, route and status code
+	requests *CounterVec
+	// duration observes the latency of the requests by method and route
+	duration *HistogramVec
+	// route returns the pattern of the route that served the request
+	route func(r *http.Request) string
+}
+
+// Middleware is a method that records the count, status code and latency of every request
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(sw, r)
+
+		route := m.route(r)
+		if route == "" {
+			// unmatched paths share a single series to keep the cardinality bounded
+			route = "unmatched"
+		}
+		m.requests.Inc(r.Method, route, strconv.Itoa(sw.status))
+		m.duration.Observe(time.Since(start).Seconds(), r.Method, route)
+	})
+}
+
+// statusWriter is a response writer that records the status code
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusWriter) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap is a method that returns the wrapped writer, used by http.ResponseController
+func (s *statusWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
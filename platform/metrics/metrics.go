@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector is an interface that represents a metric family that can be written in the Prometheus text format
+type Collector interface {
+	// Name is a method that returns the name of the metric family
+	Name() (name string)
+	// Collect is a method that writes the HELP and TYPE lines and the samples of the family
+	Collect(w io.Writer) (err error)
+}
+
+// NewRegistry is a function that returns a new instance of Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Registry is a struct that holds the collectors exposed by the application
+type Registry struct {
+	// mu protects collectors
+	mu sync.Mutex
+	// collectors are the registered metric families
+	collectors []Collector
+}
+
+// Register is a method that adds collectors to the registry
+// - it panics if a family with the same name was already registered, as it is a programming error
+func (r *Registry) Register(cs ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range cs {
+		for _, rc := range r.collectors {
+			if rc.Name() == c.Name() {
+				panic("metrics: duplicate metric family " + c.Name())
+			}
+		}
+		r.collectors = append(r.collectors, c)
+	}
+}
+
+// WriteTo is a method that writes every family, sorted by name, in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (n int64, err error) {
+	r.mu.Lock()
+	cs := make([]Collector, len(r.collectors))
+	copy(cs, r.collectors)
+	r.mu.Unlock()
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Name() < cs[j].Name() })
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, c := range cs {
+		if err = c.Collect(bw); err != nil {
+			return cw.n, err
+		}
+	}
+	err = bw.Flush()
+	return cw.n, err
+}
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler is a method that returns a handler that exposes the registry
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		w.WriteHeader(http.StatusOK)
+		r.WriteTo(w)
+	}
+}
+
+// countWriter is a writer that counts the bytes written
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (n int, err error) {
+	n, err = c.w.Write(p)
+	c.n += int64(n)
+	return
+}
+
+// Labels is a set of label names and values of a sample
+type Labels map[string]string
+
+// Sample is a struct that represents a value with its labels
+type Sample struct {
+	// Labels are the labels of the sample
+	Labels Labels
+	// Value is the value of the sample
+	Value float64
+}
+
+// family is a struct with the description shared by every metric family
+type family struct {
+	// name is the name of the metric family
+	name string
+	// help is the description of the metric family
+	help string
+	// labels are the names of the labels, in order
+	labels []string
+}
+
+// Name is a method that returns the name of the metric family
+func (f family) Name() string {
+	return f.name
+}
+
+// header is a method that writes the HELP and TYPE lines
+func (f family) header(w io.Writer, typ string) (err error) {
+	_, err = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, typ)
+	return
+}
+
+// key is a method that returns the key of a set of label values
+// - it panics if the number of values does not match the labels, as it is a programming error
+func (f family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// series is a struct that represents the label values of a sample
+type series struct {
+	key    string
+	values []string
+}
+
+// sortedSeries is a function that returns the series sorted by their label values
+func sortedSeries[T any](m map[string]T, values map[string][]string) (s []series) {
+	s = make([]series, 0, len(m))
+	for k := range m {
+		s = append(s, series{key: k, values: values[k]})
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].key < s[j].key })
+	return
+}
+
+// writeSample is a function that writes a sample line
+// - extra is an additional label, like le for histograms, written last
+func writeSample(w io.Writer, name string, labels []string, values []string, extra [2]string, value float64) (err error) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 || extra[0] != "" {
+		b.WriteByte('{')
+		first := true
+		write := func(l, v string) {
+			if !first {
+				b.WriteByte(',')
+			}
+			first = false
+			b.WriteString(l)
+			b.WriteString(`="`)
+			b.WriteString(escapeLabel(v))
+			b.WriteByte('"')
+		}
+		for i, l := range labels {
+			write(l, values[i])
+		}
+		if extra[0] != "" {
+			write(extra[0], extra[1])
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatValue(value))
+	b.WriteByte('\n')
+	_, err = io.WriteString(w, b.String())
+	return
+}
+
+// formatValue is a function that formats a value as Prometheus expects it
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabel is a function that escapes a label value
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// escapeHelp is a function that escapes a help text
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
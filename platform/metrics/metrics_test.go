@@ -0,0 +1,116 @@
+package metrics_test
+
+import (
+	"app/platform/metrics"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	t.Run("Counters, histograms and functions sorted by name and labels", func(t *testing.T) {
+		// Given
+		reg := metrics.NewRegistry()
+		c := metrics.NewCounterVec("requests_total", "Number of requests.", "code")
+		h := metrics.NewHistogramVec("latency_seconds", "Latency.", []float64{0.5, 0.1}, "route")
+		f := metrics.NewFunc("fleet_size", "Vehicles by brand.", metrics.TypeGauge, []string{"brand"}, func() []metrics.Sample {
+			return []metrics.Sample{
+				{Labels: metrics.Labels{"brand": "Ford"}, Value: 7},
+				{Labels: metrics.Labels{"brand": `A "quoted"\ brand`}, Value: 1},
+			}
+		})
+		reg.Register(c, h, f)
+
+		c.Inc("500")
+		c.Add(2, "200")
+		h.Observe(0.05, "/a")
+		h.Observe(0.2, "/a")
+		h.Observe(3, "/a")
+
+		expectedOutput := `# HELP fleet_size Vehicles by brand.
+# TYPE fleet_size gauge
+fleet_size{brand="A \"quoted\"\\ brand"} 1
+fleet_size{brand="Ford"} 7
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 1
+latency_seconds_bucket{route="/a",le="0.5"} 2
+latency_seconds_bucket{route="/a",le="+Inf"} 3
+latency_seconds_sum{route="/a"} 3.25
+latency_seconds_count{route="/a"} 3
+# HELP requests_total Number of requests.
+# TYPE requests_total counter
+requests_total{code="200"} 2
+requests_total{code="500"} 1
+`
+		// When
+		var b bytes.Buffer
+		n, err := reg.WriteTo(&b)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedOutput, b.String())
+		require.Equal(t, int64(len(expectedOutput)), n)
+		require.Equal(t, float64(2), c.Value("200"))
+	})
+
+	t.Run("Family without labels", func(t *testing.T) {
+		// Given
+		reg := metrics.NewRegistry()
+		reg.Register(metrics.NewFunc("up", "Up.", metrics.TypeGauge, nil, func() []metrics.Sample {
+			return []metrics.Sample{{Value: 1}}
+		}))
+
+		expectedOutput := "# HELP up Up.\n# TYPE up gauge\nup 1\n"
+		// When
+		var b bytes.Buffer
+		_, err := reg.WriteTo(&b)
+		// Then
+		require.NoError(t, err)
+		require.Equal(t, expectedOutput, b.String())
+	})
+
+	t.Run("Duplicate family", func(t *testing.T) {
+		// Given
+		reg := metrics.NewRegistry()
+		reg.Register(metrics.NewCounterVec("requests_total", "Number of requests."))
+		// When / Then
+		require.Panics(t, func() {
+			reg.Register(metrics.NewCounterVec("requests_total", "Number of requests."))
+		})
+	})
+}
+
+func TestHTTPMetrics_Middleware(t *testing.T) {
+	// Given
+	reg := metrics.NewRegistry()
+	mt := metrics.NewHTTPMetrics(reg, func(r *http.Request) string {
+		if r.URL.Path == "/missing" {
+			return ""
+		}
+		return "/vehicles/{id}"
+	})
+	hd := mt.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	// When
+	for _, path := range []string{"/vehicles/1", "/vehicles/2", "/missing"} {
+		hd.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	res := httptest.NewRecorder()
+	reg.Handler()(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	// Then
+	require.Equal(t, http.StatusOK, res.Code)
+	require.Equal(t, metrics.ContentType, res.Header().Get("Content-Type"))
+	require.Contains(t, res.Body.String(), `http_requests_total{method="GET",route="/vehicles/{id}",status="200"} 2`)
+	require.Contains(t, res.Body.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Contains(t, res.Body.String(), `http_request_duration_seconds_count{method="GET",route="/vehicles/{id}"} 2`)
+	require.Contains(t, res.Body.String(), `http_request_duration_seconds_bucket{method="GET",route="unmatched",le="+Inf"} 1`)
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+)
+
+// MaxEntrySize is the maximum size of the body of a response kept by the cache, larger responses are served but not kept
+const MaxEntrySize = 1 << 20
+
+// Entry is a struct that represents a response kept by the cache
+type Entry struct {
+	// Header are the headers set by the handler of the response
+	Header http.Header
+	// Body is the body of the response
+	Body []byte
+}
+
+// NewLRU is a function that returns a new instance of LRU that keeps up to size responses
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// LRU is a struct that keeps the responses of a version of the data, evicting the least recently used ones
+// - a newer version drops every response of the previous one, responses of older versions are never kept nor served
+type LRU struct {
+	// mu guards the responses
+	mu sync.Mutex
+	// size is the maximum number of responses
+	size int
+	// version is the version of the data of the responses
+	version uint64
+	// ll is the list of the responses, the most recently used first
+	ll *list.List
+	// items are the elements of the list by key
+	items map[string]*list.Element
+}
+
+// lruItem is a struct that represents an element of the list of an LRU
+type lruItem struct {
+	key   string
+	entry Entry
+}
+
+// Get is a method that returns the response of a key for a version of the data
+func (c *LRU) Get(version uint64, key string) (e Entry, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.use(version) {
+		return
+	}
+	el, ok := c.items[key]
+	if !ok {
+		return
+	}
+	c.ll.MoveToFront(el)
+	e = el.Value.(*lruItem).entry
+	return
+}
+
+// Put is a method that keeps the response of a key for a version of the data
+// - the entry must not be modified afterwards, it is shared by every Get
+func (c *LRU) Put(version uint64, key string, e Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 || len(e.Body) > MaxEntrySize || !c.use(version) {
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruItem).entry = e
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruItem{key: key, entry: e})
+	if c.ll.Len() > c.size {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*lruItem).key)
+	}
+}
+
+// Len is a method that returns the number of responses kept
+func (c *LRU) Len() (n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n = c.ll.Len()
+	return
+}
+
+// use is a method that returns whether the responses of a version can be used, dropping the ones of older versions
+func (c *LRU) use(version uint64) bool {
+	if version < c.version {
+		return false
+	}
+	if version > c.version {
+		c.version = version
+		c.ll.Init()
+		clear(c.items)
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package cache_test
+
+import (
+	"app/platform/web/cache"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	t.Run("Evict the least recently used response", func(t *testing.T) {
+		// Given
+		c := cache.NewLRU(2)
+		c.Put(1, "a", cache.Entry{Body: []byte("a")})
+		c.Put(1, "b", cache.Entry{Body: []byte("b")})
+
+		// When
+		_, okA := c.Get(1, "a")
+		c.Put(1, "c", cache.Entry{Body: []byte("c")})
+		_, okB := c.Get(1, "b")
+		eA, okA2 := c.Get(1, "a")
+		// Then
+		require.True(t, okA)
+		require.False(t, okB)
+		require.True(t, okA2)
+		require.Equal(t, []byte("a"), eA.Body)
+		require.Equal(t, 2, c.Len())
+	})
+
+	t.Run("Drop the responses of older versions", func(t *testing.T) {
+		// Given
+		c := cache.NewLRU(2)
+		c.Put(1, "a", cache.Entry{Body: []byte("a")})
+
+		// When
+		_, okNew := c.Get(2, "a")
+		c.Put(1, "a", cache.Entry{Body: []byte("a")})
+		_, okOld := c.Get(1, "a")
+		// Then
+		require.False(t, okNew)
+		require.False(t, okOld)
+		require.Equal(t, 0, c.Len())
+	})
+
+	t.Run("Keep nothing too large or with no size", func(t *testing.T) {
+		// Given
+		large := cache.NewLRU(1)
+		empty := cache.NewLRU(0)
+
+		// When
+		large.Put(1, "a", cache.Entry{Body: make([]byte, cache.MaxEntrySize+1)})
+		empty.Put(1, "a", cache.Entry{})
+		// Then
+		require.Equal(t, 0, large.Len())
+		require.Equal(t, 0, empty.Len())
+	})
+}
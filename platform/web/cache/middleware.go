@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Middleware is a function that returns a middleware that tags the responses of GET requests with a strong ETag
+// - the ETag is derived from the version of the data and the request, so it changes whenever the data changes
+// - a request with a matching If-None-Match is answered with 304 Not Modified without calling the handler,
+// with the headers of notModifiedHeaders of the kept response, and always Vary: Accept since the ETag depends on it
+// - the 200 responses are kept in lru and served from it while the version does not change, a nil lru keeps nothing
+// - the version only identifies the data within the process, so a random epoch is hashed in the ETag on each start
+func Middleware(version func() uint64, lru *LRU) func(http.Handler) http.Handler {
+	epoch := make([]byte, 8)
+	rand.Read(epoch)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			v := version()
+			key := r.URL.RequestURI() + "\n" + strings.Join(r.Header.Values("Accept"), ",")
+			etag := newETag(epoch, v, key)
+
+			if matchETag(r.Header.Values("If-None-Match"), etag) {
+				if lru != nil {
+					if e, ok := lru.Get(v, key); ok {
+						for _, k := range notModifiedHeaders {
+							if vs, ok := e.Header[k]; ok {
+								w.Header()[k] = vs
+							}
+						}
+					}
+				}
+				if !varies(w.Header(), "Accept") {
+					w.Header().Add("Vary", "Accept")
+				}
+				w.Header().Set("ETag", etag)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			if lru != nil {
+				if e, ok := lru.Get(v, key); ok {
+					for k, vs := range e.Header {
+						w.Header()[k] = vs
+					}
+					w.WriteHeader(http.StatusOK)
+					w.Write(e.Body)
+					return
+				}
+			}
+
+			before := w.Header().Clone()
+			cw := &cacheWriter{ResponseWriter: w, etag: etag, status: http.StatusOK}
+			next.ServeHTTP(cw, r)
+
+			if lru == nil || cw.status != http.StatusOK || cw.discard {
+				return
+			}
+			lru.Put(v, key, Entry{Header: changedHeader(before, w.Header()), Body: cw.body.Bytes()})
+		})
+	}
+}
+
+// notModifiedHeaders are the headers of a 200 response that are also sent in its 304, as required by RFC 9110
+// - the ETag is not one of them, it is set from the request
+var notModifiedHeaders = []string{"Cache-Control", "Content-Location", "Date", "Expires", "Vary"}
+
+// newETag is a function that returns the strong ETag of a request for a version of the data
+func newETag(epoch []byte, version uint64, key string) string {
+	h := sha256.New()
+	h.Write(epoch)
+	h.Write([]byte(key))
+	return `"` + strconv.FormatUint(version, 10) + "-" + hex.EncodeToString(h.Sum(nil))[:16] + `"`
+}
+
+// matchETag is a function that returns whether any of the ETags of the If-None-Match headers is etag
+// - the comparison is weak, as required for If-None-Match, so a W/ prefix is ignored
+// - "*" is not matched, the handler tells whether the resource exists
+func matchETag(headers []string, etag string) bool {
+	for _, h := range headers {
+		for _, t := range strings.Split(h, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+			if t == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// varies is a function that returns whether the Vary headers name the header
+func varies(h http.Header, name string) bool {
+	for _, v := range h.Values("Vary") {
+		for _, n := range strings.Split(v, ",") {
+			if n = strings.TrimSpace(n); n == "*" || strings.EqualFold(n, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changedHeader is a function that returns the headers of after that are not in before, the ones set by the handler
+func changedHeader(before, after http.Header) (h http.Header) {
+	h = make(http.Header, len(after))
+	for k, vs := range after {
+		if !slices.Equal(before[k], vs) {
+			h[k] = slices.Clone(vs)
+		}
+	}
+	return
+}
+
+// cacheWriter is a response writer that sets the ETag of 200 responses and keeps a copy of their body
+type cacheWriter struct {
+	http.ResponseWriter
+	etag        string
+	status      int
+	wroteHeader bool
+	// body is the copy of the body, up to MaxEntrySize
+	body bytes.Buffer
+	// discard is whether the body is larger than MaxEntrySize or failed to be written, so it is not kept
+	discard bool
+}
+
+func (c *cacheWriter) WriteHeader(code int) {
+	if !c.wroteHeader {
+		c.status = code
+		c.wroteHeader = true
+		if code == http.StatusOK {
+			c.Header().Set("ETag", c.etag)
+		} else {
+			c.Header().Del("ETag")
+		}
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *cacheWriter) Write(b []byte) (n int, err error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	n, err = c.ResponseWriter.Write(b)
+	if c.discard {
+		return
+	}
+	if err != nil || c.body.Len()+n > MaxEntrySize {
+		c.discard = true
+		c.body = bytes.Buffer{}
+		return
+	}
+	c.body.Write(b[:n])
+	return
+}
+
+// Unwrap is a method that returns the wrapped writer, used by http.ResponseController
+func (c *cacheWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
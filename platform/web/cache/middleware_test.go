@@ -0,0 +1,167 @@
+package cache_test
+
+import (
+	"app/platform/web/cache"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler is a handler that responds a body with the current version and counts its calls
+type countingHandler struct {
+	version *uint64
+	calls   int
+	status  int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("X-Total-Count", "1")
+	if h.status != 0 {
+		w.WriteHeader(h.status)
+	}
+	w.Write([]byte("version " + strconv.FormatUint(*h.version, 10)))
+}
+
+// serve is a helper that serves a request with the given headers
+func serve(hd http.Handler, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	res := httptest.NewRecorder()
+	hd.ServeHTTP(res, req)
+	return res
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Respond 304 to a matching If-None-Match until the version changes", func(t *testing.T) {
+		// Given
+		var version uint64 = 1
+		h := &countingHandler{version: &version}
+		hd := cache.Middleware(func() uint64 { return version }, nil)(h)
+		first := serve(hd, http.MethodGet, "/vehicles?brand=Ford", nil)
+		etag := first.Header().Get("ETag")
+
+		// When
+		same := serve(hd, http.MethodGet, "/vehicles?brand=Ford", map[string]string{"If-None-Match": `"other", W/` + etag})
+		other := serve(hd, http.MethodGet, "/vehicles?brand=Fiat", map[string]string{"If-None-Match": etag})
+		version = 2
+		changed := serve(hd, http.MethodGet, "/vehicles?brand=Ford", map[string]string{"If-None-Match": etag})
+		// Then
+		require.Equal(t, http.StatusOK, first.Code)
+		require.True(t, strings.HasPrefix(etag, `"1-`))
+		require.Equal(t, http.StatusNotModified, same.Code)
+		require.Equal(t, etag, same.Header().Get("ETag"))
+		require.Empty(t, same.Body.String())
+		require.Equal(t, http.StatusOK, other.Code)
+		require.NotEqual(t, etag, other.Header().Get("ETag"))
+		require.Equal(t, http.StatusOK, changed.Code)
+		require.True(t, strings.HasPrefix(changed.Header().Get("ETag"), `"2-`))
+		require.Equal(t, 3, h.calls)
+	})
+
+	t.Run("Respond 304 with the Vary and the validators of the kept response", func(t *testing.T) {
+		// Given
+		var version uint64 = 1
+		lru := cache.NewLRU(10)
+		hd := cache.Middleware(func() uint64 { return version }, lru)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Vary", "Accept, Accept-Encoding")
+			w.Write([]byte("vehicles"))
+		}))
+		etag := serve(hd, http.MethodGet, "/vehicles", nil).Header().Get("ETag")
+		hdNoCache := cache.Middleware(func() uint64 { return version }, nil)(&countingHandler{version: &version})
+		etagNoCache := serve(hdNoCache, http.MethodGet, "/vehicles", nil).Header().Get("ETag")
+
+		// When
+		cached := serve(hd, http.MethodGet, "/vehicles", map[string]string{"If-None-Match": etag})
+		uncached := serve(hdNoCache, http.MethodGet, "/vehicles", map[string]string{"If-None-Match": etagNoCache})
+		// Then
+		require.Equal(t, http.StatusNotModified, cached.Code)
+		require.Equal(t, http.Header{
+			"Cache-Control": {"no-cache"},
+			"Vary":          {"Accept, Accept-Encoding"},
+			"Etag":          {etag},
+		}, cached.Header())
+		require.Equal(t, http.StatusNotModified, uncached.Code)
+		require.Equal(t, []string{"Accept"}, uncached.Header().Values("Vary"))
+	})
+
+	t.Run("Tag the representations of each Accept apart", func(t *testing.T) {
+		// Given
+		var version uint64 = 1
+		hd := cache.Middleware(func() uint64 { return version }, nil)(&countingHandler{version: &version})
+
+		// When
+		json := serve(hd, http.MethodGet, "/vehicles", map[string]string{"Accept": "application/json"})
+		csv := serve(hd, http.MethodGet, "/vehicles", map[string]string{"Accept": "text/csv"})
+		// Then
+		require.NotEqual(t, json.Header().Get("ETag"), csv.Header().Get("ETag"))
+	})
+
+	t.Run("Serve the responses from the cache until the version changes", func(t *testing.T) {
+		// Given
+		var version uint64 = 1
+		h := &countingHandler{version: &version}
+		lru := cache.NewLRU(10)
+		hd := cache.Middleware(func() uint64 { return version }, lru)(h)
+		first := serve(hd, http.MethodGet, "/vehicles", nil)
+
+		// When
+		cached := serve(hd, http.MethodGet, "/vehicles", nil)
+		version = 2
+		changed := serve(hd, http.MethodGet, "/vehicles", nil)
+		// Then
+		require.Equal(t, 2, h.calls)
+		require.Equal(t, http.StatusOK, cached.Code)
+		require.Equal(t, first.Body.String(), cached.Body.String())
+		require.Equal(t, first.Header(), cached.Header())
+		require.Equal(t, "version 2", changed.Body.String())
+		require.Equal(t, 1, lru.Len())
+	})
+
+	t.Run("Keep only the headers set by the handler", func(t *testing.T) {
+		// Given
+		var version uint64 = 1
+		lru := cache.NewLRU(10)
+		hd := cache.Middleware(func() uint64 { return version }, lru)(&countingHandler{version: &version})
+		outer := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-Id", r.Header.Get("X-Request-Id"))
+			hd.ServeHTTP(w, r)
+		})
+		serve(outer, http.MethodGet, "/vehicles", map[string]string{"X-Request-Id": "a"})
+
+		// When
+		res := serve(outer, http.MethodGet, "/vehicles", map[string]string{"X-Request-Id": "b"})
+		// Then
+		require.Equal(t, "b", res.Header().Get("X-Request-Id"))
+		require.Equal(t, "1", res.Header().Get("X-Total-Count"))
+		require.NotEmpty(t, res.Header().Get("ETag"))
+	})
+
+	t.Run("Do not tag nor keep errors and writes", func(t *testing.T) {
+		// Given
+		var version uint64 = 1
+		h := &countingHandler{version: &version, status: http.StatusNotFound}
+		lru := cache.NewLRU(10)
+		hd := cache.Middleware(func() uint64 { return version }, lru)(h)
+
+		// When
+		notFound := serve(hd, http.MethodGet, "/vehicles/1", nil)
+		serve(hd, http.MethodGet, "/vehicles/1", nil)
+		h.status = http.StatusCreated
+		created := serve(hd, http.MethodPost, "/vehicles", nil)
+		// Then
+		require.Equal(t, http.StatusNotFound, notFound.Code)
+		require.Empty(t, notFound.Header().Get("ETag"))
+		require.Empty(t, created.Header().Get("ETag"))
+		require.Equal(t, 3, h.calls)
+		require.Equal(t, 0, lru.Len())
+	})
+}
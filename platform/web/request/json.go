@@ -26,7 +26,7 @@ func JSON(r *http.Request, ptr any) (err error) {
 	// get body
 	err = json.NewDecoder(r.Body).Decode(ptr)
 	if err != nil {
-		err = fmt.Errorf("%w. %v", ErrRequestJSONInvalid, err)
+		err = fmt.Errorf("%w. %w", ErrRequestJSONInvalid, err)
 		return
 	}
 
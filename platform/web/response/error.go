@@ -1,8 +1,10 @@
 package response
 
 import (
+	"app/platform/logging"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 )
 
@@ -31,12 +33,23 @@ func Error(w http.ResponseWriter, statusCode int, message string) {
 	}
 
 	// write response
-	w.WriteHeader(defaultStatusCode)
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(defaultStatusCode)
 	w.Write(bytes)
 }
 
 func Errorf(w http.ResponseWriter, statusCode int, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	Error(w, statusCode, message)
+}
+
+// InternalError writes an internal error response, logging the error that caused it with the logger of the request
+// - the error is not written in the response, so that internal details are not exposed
+func InternalError(w http.ResponseWriter, r *http.Request, err error) {
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelError, "internal error",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("error", err.Error()),
+	)
+	Problem(w, r, http.StatusInternalServerError, "internal error")
 }
\ No newline at end of file
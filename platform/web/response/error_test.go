@@ -0,0 +1,42 @@
+package response_test
+
+import (
+	"app/platform/logging"
+	"app/platform/web/response"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for InternalError function
+func TestInternalError(t *testing.T) {
+	t.Run("log the cause with the logger of the request", func(t *testing.T) {
+		// arrange
+		var b bytes.Buffer
+		logger := logging.New(&b, slog.LevelInfo).With("request_id", "abc-123")
+		req := httptest.NewRequest(http.MethodGet, "/vehicles", nil)
+		req = req.WithContext(logging.WithLogger(req.Context(), logger))
+
+		// act
+		rr := httptest.NewRecorder()
+		response.InternalError(rr, req, errors.New("disk full"))
+
+		// assert
+		expectedCode := http.StatusInternalServerError
+		expectedBody := `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal error","instance":"/vehicles"}`
+		require.Equal(t, expectedCode, rr.Code)
+		require.Equal(t, response.ProblemContentType, rr.Header().Get("Content-Type"))
+		require.JSONEq(t, expectedBody, rr.Body.String())
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(b.Bytes(), &record))
+		require.Equal(t, "internal error", record["msg"])
+		require.Equal(t, "disk full", record["error"])
+		require.Equal(t, "abc-123", record["request_id"])
+	})
+}
@@ -0,0 +1,72 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Negotiate returns the offered media type that the request accepts the most, by its Accept header
+// - offers are in order of preference, the first one is returned when the request has no Accept header
+// - each offer takes the quality of the most specific range that matches it: type/subtype, type/* or */*
+// - it returns "" when the request accepts none of the offers
+func Negotiate(r *http.Request, offers ...string) (mediaType string) {
+	accept := r.Header.Values("Accept")
+	if len(accept) == 0 || len(offers) == 0 {
+		if len(offers) > 0 {
+			mediaType = offers[0]
+		}
+		return
+	}
+
+	// accepted ranges with their quality
+	type acceptRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []acceptRange
+	for _, value := range accept {
+		for _, part := range strings.Split(value, ",") {
+			mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if value, ok := params["q"]; ok {
+				q, err = strconv.ParseFloat(value, 64)
+				if err != nil {
+					continue
+				}
+			}
+			ranges = append(ranges, acceptRange{mediaType: mt, quality: q})
+		}
+	}
+
+	// best offer
+	best := 0.0
+	for _, offer := range offers {
+		typ, _, _ := strings.Cut(offer, "/")
+		specificity, quality := 0, 0.0
+		for _, ar := range ranges {
+			var s int
+			switch ar.mediaType {
+			case offer:
+				s = 3
+			case typ + "/*":
+				s = 2
+			case "*/*":
+				s = 1
+			default:
+				continue
+			}
+			if s > specificity {
+				specificity, quality = s, ar.quality
+			}
+		}
+		if quality > best {
+			best, mediaType = quality, offer
+		}
+	}
+	return
+}
@@ -0,0 +1,47 @@
+package response_test
+
+import (
+	"app/platform/web/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Negotiate function
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "text/csv", "application/x-ndjson"}
+
+	cases := map[string]struct {
+		accept   []string
+		expected string
+	}{
+		"no accept header":             {accept: nil, expected: "application/json"},
+		"any media type":               {accept: []string{"*/*"}, expected: "application/json"},
+		"exact media type":             {accept: []string{"text/csv"}, expected: "text/csv"},
+		"media type with parameters":   {accept: []string{"text/csv; charset=utf-8"}, expected: "text/csv"},
+		"highest quality":              {accept: []string{"application/json;q=0.5, application/x-ndjson"}, expected: "application/x-ndjson"},
+		"specific range over wildcard": {accept: []string{"*/*;q=0.9, application/json;q=0.1"}, expected: "text/csv"},
+		"type wildcard":                {accept: []string{"text/*"}, expected: "text/csv"},
+		"several headers":              {accept: []string{"text/html", "application/x-ndjson"}, expected: "application/x-ndjson"},
+		"excluded media type":          {accept: []string{"text/csv;q=0"}, expected: ""},
+		"none acceptable":              {accept: []string{"application/xml"}, expected: ""},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			// arrange
+			req := httptest.NewRequest(http.MethodGet, "/vehicles", nil)
+			for _, value := range c.accept {
+				req.Header.Add("Accept", value)
+			}
+
+			// act
+			result := response.Negotiate(req, offers...)
+
+			// assert
+			require.Equal(t, c.expected, result)
+		})
+	}
+}
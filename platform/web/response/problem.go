@@ -0,0 +1,76 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemContentType is the content type of the problem details of RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// FieldError is a struct that represents why the value of a field of the request is invalid
+type FieldError struct {
+	// Field is the name of the field, query parameter or path parameter
+	Field string `json:"field"`
+	// Message is the description of the error
+	Message string `json:"message"`
+}
+
+// ProblemDetails is a struct that represents an error response as described by RFC 7807
+type ProblemDetails struct {
+	// Type is a URI that identifies the type of problem, "about:blank" when the status is enough
+	Type string `json:"type"`
+	// Title is a short summary of the type of problem
+	Title string `json:"title"`
+	// Status is the HTTP status code
+	Status int `json:"status"`
+	// Detail is an explanation of this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// Instance is a URI that identifies this occurrence of the problem
+	Instance string `json:"instance,omitempty"`
+	// Errors are the invalid fields of the request, for validation failures
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// Problem writes a problem details response for the request
+// - type is about:blank and title is the text of the status code
+// - instance is the path of the request
+func Problem(w http.ResponseWriter, r *http.Request, statusCode int, detail string, errs ...FieldError) {
+	WriteProblem(w, ProblemDetails{
+		Status:   statusCode,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Errors:   errs,
+	})
+}
+
+// WriteProblem writes problem details response
+// - status defaults to 500 if it is not an error status code, type to about:blank and title to the text of the status code
+func WriteProblem(w http.ResponseWriter, p ProblemDetails) {
+	// default values
+	if p.Status < 400 || p.Status > 599 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	// marshal body
+	bytes, err := json.Marshal(p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// set header
+	w.Header().Set("Content-Type", ProblemContentType)
+
+	// set status code
+	w.WriteHeader(p.Status)
+
+	// write body
+	w.Write(bytes)
+}
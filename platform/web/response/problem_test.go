@@ -0,0 +1,64 @@
+package response_test
+
+import (
+	"app/platform/web/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Problem function
+func TestProblem(t *testing.T) {
+	t.Run("validation failure", func(t *testing.T) {
+		// arrange
+		req := httptest.NewRequest(http.MethodGet, "/vehicles?limit=0", nil)
+
+		// act
+		rr := httptest.NewRecorder()
+		response.Problem(rr, req, http.StatusBadRequest, "invalid limit", response.FieldError{Field: "limit", Message: "must be an integer between 1 and 1000"})
+
+		// assert
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
+		expectedCode := http.StatusBadRequest
+		expectedBody := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid limit","instance":"/vehicles","errors":[{"field":"limit","message":"must be an integer between 1 and 1000"}]}`
+		require.Equal(t, expectedHeader, rr.Header())
+		require.Equal(t, expectedCode, rr.Code)
+		require.JSONEq(t, expectedBody, rr.Body.String())
+	})
+}
+
+// Tests for WriteProblem function
+func TestWriteProblem(t *testing.T) {
+	t.Run("custom type", func(t *testing.T) {
+		// arrange
+		p := response.ProblemDetails{
+			Type:   "https://example.com/problems/conflict",
+			Title:  "Vehicle already exists",
+			Status: http.StatusConflict,
+		}
+
+		// act
+		rr := httptest.NewRecorder()
+		response.WriteProblem(rr, p)
+
+		// assert
+		expectedCode := http.StatusConflict
+		expectedBody := `{"type":"https://example.com/problems/conflict","title":"Vehicle already exists","status":409}`
+		require.Equal(t, expectedCode, rr.Code)
+		require.JSONEq(t, expectedBody, rr.Body.String())
+	})
+
+	t.Run("invalid status code", func(t *testing.T) {
+		// act
+		rr := httptest.NewRecorder()
+		response.WriteProblem(rr, response.ProblemDetails{Status: http.StatusOK})
+
+		// assert
+		expectedCode := http.StatusInternalServerError
+		expectedBody := `{"type":"about:blank","title":"Internal Server Error","status":500}`
+		require.Equal(t, expectedCode, rr.Code)
+		require.JSONEq(t, expectedBody, rr.Body.String())
+	})
+}
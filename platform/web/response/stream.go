@@ -0,0 +1,121 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// CSVContentType is the content type of a CSV response with header
+	CSVContentType = "text/csv; charset=utf-8"
+	// NDJSONContentType is the content type of a response of newline delimited JSON values
+	NDJSONContentType = "application/x-ndjson"
+)
+
+// StreamFlushRecords is the number of records written between flushes of a stream to the client
+const StreamFlushRecords = 100
+
+// CSVStream is a struct that writes a CSV response one record at a time
+// - records are flushed to the client every StreamFlushRecords, so the response is never held in memory
+type CSVStream struct {
+	// w is the response writer
+	w http.ResponseWriter
+	// cw is the CSV writer over the response
+	cw *csv.Writer
+	// n is the number of records written since the last flush
+	n int
+}
+
+// NewCSVStream writes the headers of a CSV response and its header record, and returns the stream of its records
+func NewCSVStream(w http.ResponseWriter, code int, header []string) (s *CSVStream, err error) {
+	// set header
+	w.Header().Set("Content-Type", CSVContentType)
+
+	// set status code
+	w.WriteHeader(code)
+
+	// write header record
+	s = &CSVStream{w: w, cw: csv.NewWriter(w)}
+	err = s.cw.Write(header)
+	return
+}
+
+// Write is a method that writes a record
+func (s *CSVStream) Write(record []string) (err error) {
+	err = s.cw.Write(record)
+	if err != nil {
+		return
+	}
+
+	s.n++
+	if s.n == StreamFlushRecords {
+		err = s.Flush()
+	}
+	return
+}
+
+// Flush is a method that sends the records written to the client
+func (s *CSVStream) Flush() (err error) {
+	s.n = 0
+	s.cw.Flush()
+	err = s.cw.Error()
+	if err != nil {
+		return
+	}
+	err = flush(s.w)
+	return
+}
+
+// NDJSONStream is a struct that writes a response of newline delimited JSON values one value at a time
+// - values are flushed to the client every StreamFlushRecords, so the response is never held in memory
+type NDJSONStream struct {
+	// w is the response writer
+	w http.ResponseWriter
+	// enc is the JSON encoder over the response, it ends each value with a newline
+	enc *json.Encoder
+	// n is the number of values written since the last flush
+	n int
+}
+
+// NewNDJSONStream writes the headers of a newline delimited JSON response, and returns the stream of its values
+func NewNDJSONStream(w http.ResponseWriter, code int) (s *NDJSONStream) {
+	// set header
+	w.Header().Set("Content-Type", NDJSONContentType)
+
+	// set status code
+	w.WriteHeader(code)
+
+	s = &NDJSONStream{w: w, enc: json.NewEncoder(w)}
+	return
+}
+
+// Encode is a method that writes a value in a line
+func (s *NDJSONStream) Encode(v any) (err error) {
+	err = s.enc.Encode(v)
+	if err != nil {
+		return
+	}
+
+	s.n++
+	if s.n == StreamFlushRecords {
+		err = s.Flush()
+	}
+	return
+}
+
+// Flush is a method that sends the values written to the client
+func (s *NDJSONStream) Flush() (err error) {
+	s.n = 0
+	err = flush(s.w)
+	return
+}
+
+// flush is a function that sends the buffered response to the client, if the writer supports it
+func flush(w http.ResponseWriter) (err error) {
+	err = http.NewResponseController(w).Flush()
+	if err == http.ErrNotSupported {
+		err = nil
+	}
+	return
+}
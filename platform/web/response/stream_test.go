@@ -0,0 +1,73 @@
+package response_test
+
+import (
+	"app/platform/web/response"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for CSVStream
+func TestCSVStream(t *testing.T) {
+	t.Run("header and records", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		s, err := response.NewCSVStream(rr, http.StatusOK, []string{"id", "brand"})
+		require.NoError(t, err)
+		require.NoError(t, s.Write([]string{"1", "Ford"}))
+		require.NoError(t, s.Write([]string{"2", "Fiat, Spa"}))
+		require.NoError(t, s.Flush())
+
+		// assert
+		expectedHeader := http.Header{"Content-Type": []string{"text/csv; charset=utf-8"}}
+		expectedCode := http.StatusOK
+		expectedBody := "id,brand\n1,Ford\n2,\"Fiat, Spa\"\n"
+		require.Equal(t, expectedHeader, rr.Header())
+		require.Equal(t, expectedCode, rr.Code)
+		require.Equal(t, expectedBody, rr.Body.String())
+	})
+
+	t.Run("records are flushed while written", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		s, err := response.NewCSVStream(rr, http.StatusOK, []string{"id"})
+		require.NoError(t, err)
+		for i := 0; i < response.StreamFlushRecords; i++ {
+			require.NoError(t, s.Write([]string{strconv.Itoa(i)}))
+		}
+
+		// assert
+		require.True(t, rr.Flushed)
+		require.Equal(t, response.StreamFlushRecords+1, strings.Count(rr.Body.String(), "\n"))
+	})
+}
+
+// Tests for NDJSONStream
+func TestNDJSONStream(t *testing.T) {
+	t.Run("a value per line", func(t *testing.T) {
+		// arrange
+		rr := httptest.NewRecorder()
+
+		// act
+		s := response.NewNDJSONStream(rr, http.StatusOK)
+		require.NoError(t, s.Encode(map[string]int{"id": 1}))
+		require.NoError(t, s.Encode(map[string]int{"id": 2}))
+		require.NoError(t, s.Flush())
+
+		// assert
+		expectedHeader := http.Header{"Content-Type": []string{"application/x-ndjson"}}
+		expectedCode := http.StatusOK
+		expectedBody := "{\"id\":1}\n{\"id\":2}\n"
+		require.Equal(t, expectedHeader, rr.Header())
+		require.Equal(t, expectedCode, rr.Code)
+		require.Equal(t, expectedBody, rr.Body.String())
+	})
+}